@@ -9,6 +9,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -30,6 +31,11 @@ import (
 
 	"github.com/openshift/origin/pkg/monitor"
 	"github.com/openshift/origin/pkg/monitor/resourcewatch/cmd"
+	"github.com/openshift/origin/pkg/synthetictests"
+	"github.com/openshift/origin/pkg/synthetictests/allowedalerts"
+	"github.com/openshift/origin/pkg/synthetictests/allowedbackenddisruption"
+	"github.com/openshift/origin/pkg/synthetictests/historicaldata"
+	"github.com/openshift/origin/pkg/synthetictests/historicaldata/historicaldata_cmd"
 	testginkgo "github.com/openshift/origin/pkg/test/ginkgo"
 	"github.com/openshift/origin/pkg/version"
 	exutil "github.com/openshift/origin/test/extended/util"
@@ -82,6 +88,16 @@ func main() {
 			Out:    os.Stdout,
 			ErrOut: os.Stderr,
 		}),
+		monitor_cmd.NewAlertTestsCommand(genericclioptions.IOStreams{
+			In:     os.Stdin,
+			Out:    os.Stdout,
+			ErrOut: os.Stderr,
+		}),
+		historicaldata_cmd.NewHistoricalDataCommand(genericclioptions.IOStreams{
+			In:     os.Stdin,
+			Out:    os.Stdout,
+			ErrOut: os.Stderr,
+		}),
 	)
 
 	f := flag.CommandLine.Lookup("v")
@@ -215,6 +231,15 @@ type runOptions struct {
 	FromRepository string
 	Provider       string
 
+	// HistoricalDataDir holds historical data that takes precedence over the data embedded in the binary.
+	HistoricalDataDir string
+	// DiscoverAlertTests tests every alerting rule in the cluster, not only the alerts with dedicated tests.
+	DiscoverAlertTests bool
+	// MetricThresholdsFile replaces the default metric thresholds recorded as intervals.
+	MetricThresholdsFile string
+	// CertificateExpiryWindow is how long after the run every certificate in openshift namespaces must stay valid.
+	CertificateExpiryWindow time.Duration
+
 	// Passed to the test process if set
 	UpgradeSuite string
 	ToImage      string
@@ -238,6 +263,8 @@ func (opt *runOptions) AsEnv() []string {
 	args = append(args, fmt.Sprintf("KUBE_TEST_REPO=%s", opt.FromRepository))
 	args = append(args, fmt.Sprintf("TEST_PROVIDER=%s", opt.Provider))
 	args = append(args, fmt.Sprintf("TEST_JUNIT_DIR=%s", opt.JUnitDir))
+	args = append(args, fmt.Sprintf("%s=%s", historicaldata.HistoricalDataDirEnvVar, opt.HistoricalDataDir))
+	args = append(args, fmt.Sprintf("%s=%t", allowedalerts.DiscoverAlertTestsEnvVar, opt.DiscoverAlertTests))
 	for i := 10; i > 0; i-- {
 		if klog.V(klog.Level(i)).Enabled() {
 			args = append(args, fmt.Sprintf("TEST_LOG_LEVEL=%d", i))
@@ -262,6 +289,41 @@ func (opt *runOptions) AsEnv() []string {
 	return args
 }
 
+// loadHistoricalData uses the historical data directory for the tests run in this process, and fails early when the
+// data in it cannot be read.  The processes running the individual tests get the directory from AsEnv.
+func (opt *runOptions) loadHistoricalData() error {
+	if len(opt.HistoricalDataDir) == 0 {
+		return nil
+	}
+	historicaldata.SetHistoricalDataDir(opt.HistoricalDataDir)
+	messages, err := historicaldata.ValidateOverrides(map[string]string{
+		allowedalerts.HistoricalDataType:            allowedalerts.HistoricalDataNameField,
+		allowedbackenddisruption.HistoricalDataType: allowedbackenddisruption.HistoricalDataNameField,
+	})
+	if err != nil {
+		return err
+	}
+	if len(messages) == 0 {
+		fmt.Fprintf(opt.ErrOut, "warning: no historical data found in %s, using the embedded data\n", opt.HistoricalDataDir)
+	}
+	for _, message := range messages {
+		fmt.Fprintf(opt.ErrOut, "historical data: %s\n", message)
+	}
+	return nil
+}
+
+func (opt *runOptions) loadMetricThresholds() error {
+	if len(opt.MetricThresholdsFile) == 0 {
+		return nil
+	}
+	thresholds, err := monitor.LoadMetricThresholds(opt.MetricThresholdsFile)
+	if err != nil {
+		return err
+	}
+	opt.MonitorEventsOptions.MetricThresholds = thresholds
+	return nil
+}
+
 func (opt *runOptions) SelectSuite(suites testSuites, args []string) (*testSuite, error) {
 	suite, err := opt.Options.SelectSuite(suites.TestSuites(), args)
 	if err != nil {
@@ -305,6 +367,14 @@ func newRunCommand() *cobra.Command {
 					return err
 				}
 				opt.SyntheticEventTests = pulledInvalidImages(opt.FromRepository)
+				if err := opt.loadHistoricalData(); err != nil {
+					return err
+				}
+				allowedalerts.SetDiscoverAlertTests(opt.DiscoverAlertTests)
+				synthetictests.SetCertificateExpiryWindow(opt.CertificateExpiryWindow)
+				if err := opt.loadMetricThresholds(); err != nil {
+					return err
+				}
 
 				suite, err := opt.SelectSuite(staticSuites, args)
 				if err != nil {
@@ -370,6 +440,14 @@ func newRunUpgradeCommand() *cobra.Command {
 					return err
 				}
 				opt.SyntheticEventTests = pulledInvalidImages(opt.FromRepository)
+				if err := opt.loadHistoricalData(); err != nil {
+					return err
+				}
+				allowedalerts.SetDiscoverAlertTests(opt.DiscoverAlertTests)
+				synthetictests.SetCertificateExpiryWindow(opt.CertificateExpiryWindow)
+				if err := opt.loadMetricThresholds(); err != nil {
+					return err
+				}
 
 				suite, err := opt.SelectSuite(upgradeSuites, args)
 				if err != nil {
@@ -482,6 +560,11 @@ func mirrorToFile(opt *testginkgo.Options, fn func() error) error {
 func bindOptions(opt *runOptions, flags *pflag.FlagSet) {
 	flags.StringVar(&opt.FromRepository, "from-repository", opt.FromRepository, "A container image repository to retrieve test images from.")
 	flags.StringVar(&opt.Provider, "provider", opt.Provider, "The cluster infrastructure provider. Will automatically default to the correct value.")
+	flags.StringVar(&opt.HistoricalDataDir, "historical-data-dir", os.Getenv(historicaldata.HistoricalDataDirEnvVar), fmt.Sprintf("A directory of historical data, like <dir>/%s.json and <dir>/%s/*.json, that takes precedence over the embedded data. Defaults to $%s.", allowedalerts.HistoricalDataType, allowedbackenddisruption.HistoricalDataType, historicaldata.HistoricalDataDirEnvVar))
+	discoverAlertTests, _ := strconv.ParseBool(os.Getenv(allowedalerts.DiscoverAlertTestsEnvVar))
+	flags.BoolVar(&opt.DiscoverAlertTests, "discover-alert-tests", discoverAlertTests, fmt.Sprintf("Test every alerting rule in the cluster, using the severity and the namespace of its PrometheusRule to pick defaults. Defaults to $%s.", allowedalerts.DiscoverAlertTestsEnvVar))
+	flags.StringVar(&opt.MetricThresholdsFile, "metric-thresholds", opt.MetricThresholdsFile, "A JSON list of {name, query, threshold, level} replacing the default PromQL queries whose threshold crossings are recorded as intervals. An empty list records none.")
+	flags.DurationVar(&opt.CertificateExpiryWindow, "certificate-expiry-window", synthetictests.CertificateExpiryWindow(), fmt.Sprintf("How long after the run every certificate in openshift namespaces must stay valid. Defaults to $%s or 24h.", synthetictests.CertificateExpiryWindowEnvVar))
 	bindTestOptions(opt.Options, flags)
 }
 
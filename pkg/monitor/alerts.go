@@ -117,9 +117,15 @@ func FetchEventIntervalsForAllAlerts(ctx context.Context, restConfig *rest.Confi
 		return nil, fmt.Errorf("Thanos queriers not connected to all Prometheus sidecars: %w", err)
 	}
 
+	return FetchEventIntervalsForAllAlertsFromPrometheus(ctx, prometheusClient, startTime, time.Now())
+}
+
+// FetchEventIntervalsForAllAlertsFromPrometheus creates the alert intervals from any Prometheus, including one that
+// is not reached through the cluster.
+func FetchEventIntervalsForAllAlertsFromPrometheus(ctx context.Context, prometheusClient prometheusv1.API, startTime, endTime time.Time) ([]monitorapi.EventInterval, error) {
 	timeRange := prometheusv1.Range{
 		Start: startTime,
-		End:   time.Now(),
+		End:   endTime,
 		Step:  2 * time.Second,
 	}
 	alerts, warningsForQuery, err := prometheusClient.QueryRange(ctx, `ALERTS{alertstate="firing"}`, timeRange)
@@ -147,6 +153,10 @@ func FetchEventIntervalsForAllAlerts(ctx context.Context, restConfig *rest.Confi
 		return nil, err
 	}
 
+	return combineFiringAndPendingAlerts(firingAlerts, pendingAlerts), nil
+}
+
+func combineFiringAndPendingAlerts(firingAlerts, pendingAlerts []monitorapi.EventInterval) []monitorapi.EventInterval {
 	// firing alerts trump pending alerts, so if the alerts will overlap when we render, then we want to have pending
 	// broken up by firing, so the alert should not be listed as pending at the same time as it is firing in our intervals.
 	pendingAlerts = blackoutEvents(pendingAlerts, firingAlerts)
@@ -155,7 +165,7 @@ func FetchEventIntervalsForAllAlerts(ctx context.Context, restConfig *rest.Confi
 	ret = append(ret, firingAlerts...)
 	ret = append(ret, pendingAlerts...)
 
-	return ret, nil
+	return ret
 }
 
 // blackoutEvents filters startingEvents and rewrites into potentially multiple events to avoid overlap with the blackoutWindows.
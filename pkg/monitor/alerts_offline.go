@@ -0,0 +1,141 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	prometheusapi "github.com/prometheus/client_golang/api"
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	prometheustypes "github.com/prometheus/common/model"
+)
+
+// NewPrometheusClientForURL returns a client for a Prometheus reachable without the cluster.
+func NewPrometheusClientForURL(prometheusURL string) (prometheusv1.API, error) {
+	client, err := prometheusapi.NewClient(prometheusapi.Config{Address: prometheusURL})
+	if err != nil {
+		return nil, err
+	}
+	return prometheusv1.NewAPI(client), nil
+}
+
+// EventIntervalsForAlertsFromFiles creates the same alert intervals as FetchEventIntervalsForAllAlerts from ALERTS
+// series exported with a range query.  Every file may hold the response of /api/v1/query_range, only its data, or
+// only the list of series.  A directory is read as all of the .json files in it.  The range queries must use a step
+// of less than five seconds, so that an alert is not broken up between samples.
+func EventIntervalsForAlertsFromFiles(ctx context.Context, filenames ...string) ([]monitorapi.EventInterval, error) {
+	files := []string{}
+	for _, filename := range filenames {
+		info, err := os.Stat(filename)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, filename)
+			continue
+		}
+		dirFiles, err := filepath.Glob(filepath.Join(filename, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(dirFiles)
+		files = append(files, dirFiles...)
+	}
+
+	firing, pending := prometheustypes.Matrix{}, prometheustypes.Matrix{}
+	for _, file := range files {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		series, err := alertSeriesFromJSON(content)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read alerts from %v: %w", file, err)
+		}
+		for _, curr := range series {
+			switch curr.Metric["alertstate"] {
+			case "firing":
+				firing = append(firing, curr)
+			case "pending":
+				pending = append(pending, curr)
+			}
+		}
+	}
+
+	firingAlerts, err := CreateEventIntervalsForAlerts(ctx, firing, startOfSeries(firing))
+	if err != nil {
+		return nil, err
+	}
+	pendingAlerts, err := CreateEventIntervalsForAlerts(ctx, pending, startOfSeries(pending))
+	if err != nil {
+		return nil, err
+	}
+	return combineFiringAndPendingAlerts(firingAlerts, pendingAlerts), nil
+}
+
+// alertSeriesFromJSON reads the ALERTS series from the formats a range query is usually exported in.  Series of other
+// metrics are skipped.
+func alertSeriesFromJSON(content []byte) (prometheustypes.Matrix, error) {
+	type queryData struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	}
+	type queryResponse struct {
+		Status string     `json:"status"`
+		Data   *queryData `json:"data"`
+	}
+
+	var result json.RawMessage
+	response := &queryResponse{}
+	data := &queryData{}
+	switch {
+	case json.Unmarshal(content, response) == nil && response.Data != nil:
+		if len(response.Status) > 0 && response.Status != "success" {
+			return nil, fmt.Errorf("query status was %q", response.Status)
+		}
+		data = response.Data
+		result = data.Result
+	case json.Unmarshal(content, data) == nil && len(data.Result) > 0:
+		result = data.Result
+	default:
+		result = content
+	}
+	if len(data.ResultType) > 0 && data.ResultType != prometheustypes.ValMatrix.String() {
+		return nil, fmt.Errorf("expected a range query result, got %q", data.ResultType)
+	}
+
+	matrix := prometheustypes.Matrix{}
+	if err := json.Unmarshal(result, &matrix); err != nil {
+		return nil, err
+	}
+	ret := prometheustypes.Matrix{}
+	for _, series := range matrix {
+		if name, ok := series.Metric[prometheustypes.MetricNameLabel]; ok && name != "ALERTS" {
+			continue
+		}
+		if len(series.Metric[prometheustypes.AlertNameLabel]) == 0 {
+			continue
+		}
+		ret = append(ret, series)
+	}
+	return ret, nil
+}
+
+func startOfSeries(matrix prometheustypes.Matrix) time.Time {
+	ret := time.Time{}
+	for _, series := range matrix {
+		if len(series.Values) == 0 {
+			continue
+		}
+		if start := series.Values[0].Timestamp.Time(); ret.IsZero() || start.Before(ret) {
+			ret = start
+		}
+	}
+	return ret
+}
@@ -0,0 +1,76 @@
+package monitor
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEventIntervalsForAlertsFromFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "alerts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// etcdNoLeader is pending for 4s, then firing for 4s
+	queryRangeResponse := `{
+  "status": "success",
+  "data": {
+    "resultType": "matrix",
+    "result": [
+      {
+        "metric": {"__name__": "ALERTS", "alertname": "etcdNoLeader", "alertstate": "pending", "namespace": "openshift-etcd", "severity": "critical"},
+        "values": [[1657040400, "1"], [1657040402, "1"], [1657040404, "1"]]
+      },
+      {
+        "metric": {"__name__": "ALERTS", "alertname": "etcdNoLeader", "alertstate": "firing", "namespace": "openshift-etcd", "severity": "critical"},
+        "values": [[1657040404, "1"], [1657040406, "1"], [1657040408, "1"]]
+      },
+      {
+        "metric": {"__name__": "up", "job": "etcd"},
+        "values": [[1657040400, "1"]]
+      }
+    ]
+  }
+}`
+	// only the result, the way the series are copied out of the console
+	queryRangeResult := `[
+  {
+    "metric": {"__name__": "ALERTS", "alertname": "Watchdog", "alertstate": "firing", "namespace": "openshift-monitoring", "severity": "none"},
+    "values": [[1657040400, "1"], [1657040402, "1"]]
+  }
+]`
+	if err := ioutil.WriteFile(filepath.Join(dir, "etcd.json"), []byte(queryRangeResponse), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "watchdog.json"), []byte(queryRangeResult), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	intervals, err := EventIntervalsForAlertsFromFiles(context.TODO(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Unix(1657040400, 0)
+	want := []struct {
+		locator  string
+		from, to time.Time
+	}{
+		{locator: "alert/etcdNoLeader ns/openshift-etcd", from: start.Add(4 * time.Second), to: start.Add(8 * time.Second)},
+		{locator: "alert/Watchdog ns/openshift-monitoring", from: start, to: start.Add(2 * time.Second)},
+		{locator: "alert/etcdNoLeader ns/openshift-etcd", from: start, to: start.Add(4 * time.Second)},
+	}
+	if len(intervals) != len(want) {
+		t.Fatalf("expected %d intervals, got %v", len(want), intervals)
+	}
+	for i := range want {
+		if intervals[i].Locator != want[i].locator || !intervals[i].From.Equal(want[i].from) || !intervals[i].To.Equal(want[i].to) {
+			t.Errorf("expected %v from %v to %v, got %v", want[i].locator, want[i].from, want[i].to, intervals[i])
+		}
+	}
+}
@@ -13,6 +13,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
@@ -41,6 +42,10 @@ func Start(ctx context.Context, restConfig *rest.Config, additionalEventInterval
 	if err != nil {
 		return nil, err
 	}
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
 
 	for _, additionalEventIntervalRecorder := range additionalEventIntervalRecorders {
 		if err := additionalEventIntervalRecorder(ctx, m, restConfig); err != nil {
@@ -52,8 +57,17 @@ func Start(ctx context.Context, restConfig *rest.Config, additionalEventInterval
 	startNodeMonitoring(ctx, m, client)
 	startEventMonitoring(ctx, m, client)
 
+	// the monitors of openshift-* namespaces share the informers of each namespace
+	namespaceInformers := newOpenShiftNamespaceInformers(m, client)
+	startLeaseMonitoring(ctx, m, client, namespaceInformers)
+	startPodDisruptionBudgetMonitoring(m, namespaceInformers)
+	startConfigMapMonitoring(m, namespaceInformers)
+	startCertificateMonitoring(m, namespaceInformers)
+	namespaceInformers.Start(ctx)
+
 	// add interval creation at the same point where we add the monitors
 	startClusterOperatorMonitoring(ctx, m, configClient)
+	startMachineConfigPoolMonitoring(ctx, m, dynamicClient)
 
 	m.StartSampling(ctx)
 	return m, nil
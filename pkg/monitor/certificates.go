@@ -0,0 +1,217 @@
+package monitor
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+	certutil "k8s.io/client-go/util/cert"
+)
+
+const (
+	// caBundleKey is where the CA bundles published by the platform keep their certificates.
+	caBundleKey = "ca-bundle.crt"
+)
+
+// startCertificateMonitoring records the certificates in TLS secrets and the CA bundles in configmaps in openshift-*
+// namespaces, and every time they are rotated.  The informers drop everything but the certificates before the objects
+// are cached, and the private keys are never kept.
+func startCertificateMonitoring(m Recorder, namespaceInformers *openshiftNamespaceInformers) {
+	namespaceInformers.AddHandler(func(namespace string, factory informers.SharedInformerFactory) {
+		namespaceInformers.TLSSecretInformer(namespace, factory).AddEventHandler(
+			cache.ResourceEventHandlerFuncs{
+				AddFunc: func(obj interface{}) {
+					secret, ok := obj.(*corev1.Secret)
+					if !ok {
+						return
+					}
+					m.Record(certificateConditions(monitorapi.SecretLocator(secret.Namespace, secret.Name), nil, secret.Data[corev1.TLSCertKey])...)
+				},
+				UpdateFunc: func(old, obj interface{}) {
+					secret, ok := obj.(*corev1.Secret)
+					if !ok {
+						return
+					}
+					oldSecret, ok := old.(*corev1.Secret)
+					if !ok {
+						return
+					}
+					m.Record(certificateConditions(monitorapi.SecretLocator(secret.Namespace, secret.Name), oldSecret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSCertKey])...)
+				},
+			},
+		)
+
+		namespaceInformers.ConfigMapInformer(namespace, factory).AddEventHandler(
+			cache.ResourceEventHandlerFuncs{
+				AddFunc: func(obj interface{}) {
+					configMap, ok := obj.(*corev1.ConfigMap)
+					if !ok {
+						return
+					}
+					m.Record(caBundleConditions(monitorapi.ConfigMapLocator(configMap.Namespace, configMap.Name), "", configMap.Data[caBundleKey])...)
+				},
+				UpdateFunc: func(old, obj interface{}) {
+					configMap, ok := obj.(*corev1.ConfigMap)
+					if !ok {
+						return
+					}
+					oldConfigMap, ok := old.(*corev1.ConfigMap)
+					if !ok {
+						return
+					}
+					m.Record(caBundleConditions(monitorapi.ConfigMapLocator(configMap.Namespace, configMap.Name), oldConfigMap.Data[caBundleKey], configMap.Data[caBundleKey])...)
+				},
+			},
+		)
+	})
+}
+
+func keepOnlyCertificate(secret *corev1.Secret) {
+	secret.Data = map[string][]byte{corev1.TLSCertKey: secret.Data[corev1.TLSCertKey]}
+	secret.StringData = nil
+	secret.ManagedFields = nil
+	delete(secret.Annotations, corev1.LastAppliedConfigAnnotation)
+}
+
+func keepOnlyCABundle(configMap *corev1.ConfigMap) {
+	caBundle, ok := configMap.Data[caBundleKey]
+	configMap.Data = nil
+	if ok {
+		configMap.Data = map[string]string{caBundleKey: caBundle}
+	}
+	configMap.BinaryData = nil
+	configMap.ManagedFields = nil
+	delete(configMap.Annotations, corev1.LastAppliedConfigAnnotation)
+}
+
+// certificateSummary is what we record about a certificate.
+type certificateSummary struct {
+	fingerprint string
+	commonName  string
+	notBefore   time.Time
+	notAfter    time.Time
+}
+
+// parseCertificateSummaries returns the certificates in the PEM data.  Data that cannot be parsed has no certificates.
+func parseCertificateSummaries(pemData []byte) []certificateSummary {
+	if len(pemData) == 0 {
+		return nil
+	}
+	certs, err := certutil.ParseCertsPEM(pemData)
+	if err != nil {
+		return nil
+	}
+	ret := []certificateSummary{}
+	for _, cert := range certs {
+		ret = append(ret, certificateSummary{
+			fingerprint: fmt.Sprintf("%x", sha256.Sum256(cert.Raw)),
+			// common names like "kube-apiserver-localhost-signer" are the norm, but keep the message parseable
+			commonName: strings.NewReplacer(" ", "_", "/", "_").Replace(cert.Subject.CommonName),
+			notBefore:  cert.NotBefore,
+			notAfter:   cert.NotAfter,
+		})
+	}
+	return ret
+}
+
+// certificateConditions records the leaf certificate of a TLS secret when it is first seen and when it changes.
+func certificateConditions(locator string, oldPEM, newPEM []byte) []monitorapi.Condition {
+	newCerts := parseCertificateSummaries(newPEM)
+	if len(newCerts) == 0 {
+		return nil
+	}
+	current := newCerts[0]
+	if oldPEM == nil {
+		return []monitorapi.Condition{
+			{
+				Level:   monitorapi.Info,
+				Locator: locator,
+				Message: fmt.Sprintf("reason/%s commonName/%s notBefore/%s notAfter/%s", monitorapi.CertificateReasonObserved,
+					current.commonName, current.notBefore.UTC().Format(time.RFC3339), current.notAfter.UTC().Format(time.RFC3339)),
+			},
+		}
+	}
+
+	oldCerts := parseCertificateSummaries(oldPEM)
+	if len(oldCerts) == 0 {
+		return nil
+	}
+	previous := oldCerts[0]
+	if previous.fingerprint == current.fingerprint {
+		return nil
+	}
+	level := monitorapi.Info
+	if current.notBefore.After(previous.notAfter) {
+		level = monitorapi.Warning
+	}
+	return []monitorapi.Condition{
+		{
+			Level:   level,
+			Locator: locator,
+			Message: fmt.Sprintf("reason/%s commonName/%s notBefore/%s notAfter/%s previousNotBefore/%s previousNotAfter/%s", monitorapi.CertificateReasonRotated,
+				current.commonName, current.notBefore.UTC().Format(time.RFC3339), current.notAfter.UTC().Format(time.RFC3339),
+				previous.notBefore.UTC().Format(time.RFC3339), previous.notAfter.UTC().Format(time.RFC3339)),
+		},
+	}
+}
+
+// caBundleConditions records a CA bundle when it is first seen and when certificates are added to or removed from it.
+// A rotation that does not retain any of the previous certificates leaves clients trusting only the new CA at the
+// same instant servers may still present certificates signed by the old one.
+func caBundleConditions(locator string, oldPEM, newPEM string) []monitorapi.Condition {
+	newCerts := parseCertificateSummaries([]byte(newPEM))
+	if len(newCerts) == 0 {
+		return nil
+	}
+	// the bundle is valid for as long as its longest lived certificate
+	notAfter := newCerts[0].notAfter
+	for _, cert := range newCerts {
+		if cert.notAfter.After(notAfter) {
+			notAfter = cert.notAfter
+		}
+	}
+	if len(oldPEM) == 0 {
+		return []monitorapi.Condition{
+			{
+				Level:   monitorapi.Info,
+				Locator: locator,
+				Message: fmt.Sprintf("reason/%s certificates/%d notAfter/%s", monitorapi.CABundleReasonObserved, len(newCerts), notAfter.UTC().Format(time.RFC3339)),
+			},
+		}
+	}
+
+	oldFingerprints := map[string]bool{}
+	for _, cert := range parseCertificateSummaries([]byte(oldPEM)) {
+		oldFingerprints[cert.fingerprint] = true
+	}
+	added, retained := 0, 0
+	for _, cert := range newCerts {
+		if oldFingerprints[cert.fingerprint] {
+			retained++
+		} else {
+			added++
+		}
+	}
+	removed := len(oldFingerprints) - retained
+	if added == 0 && removed == 0 {
+		return nil
+	}
+	level := monitorapi.Info
+	if retained == 0 && removed > 0 {
+		level = monitorapi.Warning
+	}
+	return []monitorapi.Condition{
+		{
+			Level:   level,
+			Locator: locator,
+			Message: fmt.Sprintf("reason/%s certificates/%d added/%d removed/%d retained/%d notAfter/%s", monitorapi.CABundleReasonRotated,
+				len(newCerts), added, removed, retained, notAfter.UTC().Format(time.RFC3339)),
+		},
+	}
+}
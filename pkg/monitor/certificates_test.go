@@ -0,0 +1,112 @@
+package monitor
+
+import (
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	certutil "k8s.io/client-go/util/cert"
+)
+
+// newTestCertificate returns a self-signed certificate, without the CA that GenerateSelfSignedCertKey signs it with.
+func newTestCertificate(t *testing.T, host string) []byte {
+	chain, _, err := certutil.GenerateSelfSignedCertKey(host, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, _ := pem.Decode(chain)
+	return pem.EncodeToMemory(block)
+}
+
+func TestCertificateConditions(t *testing.T) {
+	locator := monitorapi.SecretLocator("openshift-ingress", "router-certs-default")
+	first := newTestCertificate(t, "first.example.com")
+	second := newTestCertificate(t, "second.example.com")
+
+	observed := certificateConditions(locator, nil, first)
+	if len(observed) != 1 || !strings.HasPrefix(observed[0].Message, "reason/CertificateObserved commonName/first.example.com@") {
+		t.Fatalf("unexpected conditions when first observed: %#v", observed)
+	}
+	if unchanged := certificateConditions(locator, first, first); len(unchanged) != 0 {
+		t.Errorf("expected no conditions for an unchanged certificate, got %#v", unchanged)
+	}
+	if unparseable := certificateConditions(locator, nil, []byte("not a certificate")); len(unparseable) != 0 {
+		t.Errorf("expected no conditions for an unparseable certificate, got %#v", unparseable)
+	}
+
+	rotated := certificateConditions(locator, first, second)
+	if len(rotated) != 1 {
+		t.Fatalf("expected a single condition on rotation, got %#v", rotated)
+	}
+	annotations := monitorapi.AnnotationsFromMessage(rotated[0].Message)
+	if annotations["reason"] != monitorapi.CertificateReasonRotated || len(annotations["previousNotAfter"]) == 0 || len(annotations["notBefore"]) == 0 {
+		t.Errorf("unexpected rotation message: %v", rotated[0].Message)
+	}
+	if rotated[0].Level != monitorapi.Info {
+		t.Errorf("expected overlapping certificates to be info, got %v", rotated[0].Level)
+	}
+}
+
+func TestCABundleConditions(t *testing.T) {
+	locator := monitorapi.ConfigMapLocator("openshift-config-managed", "kube-apiserver-client-ca")
+	oldCA := string(newTestCertificate(t, "old-signer"))
+	newCA := string(newTestCertificate(t, "new-signer"))
+
+	tests := []struct {
+		name            string
+		oldPEM, newPEM  string
+		expectedMessage string
+		expectedLevel   monitorapi.EventLevel
+	}{
+		{
+			name:            "observed",
+			newPEM:          oldCA,
+			expectedMessage: "reason/CABundleObserved certificates/1 ",
+			expectedLevel:   monitorapi.Info,
+		},
+		{
+			name:   "unchanged",
+			oldPEM: oldCA,
+			newPEM: oldCA,
+		},
+		{
+			name:            "new CA added",
+			oldPEM:          oldCA,
+			newPEM:          oldCA + newCA,
+			expectedMessage: "reason/CABundleRotated certificates/2 added/1 removed/0 retained/1 ",
+			expectedLevel:   monitorapi.Info,
+		},
+		{
+			name:            "old CA removed after overlap",
+			oldPEM:          oldCA + newCA,
+			newPEM:          newCA,
+			expectedMessage: "reason/CABundleRotated certificates/1 added/0 removed/1 retained/1 ",
+			expectedLevel:   monitorapi.Info,
+		},
+		{
+			name:            "replaced without overlap",
+			oldPEM:          oldCA,
+			newPEM:          newCA,
+			expectedMessage: "reason/CABundleRotated certificates/1 added/1 removed/1 retained/0 ",
+			expectedLevel:   monitorapi.Warning,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conditions := caBundleConditions(locator, tt.oldPEM, tt.newPEM)
+			if len(tt.expectedMessage) == 0 {
+				if len(conditions) != 0 {
+					t.Fatalf("expected no conditions, got %#v", conditions)
+				}
+				return
+			}
+			if len(conditions) != 1 {
+				t.Fatalf("expected a single condition, got %#v", conditions)
+			}
+			if !strings.HasPrefix(conditions[0].Message, tt.expectedMessage) || conditions[0].Level != tt.expectedLevel {
+				t.Errorf("expected %v %q, got %v %q", tt.expectedLevel, tt.expectedMessage, conditions[0].Level, conditions[0].Message)
+			}
+		})
+	}
+}
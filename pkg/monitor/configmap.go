@@ -0,0 +1,48 @@
+package monitor
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// startConfigMapMonitoring records every configmap in openshift-* namespaces so that invariants can tell how often
+// they were updated and recreated.  Only the metadata is kept, because the recorded resources are written to the
+// artifacts.
+func startConfigMapMonitoring(m Recorder, namespaceInformers *openshiftNamespaceInformers) {
+	recordConfigMap := func(obj interface{}) {
+		configMap, ok := obj.(*corev1.ConfigMap)
+		if !ok {
+			return
+		}
+		configMap = configMap.DeepCopy()
+		removeConfigMapData(configMap)
+		m.RecordResource("configmaps", configMap)
+	}
+
+	namespaceInformers.AddHandler(func(namespace string, factory informers.SharedInformerFactory) {
+		namespaceInformers.ConfigMapInformer(namespace, factory).AddEventHandler(
+			cache.ResourceEventHandlerFuncs{
+				AddFunc: recordConfigMap,
+				UpdateFunc: func(old, obj interface{}) {
+					oldConfigMap, ok := old.(*corev1.ConfigMap)
+					if !ok {
+						return
+					}
+					configMap, ok := obj.(*corev1.ConfigMap)
+					// resyncs are not updates
+					if !ok || oldConfigMap.ResourceVersion == configMap.ResourceVersion {
+						return
+					}
+					recordConfigMap(configMap)
+				},
+			},
+		)
+	})
+}
+
+func removeConfigMapData(configMap *corev1.ConfigMap) {
+	configMap.Data = nil
+	configMap.BinaryData = nil
+	configMap.ManagedFields = nil
+}
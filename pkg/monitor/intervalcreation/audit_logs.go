@@ -0,0 +1,321 @@
+package intervalcreation
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/monitor/nodedetails"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// kubeAPIServerAuditLog is the current audit log of the kube-apiserver, relative to /var/log on control plane nodes.
+	kubeAPIServerAuditLog = "kube-apiserver/audit.log"
+
+	// shortWatchDuration is how long a watch must last to not count as reset.  Watches time out after several
+	// minutes unless the client asks for less.
+	shortWatchDuration = time.Minute
+
+	// maxAuditLogLineSize is how long a single audit event may be.  Events at the RequestResponse level carry objects.
+	maxAuditLogLineSize = 16 * 1024 * 1024
+)
+
+// AuditLogRequestCounts are the requests a single user agent made.
+type AuditLogRequestCounts struct {
+	Requests int
+	// ListsWithoutResourceVersion are lists that could not be served from the watch cache and went to etcd.
+	ListsWithoutResourceVersion int
+	// Throttled are requests rejected with a 429.
+	Throttled int
+	// ServerErrors are requests that failed with a 5xx.
+	ServerErrors int
+	// ShortWatches are watches that ended successfully in less than shortWatchDuration without the client asking for a
+	// short timeout, which usually means the watch was reset.
+	ShortWatches int
+}
+
+func (c *AuditLogRequestCounts) add(other AuditLogRequestCounts) {
+	c.Requests += other.Requests
+	c.ListsWithoutResourceVersion += other.ListsWithoutResourceVersion
+	c.Throttled += other.Throttled
+	c.ServerErrors += other.ServerErrors
+	c.ShortWatches += other.ShortWatches
+}
+
+// AuditLogSummary holds the request statistics of every user agent, and of every minute for each user agent.
+type AuditLogSummary struct {
+	UserAgentToCounts map[string]*AuditLogRequestCounts
+	// userAgentToMinuteCounts is used to find when requests were throttled or failed.
+	userAgentToMinuteCounts map[string]map[time.Time]*AuditLogRequestCounts
+
+	// firstRequest and lastRequest are when the earliest and the latest requests were received.
+	firstRequest time.Time
+	lastRequest  time.Time
+}
+
+func NewAuditLogSummary() *AuditLogSummary {
+	return &AuditLogSummary{
+		UserAgentToCounts:       map[string]*AuditLogRequestCounts{},
+		userAgentToMinuteCounts: map[string]map[time.Time]*AuditLogRequestCounts{},
+	}
+}
+
+// Add merges the statistics of another summary, like the one of another kube-apiserver, into this one.
+func (s *AuditLogSummary) Add(other *AuditLogSummary) {
+	s.addRequestTime(other.firstRequest)
+	s.addRequestTime(other.lastRequest)
+	for userAgent, counts := range other.UserAgentToCounts {
+		if _, ok := s.UserAgentToCounts[userAgent]; !ok {
+			s.UserAgentToCounts[userAgent] = &AuditLogRequestCounts{}
+		}
+		s.UserAgentToCounts[userAgent].add(*counts)
+	}
+	for userAgent, minuteToCounts := range other.userAgentToMinuteCounts {
+		if _, ok := s.userAgentToMinuteCounts[userAgent]; !ok {
+			s.userAgentToMinuteCounts[userAgent] = map[time.Time]*AuditLogRequestCounts{}
+		}
+		for minute, counts := range minuteToCounts {
+			if _, ok := s.userAgentToMinuteCounts[userAgent][minute]; !ok {
+				s.userAgentToMinuteCounts[userAgent][minute] = &AuditLogRequestCounts{}
+			}
+			s.userAgentToMinuteCounts[userAgent][minute].add(*counts)
+		}
+	}
+}
+
+func (s *AuditLogSummary) addRequestTime(t time.Time) {
+	if t.IsZero() {
+		return
+	}
+	if s.firstRequest.IsZero() || t.Before(s.firstRequest) {
+		s.firstRequest = t
+	}
+	if t.After(s.lastRequest) {
+		s.lastRequest = t
+	}
+}
+
+func (s *AuditLogSummary) addEvent(event *auditv1.Event) {
+	s.addRequestTime(event.RequestReceivedTimestamp.Time)
+	counts := AuditLogRequestCounts{Requests: 1}
+	code := int32(0)
+	if event.ResponseStatus != nil {
+		code = event.ResponseStatus.Code
+	}
+	switch {
+	case code == 429:
+		counts.Throttled = 1
+	case code >= 500:
+		counts.ServerErrors = 1
+	}
+
+	query := url.Values{}
+	if requestURL, err := url.Parse(event.RequestURI); err == nil {
+		query = requestURL.Query()
+	}
+	switch event.Verb {
+	case "list":
+		if len(query.Get("resourceVersion")) == 0 {
+			counts.ListsWithoutResourceVersion = 1
+		}
+	case "watch":
+		requestedTimeout, _ := strconv.Atoi(query.Get("timeoutSeconds"))
+		duration := event.StageTimestamp.Sub(event.RequestReceivedTimestamp.Time)
+		requestedShortWatch := requestedTimeout > 0 && time.Duration(requestedTimeout)*time.Second <= shortWatchDuration
+		if code == 200 && duration < shortWatchDuration && !requestedShortWatch {
+			counts.ShortWatches = 1
+		}
+	}
+
+	userAgent := monitorapi.UserAgentFrom(event.UserAgent)
+	if _, ok := s.UserAgentToCounts[userAgent]; !ok {
+		s.UserAgentToCounts[userAgent] = &AuditLogRequestCounts{}
+	}
+	s.UserAgentToCounts[userAgent].add(counts)
+
+	minute := event.RequestReceivedTimestamp.Time.UTC().Truncate(time.Minute)
+	if _, ok := s.userAgentToMinuteCounts[userAgent]; !ok {
+		s.userAgentToMinuteCounts[userAgent] = map[time.Time]*AuditLogRequestCounts{}
+	}
+	if _, ok := s.userAgentToMinuteCounts[userAgent][minute]; !ok {
+		s.userAgentToMinuteCounts[userAgent][minute] = &AuditLogRequestCounts{}
+	}
+	s.userAgentToMinuteCounts[userAgent][minute].add(counts)
+}
+
+// ParseAuditLog reads the audit events of a kube-apiserver, one JSON event per line, and adds every completed request
+// received between beginning and end to the summary.  Lines that are not audit events are skipped.
+func ParseAuditLog(in io.Reader, beginning, end time.Time, summary *AuditLogSummary) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), maxAuditLogLineSize)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		event := &auditv1.Event{}
+		if err := json.Unmarshal(line, event); err != nil {
+			continue
+		}
+		// every request is logged once when it completes, and long running requests once more when they start
+		if event.Stage != auditv1.StageResponseComplete && event.Stage != auditv1.StagePanic {
+			continue
+		}
+		if event.RequestReceivedTimestamp.Time.Before(beginning) {
+			continue
+		}
+		if !end.IsZero() && event.RequestReceivedTimestamp.Time.After(end) {
+			continue
+		}
+		summary.addEvent(event)
+	}
+	return scanner.Err()
+}
+
+// IntervalsFromAuditLogSummary creates an interval spanning the run with the request statistics of every user agent,
+// and intervals for the minutes in which requests of a user agent were throttled or failed with a 5xx.  Consecutive
+// minutes are merged, and cut to the run.  When the beginning or the end of the run is unknown, the earliest or the
+// latest request is used instead, so that the requests are never spread over more time than they were made in.
+func IntervalsFromAuditLogSummary(summary *AuditLogSummary, beginning, end time.Time) monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+	if beginning.IsZero() {
+		beginning = summary.firstRequest
+	}
+	if end.IsZero() {
+		end = summary.lastRequest
+	}
+
+	userAgents := []string{}
+	for userAgent := range summary.UserAgentToCounts {
+		userAgents = append(userAgents, userAgent)
+	}
+	sort.Strings(userAgents)
+
+	for _, userAgent := range userAgents {
+		counts := summary.UserAgentToCounts[userAgent]
+		locator := monitorapi.UserAgentLocator(userAgent)
+		ret = append(ret, monitorapi.EventInterval{
+			Condition: monitorapi.Condition{
+				Level:   monitorapi.Info,
+				Locator: locator,
+				Message: fmt.Sprintf("reason/%s requests/%d listsWithoutResourceVersion/%d throttled/%d serverErrors/%d shortWatches/%d",
+					monitorapi.AuditLogReasonRequestSummary, counts.Requests, counts.ListsWithoutResourceVersion,
+					counts.Throttled, counts.ServerErrors, counts.ShortWatches),
+			},
+			From: beginning,
+			To:   end,
+		})
+
+		minuteToCounts := summary.userAgentToMinuteCounts[userAgent]
+		minutes := []time.Time{}
+		for minute := range minuteToCounts {
+			minutes = append(minutes, minute)
+		}
+		sort.Slice(minutes, func(i, j int) bool {
+			return minutes[i].Before(minutes[j])
+		})
+		ret = append(ret, mergedMinuteIntervals(locator, monitorapi.AuditLogReasonThrottled, monitorapi.Warning, minutes, func(minute time.Time) int {
+			return minuteToCounts[minute].Throttled
+		})...)
+		ret = append(ret, mergedMinuteIntervals(locator, monitorapi.AuditLogReasonServerErrors, monitorapi.Error, minutes, func(minute time.Time) int {
+			return minuteToCounts[minute].ServerErrors
+		})...)
+	}
+
+	ret = ret.Cut(beginning, end)
+	sort.Sort(ret)
+	return ret
+}
+
+func mergedMinuteIntervals(locator, reason string, level monitorapi.EventLevel, minutes []time.Time, countFor func(time.Time) int) monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+	var current *monitorapi.EventInterval
+	total := 0
+	flush := func() {
+		if current == nil {
+			return
+		}
+		current.Message = fmt.Sprintf("reason/%s count/%d", reason, total)
+		ret = append(ret, *current)
+		current = nil
+		total = 0
+	}
+	for _, minute := range minutes {
+		count := countFor(minute)
+		if count == 0 {
+			continue
+		}
+		if current != nil && current.To.Equal(minute) {
+			current.To = minute.Add(time.Minute)
+			total += count
+			continue
+		}
+		flush()
+		current = &monitorapi.EventInterval{
+			Condition: monitorapi.Condition{Level: level, Locator: locator},
+			From:      minute,
+			To:        minute.Add(time.Minute),
+		}
+		total = count
+	}
+	flush()
+	return ret
+}
+
+// IntervalsFromAuditLogs reads the audit log of the kube-apiserver on every control plane node and creates intervals
+// from the request statistics of every user agent for the requests received between beginning and end.  Only the
+// current audit log is read, so on long runs the oldest requests may have been rotated away already.  The audit logs
+// are a best effort source: they are missing when the audit profile is None, so logs that cannot be read are logged and
+// never fail the collection.
+func IntervalsFromAuditLogs(ctx context.Context, kubeClient kubernetes.Interface, beginning, end time.Time) (monitorapi.Intervals, error) {
+	masters, err := kubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: "node-role.kubernetes.io/master"})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Skipping audit logs: %v\n", err)
+		return nil, nil
+	}
+
+	collectionStart := time.Now()
+	summary := NewAuditLogSummary()
+	lock := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	for _, node := range masters.Items {
+		wg.Add(1)
+		go func(nodeName string) {
+			defer wg.Done()
+
+			nodeSummary := NewAuditLogSummary()
+			err := func() error {
+				in, err := nodedetails.StreamNodeLogFile(ctx, kubeClient, nodeName, kubeAPIServerAuditLog)
+				if err != nil {
+					return err
+				}
+				defer in.Close()
+				return ParseAuditLog(in, beginning, end, nodeSummary)
+			}()
+
+			lock.Lock()
+			defer lock.Unlock()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Skipping audit log %q on node/%s: %v\n", kubeAPIServerAuditLog, nodeName, err)
+				return
+			}
+			summary.Add(nodeSummary)
+		}(node.Name)
+	}
+	wg.Wait()
+	fmt.Fprintf(os.Stderr, "Collection of audit logs and analysis took: %v\n", time.Now().Sub(collectionStart))
+
+	return IntervalsFromAuditLogSummary(summary, beginning, end), nil
+}
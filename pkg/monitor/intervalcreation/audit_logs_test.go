@@ -0,0 +1,111 @@
+package intervalcreation
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestParseAuditLog(t *testing.T) {
+	start := timeFor("2022-07-05T17:00:00Z")
+	end := start.Add(10 * time.Minute)
+
+	in, err := os.Open("testdata/audit.log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer in.Close()
+
+	summary := NewAuditLogSummary()
+	if err := ParseAuditLog(in, start, end, summary); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedCounts := map[string]*AuditLogRequestCounts{
+		"cluster-version-operator": {Requests: 4, ListsWithoutResourceVersion: 1, ShortWatches: 1},
+		"kube-controller-manager":  {Requests: 7, Throttled: 4, ServerErrors: 1},
+	}
+	if !reflect.DeepEqual(summary.UserAgentToCounts, expectedCounts) {
+		for userAgent, counts := range summary.UserAgentToCounts {
+			t.Logf("%s: %#v", userAgent, *counts)
+		}
+		t.Fatal("unexpected counts")
+	}
+
+	// a second kube-apiserver doubles everything
+	summary.Add(summary)
+	if counts := summary.UserAgentToCounts["kube-controller-manager"]; counts.Requests != 14 || counts.Throttled != 8 {
+		t.Errorf("unexpected counts after adding another summary: %#v", *counts)
+	}
+}
+
+func TestIntervalsFromAuditLogSummary(t *testing.T) {
+	start := timeFor("2022-07-05T17:00:00Z")
+	end := start.Add(10 * time.Minute)
+
+	in, err := os.Open("testdata/audit.log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer in.Close()
+	summary := NewAuditLogSummary()
+	if err := ParseAuditLog(in, start, end, summary); err != nil {
+		t.Fatal(err)
+	}
+
+	actual := IntervalsFromAuditLogSummary(summary, start, end)
+	expected := []struct {
+		locator  string
+		message  string
+		from, to time.Time
+	}{
+		{"user-agent/cluster-version-operator", "reason/AuditLogRequestSummary requests/4 listsWithoutResourceVersion/1 throttled/0 serverErrors/0 shortWatches/1", start, end},
+		{"user-agent/kube-controller-manager", "reason/AuditLogRequestSummary requests/7 listsWithoutResourceVersion/0 throttled/4 serverErrors/1 shortWatches/0", start, end},
+		{"user-agent/kube-controller-manager", "reason/APIRequestsThrottled count/3", start.Add(time.Minute), start.Add(3 * time.Minute)},
+		{"user-agent/kube-controller-manager", "reason/APIServerErrors count/1", start.Add(3 * time.Minute), start.Add(4 * time.Minute)},
+		{"user-agent/kube-controller-manager", "reason/APIRequestsThrottled count/1", start.Add(5 * time.Minute), start.Add(6 * time.Minute)},
+	}
+	if len(actual) != len(expected) {
+		t.Fatalf("unexpected intervals: %v", actual.Strings())
+	}
+	for i := range expected {
+		if actual[i].Locator != expected[i].locator || actual[i].Message != expected[i].message || !actual[i].From.Equal(expected[i].from) || !actual[i].To.Equal(expected[i].to) {
+			t.Errorf("unexpected interval %d: %v", i, actual[i].String())
+		}
+	}
+}
+
+func TestIntervalsFromAuditLogSummary_window(t *testing.T) {
+	start := timeFor("2022-07-05T17:00:00Z")
+
+	in, err := os.Open("testdata/audit.log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer in.Close()
+	summary := NewAuditLogSummary()
+	if err := ParseAuditLog(in, time.Time{}, time.Time{}, summary); err != nil {
+		t.Fatal(err)
+	}
+
+	// without the run, the summaries span the requests
+	for _, interval := range IntervalsFromAuditLogSummary(summary, time.Time{}, time.Time{}) {
+		if monitorapi.ReasonFrom(interval.Message) != monitorapi.AuditLogReasonRequestSummary {
+			continue
+		}
+		if !interval.From.Equal(timeFor("2022-07-05T16:59:59Z")) || !interval.To.Equal(timeFor("2022-07-05T17:05:10Z")) {
+			t.Errorf("unexpected summary: %v", interval.String())
+		}
+	}
+
+	// minutes are cut to the run
+	end := start.Add(5*time.Minute + 30*time.Second)
+	for _, interval := range IntervalsFromAuditLogSummary(summary, start, end) {
+		if interval.From.Before(start) || interval.To.After(end) {
+			t.Errorf("interval outside of the run: %v", interval.String())
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package intervalcreation
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// IntervalsFromEvents_CertificateRotations creates an interval for every certificate rotation, from when the new
+// certificate became valid until the previous certificate expired.  If the new certificate only became valid after the
+// previous one expired, the interval is the gap between them instead.  Certificates are valid for much longer than a
+// run, so the intervals are clipped to the run.
+func IntervalsFromEvents_CertificateRotations(events monitorapi.Intervals, _ monitorapi.ResourcesMap, beginning, end time.Time) monitorapi.Intervals {
+	var intervals monitorapi.Intervals
+	for _, event := range events {
+		if !monitorapi.IsCertificateHolder(event.Locator) {
+			continue
+		}
+		if monitorapi.ReasonFrom(event.Message) != monitorapi.CertificateReasonRotated {
+			continue
+		}
+		annotations := monitorapi.AnnotationsFromMessage(event.Message)
+		notBefore, err := time.Parse(time.RFC3339, annotations["notBefore"])
+		if err != nil {
+			continue
+		}
+		previousNotAfter, err := time.Parse(time.RFC3339, annotations["previousNotAfter"])
+		if err != nil {
+			continue
+		}
+
+		level := monitorapi.Info
+		overlap := true
+		from, to := notBefore, previousNotAfter
+		if from.After(to) {
+			level = monitorapi.Error
+			overlap = false
+			from, to = previousNotAfter, notBefore
+		}
+		if from.Before(beginning) {
+			from = beginning
+		}
+		if !end.IsZero() && to.After(end) {
+			to = end
+		}
+		if to.Before(from) {
+			continue
+		}
+
+		intervals = append(intervals, monitorapi.EventInterval{
+			Condition: monitorapi.Condition{
+				Level:   level,
+				Locator: event.Locator,
+				Message: fmt.Sprintf("reason/%s commonName/%s overlap/%v notBefore/%s previousNotAfter/%s", monitorapi.CertificateReasonRotation,
+					annotations["commonName"], overlap, annotations["notBefore"], annotations["previousNotAfter"]),
+			},
+			From: from,
+			To:   to,
+		})
+	}
+	return intervals
+}
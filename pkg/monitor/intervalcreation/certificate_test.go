@@ -0,0 +1,38 @@
+package intervalcreation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestIntervalsFromEvents_CertificateRotations(t *testing.T) {
+	start := timeFor("2022-07-05T17:00:00Z")
+	end := start.Add(time.Hour)
+	serving := monitorapi.SecretLocator("openshift-kube-apiserver", "internal-loadbalancer-serving-certkey")
+	client := monitorapi.SecretLocator("openshift-kube-apiserver", "kubelet-client")
+	events := monitorapi.Intervals{
+		instant(serving, "reason/CertificateRotated commonName/api-int notBefore/2022-07-05T17:09:00Z notAfter/2022-08-04T17:09:00Z previousNotBefore/2022-07-05T16:00:00Z previousNotAfter/2022-08-04T16:00:00Z", start.Add(10*time.Minute)),
+		instant(client, "reason/CertificateRotated commonName/kubelet notBefore/2022-07-05T17:25:00Z notAfter/2022-08-04T17:25:00Z previousNotBefore/2022-07-04T17:00:00Z previousNotAfter/2022-07-05T17:20:00Z", start.Add(25*time.Minute)),
+		instant(serving, "reason/CertificateObserved commonName/api-int notBefore/2022-07-05T16:00:00Z notAfter/2022-08-04T16:00:00Z", start),
+	}
+
+	actual := IntervalsFromEvents_CertificateRotations(events, nil, start, end)
+	expected := []struct {
+		locator  string
+		level    monitorapi.EventLevel
+		from, to time.Time
+	}{
+		{serving, monitorapi.Info, start.Add(9 * time.Minute), end},
+		{client, monitorapi.Error, start.Add(20 * time.Minute), start.Add(25 * time.Minute)},
+	}
+	if len(actual) != len(expected) {
+		t.Fatalf("unexpected intervals: %v", actual.Strings())
+	}
+	for i := range expected {
+		if actual[i].Locator != expected[i].locator || actual[i].Level != expected[i].level || !actual[i].From.Equal(expected[i].from) || !actual[i].To.Equal(expected[i].to) {
+			t.Errorf("unexpected interval %d: %v", i, actual[i].String())
+		}
+	}
+}
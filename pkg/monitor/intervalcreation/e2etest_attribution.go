@@ -0,0 +1,207 @@
+package intervalcreation
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// e2eTestWindow is when a single e2e test ran.
+type e2eTestWindow struct {
+	testName string
+	from     time.Time
+	to       time.Time
+}
+
+// e2eNamespaceSpan is the first and last time we saw anything happen in an e2e namespace.
+type e2eNamespaceSpan struct {
+	namespace string
+	from      time.Time
+	to        time.Time
+}
+
+// IntervalsFromEvents_E2ETestNamespaces attributes every ns/e2e-* namespace to the e2e test that created it and
+// creates an interval per namespace spanning everything observed in it.  It depends on the intervals created by
+// IntervalsFromEvents_E2ETests to know when each test ran.
+//
+// e2e namespaces are created by the test framework at the beginning of a test, so the owning test is one that was
+// running when the namespace was first seen.  When several tests run in parallel, the framework base name in the
+// namespace, like router-metrics in e2e-test-router-metrics-qmrpb, picks the test whose name shares the most words with
+// it.  Only when that does not tell them apart does the test that started most recently win.
+func IntervalsFromEvents_E2ETestNamespaces(events monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+
+	testWindows := e2eTestWindowsFrom(events)
+	for _, span := range e2eNamespaceSpansFrom(events, recordedResources) {
+		testName, ok := owningE2ETest(testWindows, span.namespace, span.from)
+		if !ok {
+			continue
+		}
+		ret = append(ret, monitorapi.EventInterval{
+			Condition: monitorapi.Condition{
+				Level:   monitorapi.Info,
+				Locator: fmt.Sprintf("ns/%s", span.namespace),
+				Message: monitorapi.ReasonedMessagef(monitorapi.E2ETestReasonNamespaceOwner, "test/%s", strconv.Quote(testName)),
+			},
+			From: span.from,
+			To:   span.to,
+		})
+	}
+
+	return ret
+}
+
+func e2eTestWindowsFrom(events monitorapi.Intervals) []e2eTestWindow {
+	ret := []e2eTestWindow{}
+	for _, event := range events {
+		testName, ok := monitorapi.E2ETestFromLocator(event.Locator)
+		if !ok {
+			continue
+		}
+		// only the calculated intervals have a duration, the started and finished instants do not.
+		if !event.To.After(event.From) {
+			continue
+		}
+		ret = append(ret, e2eTestWindow{testName: testName, from: event.From, to: event.To})
+	}
+	return ret
+}
+
+func e2eNamespaceSpansFrom(events monitorapi.Intervals, recordedResources monitorapi.ResourcesMap) []e2eNamespaceSpan {
+	namespaceToSpan := map[string]*e2eNamespaceSpan{}
+	observe := func(namespace string, from, to time.Time) {
+		if !strings.HasPrefix(namespace, "e2e-") || from.IsZero() {
+			return
+		}
+		if to.Before(from) {
+			to = from
+		}
+		span, ok := namespaceToSpan[namespace]
+		if !ok {
+			namespaceToSpan[namespace] = &e2eNamespaceSpan{namespace: namespace, from: from, to: to}
+			return
+		}
+		if from.Before(span.from) {
+			span.from = from
+		}
+		if to.After(span.to) {
+			span.to = to
+		}
+	}
+
+	for _, event := range events {
+		observe(monitorapi.NamespaceFromLocator(event.Locator), event.From, event.To)
+	}
+	// pods are often created before any event about them is recorded, so their creation time is a better signal.
+	for _, obj := range recordedResources["pods"] {
+		pod, ok := obj.(*corev1.Pod)
+		if !ok {
+			continue
+		}
+		observe(pod.Namespace, pod.CreationTimestamp.Time, pod.CreationTimestamp.Time)
+	}
+
+	ret := []e2eNamespaceSpan{}
+	for _, span := range namespaceToSpan {
+		ret = append(ret, *span)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].namespace < ret[j].namespace
+	})
+	return ret
+}
+
+func owningE2ETest(testWindows []e2eTestWindow, namespace string, firstSeen time.Time) (string, bool) {
+	baseNameWords := e2eNamespaceBaseNameWords(namespace)
+	var owner *e2eTestWindow
+	ownerMatches := 0
+	for i := range testWindows {
+		window := &testWindows[i]
+		if firstSeen.Before(window.from) || firstSeen.After(window.to) {
+			continue
+		}
+		matches := 0
+		testNameWords := sets.NewString(nameWords(window.testName)...)
+		for _, word := range baseNameWords {
+			if testNameWords.Has(word) {
+				matches++
+			}
+		}
+		if owner == nil || matches > ownerMatches || (matches == ownerMatches && window.from.After(owner.from)) {
+			owner = window
+			ownerMatches = matches
+		}
+	}
+	if owner == nil {
+		return "", false
+	}
+	return owner.testName, true
+}
+
+// e2eNamespaceBaseNameWords returns the words of the framework base name in an e2e namespace.  The namespaces are named
+// e2e-<base name>-<random suffix>, and the openshift framework prefixes the base name with test-.
+func e2eNamespaceBaseNameWords(namespace string) []string {
+	baseName := strings.TrimPrefix(strings.TrimPrefix(namespace, "e2e-"), "test-")
+	if i := strings.LastIndex(baseName, "-"); i != -1 {
+		baseName = baseName[:i]
+	}
+	return nameWords(baseName)
+}
+
+// nameWords splits a test or namespace name into lowercase words.
+func nameWords(name string) []string {
+	return strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// E2ETestNamespaceOwners returns the owning test for each e2e namespace, based on the intervals created by
+// IntervalsFromEvents_E2ETestNamespaces.
+func E2ETestNamespaceOwners(events monitorapi.Intervals) map[string]string {
+	ret := map[string]string{}
+	for _, event := range events {
+		if monitorapi.ReasonFrom(event.Message) != monitorapi.E2ETestReasonNamespaceOwner {
+			continue
+		}
+		i := strings.Index(event.Message, "test/")
+		if i == -1 {
+			continue
+		}
+		testName, err := strconv.Unquote(event.Message[i+len("test/"):])
+		if err != nil {
+			continue
+		}
+		ret[monitorapi.NamespaceFromLocator(event.Locator)] = testName
+	}
+	return ret
+}
+
+// GroupByE2ETest prefixes the locator of every interval in an attributed e2e namespace with its owning test, so
+// that timelines sorted by locator show everything a test did next to each other.
+func GroupByE2ETest(events monitorapi.Intervals) monitorapi.Intervals {
+	namespaceToTest := E2ETestNamespaceOwners(events)
+	ret := make(monitorapi.Intervals, 0, len(events))
+	for _, event := range events {
+		testName, ok := namespaceToTest[monitorapi.NamespaceFromLocator(event.Locator)]
+		if ok {
+			event.Locator = monitorapi.E2ETestLocator(testName) + " " + event.Locator
+		}
+		ret = append(ret, event)
+	}
+	return ret
+}
+
+// BelongsInE2ETest selects e2e tests and everything in e2e namespaces.
+func BelongsInE2ETest(eventInterval monitorapi.EventInterval) bool {
+	if monitorapi.IsE2ETest(eventInterval.Locator) {
+		return true
+	}
+	return monitorapi.IsInE2ENamespace(eventInterval)
+}
@@ -0,0 +1,87 @@
+package intervalcreation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIntervalsFromEvents_E2ETestNamespaces(t *testing.T) {
+	start := timeFor("2022-07-05T17:00:00Z")
+	testA := monitorapi.E2ETestLocator("[sig-apps] test a")
+	testB := monitorapi.E2ETestLocator("[sig-network] test b")
+	events := monitorapi.Intervals{
+		instant(testA, "started", start),
+		instant(testB, "started", start.Add(time.Minute)),
+		instant("ns/e2e-test-a-1234 pod/pod-a node/worker-0 uid/a", "reason/Scheduled node/worker-0", start.Add(30*time.Second)),
+		instant("ns/e2e-test-b-5678 pod/pod-b node/worker-0 uid/b", "reason/Scheduled node/worker-0", start.Add(3*time.Minute)),
+		instant(testA, "finishedStatus/Passed", start.Add(5*time.Minute)),
+		instant(testB, "finishedStatus/Failed", start.Add(6*time.Minute)),
+		instant("ns/e2e-orphan pod/pod-c node/worker-0 uid/c", "reason/Scheduled node/worker-0", start.Add(10*time.Minute)),
+	}
+	// the pod in the second namespace was created before anything was recorded about it.
+	recordedResources := monitorapi.ResourcesMap{
+		"pods": monitorapi.InstanceMap{
+			monitorapi.InstanceKey{Namespace: "e2e-test-b-5678", Name: "pod-b", UID: "b"}: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:         "e2e-test-b-5678",
+					Name:              "pod-b",
+					CreationTimestamp: metav1.NewTime(start.Add(2 * time.Minute)),
+				},
+			},
+		},
+	}
+
+	allEvents := InsertCalculatedIntervals(events, recordedResources, start, start.Add(time.Hour))
+	owners := E2ETestNamespaceOwners(allEvents)
+	if len(owners) != 2 {
+		t.Fatalf("unexpected owners: %v", owners)
+	}
+	if owners["e2e-test-a-1234"] != "[sig-apps] test a" {
+		t.Errorf("unexpected owner for e2e-test-a-1234: %v", owners["e2e-test-a-1234"])
+	}
+	if owners["e2e-test-b-5678"] != "[sig-network] test b" {
+		t.Errorf("unexpected owner for e2e-test-b-5678: %v", owners["e2e-test-b-5678"])
+	}
+
+	grouped := GroupByE2ETest(allEvents).Filter(func(eventInterval monitorapi.EventInterval) bool {
+		return monitorapi.ReasonFrom(eventInterval.Message) == "Scheduled" && !IsPodLifecycle(eventInterval)
+	})
+	if len(grouped) != 3 {
+		t.Fatalf("unexpected intervals: %v", grouped.Strings())
+	}
+	if grouped[0].Locator != testA+" ns/e2e-test-a-1234 pod/pod-a node/worker-0 uid/a" {
+		t.Errorf("unexpected locator: %v", grouped[0].Locator)
+	}
+	if grouped[2].Locator != "ns/e2e-orphan pod/pod-c node/worker-0 uid/c" {
+		t.Errorf("unexpected locator: %v", grouped[2].Locator)
+	}
+}
+
+func TestIntervalsFromEvents_E2ETestNamespaces_parallel(t *testing.T) {
+	start := timeFor("2022-07-05T17:00:00Z")
+	routerTestName := "[sig-network][Feature:Router] The HAProxy router should expose prometheus metrics"
+	statefulSetTestName := "[sig-apps] StatefulSet Basic StatefulSet functionality should have a working scale subresource"
+	routerTest := monitorapi.E2ETestLocator(routerTestName)
+	statefulSetTest := monitorapi.E2ETestLocator(statefulSetTestName)
+	events := monitorapi.Intervals{
+		instant(routerTest, "started", start),
+		instant(statefulSetTest, "started", start.Add(time.Minute)),
+		// the router test namespace is first seen after the statefulset test started
+		instant("ns/e2e-test-router-metrics-qmrpb pod/router node/worker-0 uid/a", "reason/Scheduled node/worker-0", start.Add(2*time.Minute)),
+		instant("ns/e2e-statefulset-6521 pod/ss-0 node/worker-0 uid/b", "reason/Scheduled node/worker-0", start.Add(3*time.Minute)),
+		instant(routerTest, "finishedStatus/Passed", start.Add(5*time.Minute)),
+		instant(statefulSetTest, "finishedStatus/Passed", start.Add(6*time.Minute)),
+	}
+
+	owners := E2ETestNamespaceOwners(InsertCalculatedIntervals(events, nil, start, start.Add(time.Hour)))
+	if owner := owners["e2e-test-router-metrics-qmrpb"]; owner != routerTestName {
+		t.Errorf("unexpected owner for e2e-test-router-metrics-qmrpb: %v", owner)
+	}
+	if owner := owners["e2e-statefulset-6521"]; owner != statefulSetTestName {
+		t.Errorf("unexpected owner for e2e-statefulset-6521: %v", owner)
+	}
+}
@@ -0,0 +1,68 @@
+package intervalcreation
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// IntervalsFromEvents_LeaseHolders creates an interval for every holder of a lease, from when the monitor first saw
+// them holding it until the lease was released, changed hands or the run ended.
+func IntervalsFromEvents_LeaseHolders(events monitorapi.Intervals, _ monitorapi.ResourcesMap, beginning, end time.Time) monitorapi.Intervals {
+	var intervals monitorapi.Intervals
+	type openHolder struct {
+		holder string
+		from   time.Time
+	}
+	leaseToHolder := map[string]openHolder{}
+
+	closeHolder := func(locator string, to time.Time) {
+		open, ok := leaseToHolder[locator]
+		if !ok {
+			return
+		}
+		delete(leaseToHolder, locator)
+		intervals = append(intervals, monitorapi.EventInterval{
+			Condition: monitorapi.Condition{
+				Level:   monitorapi.Info,
+				Locator: locator,
+				Message: fmt.Sprintf("reason/%s holder/%s", monitorapi.LeaseReasonHolder, open.holder),
+			},
+			From: open.from,
+			To:   to,
+		})
+	}
+
+	for _, event := range events {
+		if !monitorapi.IsLease(event.Locator) {
+			continue
+		}
+		reason := monitorapi.ReasonFrom(event.Message)
+		if reason == monitorapi.LeaseReasonHolderReleased {
+			closeHolder(event.Locator, event.From)
+			continue
+		}
+		if reason != monitorapi.LeaseReasonHolderObserved && reason != monitorapi.LeaseReasonHolderChanged {
+			continue
+		}
+		holder := monitorapi.AnnotationsFromMessage(event.Message)["holder"]
+		if open, ok := leaseToHolder[event.Locator]; ok && open.holder == holder {
+			continue
+		}
+		closeHolder(event.Locator, event.From)
+		leaseToHolder[event.Locator] = openHolder{holder: holder, from: event.From}
+	}
+
+	locators := []string{}
+	for locator := range leaseToHolder {
+		locators = append(locators, locator)
+	}
+	sort.Strings(locators)
+	for _, locator := range locators {
+		closeHolder(locator, end)
+	}
+
+	return intervals
+}
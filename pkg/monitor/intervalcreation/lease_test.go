@@ -0,0 +1,40 @@
+package intervalcreation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestIntervalsFromEvents_LeaseHolders(t *testing.T) {
+	start := timeFor("2022-07-05T17:00:00Z")
+	locator := monitorapi.LeaseLocator("kube-system", "kube-scheduler")
+	events := monitorapi.Intervals{
+		instant(locator, "reason/LeaseHolderObserved holder/master-0_a transitions/3", start),
+		instant("node/master-0", "reason/NodeUpdate", start.Add(time.Minute)),
+		instant(locator, "reason/LeaseHolderChanged holder/master-1_b previousHolder/master-0_a transitions/4", start.Add(2*time.Minute)),
+		instant(locator, "reason/LeaseHolderChanged holder/master-1_b previousHolder/master-1_b transitions/4", start.Add(3*time.Minute)),
+		// released on graceful shutdown
+		instant(locator, "reason/LeaseHolderReleased previousHolder/master-1_b transitions/4", start.Add(5*time.Minute)),
+		instant(locator, "reason/LeaseHolderChanged holder/master-2_c previousHolder/master-1_b transitions/5", start.Add(6*time.Minute)),
+	}
+
+	actual := IntervalsFromEvents_LeaseHolders(events, nil, start, start.Add(10*time.Minute))
+	expected := []struct {
+		message  string
+		duration time.Duration
+	}{
+		{"reason/LeaseHolder holder/master-0_a", 2 * time.Minute},
+		{"reason/LeaseHolder holder/master-1_b", 3 * time.Minute},
+		{"reason/LeaseHolder holder/master-2_c", 4 * time.Minute},
+	}
+	if len(actual) != len(expected) {
+		t.Fatalf("unexpected intervals: %v", actual.Strings())
+	}
+	for i := range expected {
+		if actual[i].Locator != locator || actual[i].Message != expected[i].message || actual[i].To.Sub(actual[i].From) != expected[i].duration {
+			t.Errorf("unexpected interval %d: %v", i, actual[i].String())
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package intervalcreation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// IntervalsFromEvents_MachineConfigPoolUpdating creates an interval for every time a MachineConfigPool was updating
+// or degraded.
+func IntervalsFromEvents_MachineConfigPoolUpdating(events monitorapi.Intervals, _ monitorapi.ResourcesMap, beginning, end time.Time) monitorapi.Intervals {
+	var intervals monitorapi.Intervals
+	type openCondition struct {
+		from    time.Time
+		message string
+	}
+	poolToOpen := map[string]map[string]openCondition{}
+	reasonFor := map[string]string{
+		"Updating": monitorapi.MachineConfigPoolReasonUpdating,
+		"Degraded": monitorapi.MachineConfigPoolReasonDegraded,
+	}
+	levelFor := map[string]monitorapi.EventLevel{
+		"Updating": monitorapi.Info,
+		"Degraded": monitorapi.Error,
+	}
+
+	for _, event := range events {
+		pool, ok := monitorapi.MachineConfigPoolFromLocator(event.Locator)
+		if !ok {
+			continue
+		}
+		condition := monitorapi.GetOperatorConditionStatus(event.Message)
+		if condition == nil {
+			continue
+		}
+		conditionType := string(condition.Type)
+		if _, ok := reasonFor[conditionType]; !ok {
+			continue
+		}
+		state, ok := poolToOpen[pool]
+		if !ok {
+			state = map[string]openCondition{}
+			poolToOpen[pool] = state
+		}
+
+		open, isOpen := state[conditionType]
+		switch {
+		case condition.Status == "True" && !isOpen:
+			state[conditionType] = openCondition{from: event.From, message: condition.Message}
+		case condition.Status != "True" && isOpen:
+			delete(state, conditionType)
+			intervals = append(intervals, monitorapi.EventInterval{
+				Condition: monitorapi.Condition{
+					Level:   levelFor[conditionType],
+					Locator: event.Locator,
+					Message: monitorapi.ReasonedMessage(reasonFor[conditionType], open.message),
+				},
+				From: open.from,
+				To:   event.From,
+			})
+		}
+	}
+
+	pools := []string{}
+	for pool := range poolToOpen {
+		pools = append(pools, pool)
+	}
+	sort.Strings(pools)
+	for _, pool := range pools {
+		for _, conditionType := range []string{"Updating", "Degraded"} {
+			open, ok := poolToOpen[pool][conditionType]
+			if !ok {
+				continue
+			}
+			intervals = append(intervals, monitorapi.EventInterval{
+				Condition: monitorapi.Condition{
+					Level:   monitorapi.Warning,
+					Locator: monitorapi.MachineConfigPoolLocator(pool),
+					Message: monitorapi.ReasonedMessage(reasonFor[conditionType], "never completed", open.message),
+				},
+				From: open.from,
+				To:   end,
+			})
+		}
+	}
+
+	return intervals
+}
+
+// IntervalsFromEvents_MachineConfigNodeUpdates creates intervals for each node applying a machine config, for the
+// drain requested by the machine-config-daemon, and for the node rebooting (not ready) while applying the config.
+// Unlike IntervalsFromEvents_NodeChanges, these are based on the node annotations instead of events.
+func IntervalsFromEvents_MachineConfigNodeUpdates(events monitorapi.Intervals, _ monitorapi.ResourcesMap, beginning, end time.Time) monitorapi.Intervals {
+	var intervals monitorapi.Intervals
+	nodeToOpenPhases := map[string]map[string]time.Time{}
+	nodeNameToRoles := map[string]string{}
+
+	openPhase := func(node, phase string, from time.Time) {
+		if _, ok := nodeToOpenPhases[node][phase]; !ok {
+			nodeToOpenPhases[node][phase] = from
+		}
+	}
+	closePhase := func(node, phase string, level monitorapi.EventLevel, to time.Time) {
+		from, ok := nodeToOpenPhases[node][phase]
+		if !ok {
+			return
+		}
+		delete(nodeToOpenPhases[node], phase)
+		intervals = append(intervals, monitorapi.EventInterval{
+			Condition: monitorapi.Condition{
+				Level:   level,
+				Locator: monitorapi.NodeLocator(node),
+				Message: fmt.Sprintf("reason/%s phase/%s roles/%s", monitorapi.NodeReasonMachineConfigNodeUpdate, phase, nodeNameToRoles[node]),
+			},
+			From: from,
+			To:   to,
+		})
+	}
+
+	for _, event := range events {
+		node, ok := monitorapi.NodeFromLocator(event.Locator)
+		if !ok {
+			continue
+		}
+		// only the node itself, not devices or units on it
+		if event.Locator != monitorapi.NodeLocator(node) {
+			continue
+		}
+		if _, ok := nodeToOpenPhases[node]; !ok {
+			nodeToOpenPhases[node] = map[string]time.Time{}
+		}
+		if roles := monitorapi.GetNodeRoles(event); len(roles) > 0 {
+			nodeNameToRoles[node] = roles
+		}
+
+		annotations := monitorapi.AnnotationsFromMessage(event.Message)
+		switch monitorapi.ReasonFrom(event.Message) {
+		case monitorapi.NodeReasonMachineConfigState:
+			switch annotations["state"] {
+			case "Working":
+				openPhase(node, "Applying", event.From)
+			case "Done":
+				closePhase(node, "Rebooting", monitorapi.Info, event.From)
+				closePhase(node, "Applying", monitorapi.Info, event.From)
+			case "Degraded", "Unreconcilable":
+				closePhase(node, "Rebooting", monitorapi.Error, event.From)
+				closePhase(node, "Applying", monitorapi.Error, event.From)
+			}
+		case monitorapi.NodeReasonMachineConfigDrainRequested:
+			openPhase(node, "Draining", event.From)
+		case monitorapi.NodeReasonMachineConfigDrainComplete:
+			closePhase(node, "Draining", monitorapi.Info, event.From)
+		}
+
+		// node readiness changes while a config is being applied are the reboot.
+		if annotations["condition"] == "Ready" {
+			_, applying := nodeToOpenPhases[node]["Applying"]
+			switch {
+			case annotations["status"] != "True" && applying:
+				openPhase(node, "Rebooting", event.From)
+			case annotations["status"] == "True":
+				closePhase(node, "Rebooting", monitorapi.Info, event.From)
+			}
+		}
+	}
+
+	nodes := []string{}
+	for node := range nodeToOpenPhases {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+	for _, node := range nodes {
+		phases := []string{}
+		for phase := range nodeToOpenPhases[node] {
+			phases = append(phases, phase)
+		}
+		sort.Strings(phases)
+		for _, phase := range phases {
+			closePhase(node, phase, monitorapi.Warning, end)
+		}
+	}
+
+	return intervals
+}
+
+// IntervalsFromEvents_ClusterVersionUpdates creates an interval for every ClusterVersion update observed, from the
+// history entry being added until it completed or was replaced by another update.
+func IntervalsFromEvents_ClusterVersionUpdates(events monitorapi.Intervals, _ monitorapi.ResourcesMap, beginning, end time.Time) monitorapi.Intervals {
+	var intervals monitorapi.Intervals
+	type openUpdate struct {
+		from    time.Time
+		fromTo  string
+		locator string
+	}
+	var current *openUpdate
+
+	for _, event := range events {
+		if !monitorapi.IsClusterVersion(event.Locator) {
+			continue
+		}
+		annotations := monitorapi.AnnotationsFromMessage(event.Message)
+		fromTo := fmt.Sprintf("from/%s to/%s", annotations["from"], annotations["to"])
+
+		switch monitorapi.ReasonFrom(event.Message) {
+		case monitorapi.ClusterVersionReasonUpdateStarted:
+			if current != nil {
+				intervals = append(intervals, monitorapi.EventInterval{
+					Condition: monitorapi.Condition{
+						Level:   monitorapi.Warning,
+						Locator: current.locator,
+						Message: monitorapi.ReasonedMessage(monitorapi.ClusterVersionReasonUpdate, current.fromTo+" replaced by another update before completing"),
+					},
+					From: current.from,
+					To:   event.From,
+				})
+			}
+			current = &openUpdate{from: event.From, fromTo: fromTo, locator: event.Locator}
+
+		case monitorapi.ClusterVersionReasonUpdateCompleted:
+			from := beginning
+			if current != nil {
+				from = current.from
+			}
+			intervals = append(intervals, monitorapi.EventInterval{
+				Condition: monitorapi.Condition{
+					Level:   monitorapi.Info,
+					Locator: event.Locator,
+					Message: monitorapi.ReasonedMessage(monitorapi.ClusterVersionReasonUpdate, fromTo+" completed"),
+				},
+				From: from,
+				To:   event.From,
+			})
+			current = nil
+		}
+	}
+
+	if current != nil {
+		intervals = append(intervals, monitorapi.EventInterval{
+			Condition: monitorapi.Condition{
+				Level:   monitorapi.Warning,
+				Locator: current.locator,
+				Message: monitorapi.ReasonedMessage(monitorapi.ClusterVersionReasonUpdate, current.fromTo+" never completed"),
+			},
+			From: current.from,
+			To:   end,
+		})
+	}
+
+	return intervals
+}
+
+// BelongsInMachineConfig selects the intervals about cluster version updates and MCO rollouts.
+func BelongsInMachineConfig(eventInterval monitorapi.EventInterval) bool {
+	switch {
+	case monitorapi.IsClusterVersion(eventInterval.Locator):
+		return true
+	case monitorapi.IsMachineConfigPool(eventInterval.Locator):
+		return true
+	case eventInterval.Locator == monitorapi.OperatorLocator("machine-config"):
+		return true
+	case monitorapi.IsNode(eventInterval.Locator):
+		reason := monitorapi.ReasonFrom(eventInterval.Message)
+		return strings.HasPrefix(reason, "MachineConfig") || reason == "NodeUpdate" || strings.Contains(eventInterval.Message, "node is not ready")
+	}
+	return false
+}
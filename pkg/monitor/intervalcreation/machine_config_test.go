@@ -0,0 +1,89 @@
+package intervalcreation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func instant(locator, message string, at time.Time) monitorapi.EventInterval {
+	return monitorapi.EventInterval{
+		Condition: monitorapi.Condition{Level: monitorapi.Info, Locator: locator, Message: message},
+		From:      at,
+		To:        at,
+	}
+}
+
+func TestIntervalsFromEvents_MachineConfigNodeUpdates(t *testing.T) {
+	start := timeFor("2022-07-05T17:00:00Z")
+	events := monitorapi.Intervals{
+		instant("node/worker-0", "reason/MachineConfigState state/Working config/rendered-worker-2 roles/worker", start),
+		instant("node/worker-0", "reason/MachineConfigDrainRequested drain/drain-rendered-worker-2 roles/worker drain requested", start.Add(time.Minute)),
+		instant("node/worker-0", "reason/MachineConfigDrainComplete drain/drain-rendered-worker-2 roles/worker drain complete", start.Add(3*time.Minute)),
+		instant("node/worker-0", "condition/Ready status/Unknown reason/NodeStatusUnknown roles/worker changed", start.Add(4*time.Minute)),
+		instant("node/worker-0", "condition/Ready status/True reason/KubeletReady roles/worker changed", start.Add(6*time.Minute)),
+		instant("node/worker-0", "reason/MachineConfigState state/Done config/rendered-worker-2 roles/worker", start.Add(7*time.Minute)),
+		instant("node/worker-1", "reason/MachineConfigState state/Working config/rendered-worker-2 roles/worker", start.Add(8*time.Minute)),
+	}
+
+	actual := IntervalsFromEvents_MachineConfigNodeUpdates(events, nil, start, start.Add(10*time.Minute))
+	expected := []struct {
+		locator  string
+		message  string
+		duration time.Duration
+	}{
+		{"node/worker-0", "reason/MachineConfigNodeUpdate phase/Draining roles/worker", 2 * time.Minute},
+		{"node/worker-0", "reason/MachineConfigNodeUpdate phase/Rebooting roles/worker", 2 * time.Minute},
+		{"node/worker-0", "reason/MachineConfigNodeUpdate phase/Applying roles/worker", 7 * time.Minute},
+		{"node/worker-1", "reason/MachineConfigNodeUpdate phase/Applying roles/worker", 2 * time.Minute},
+	}
+	if len(actual) != len(expected) {
+		t.Fatalf("unexpected intervals: %v", actual.Strings())
+	}
+	for i := range expected {
+		if actual[i].Locator != expected[i].locator || actual[i].Message != expected[i].message || actual[i].To.Sub(actual[i].From) != expected[i].duration {
+			t.Errorf("unexpected interval %d: %v", i, actual[i].String())
+		}
+	}
+	if actual[3].Level != monitorapi.Warning {
+		t.Errorf("expected an unfinished update to be a warning: %v", actual[3].String())
+	}
+}
+
+func TestIntervalsFromEvents_MachineConfigPoolUpdating(t *testing.T) {
+	start := timeFor("2022-07-05T17:00:00Z")
+	events := monitorapi.Intervals{
+		instant("machineconfigpool/worker", "condition/Updating status/True reason/ changed: All nodes are updating to rendered-worker-2", start),
+		instant("machineconfigpool/worker", "condition/Updated status/False reason/ changed: ", start),
+		instant("machineconfigpool/worker", "condition/Updating status/False reason/ changed: ", start.Add(20*time.Minute)),
+	}
+
+	actual := IntervalsFromEvents_MachineConfigPoolUpdating(events, nil, start, start.Add(time.Hour))
+	if len(actual) != 1 {
+		t.Fatalf("unexpected intervals: %v", actual.Strings())
+	}
+	if actual[0].Message != "reason/MachineConfigPoolUpdating All nodes are updating to rendered-worker-2" || actual[0].To.Sub(actual[0].From) != 20*time.Minute {
+		t.Errorf("unexpected interval: %v", actual[0].String())
+	}
+}
+
+func TestIntervalsFromEvents_ClusterVersionUpdates(t *testing.T) {
+	start := timeFor("2022-07-05T17:00:00Z")
+	events := monitorapi.Intervals{
+		instant("clusterversion/version", "reason/UpdateStarted from/4.10.20 to/4.11.0 started update", start),
+		instant("clusterversion/version", "reason/UpdateCompleted from/4.10.20 to/4.11.0 completed update", start.Add(time.Hour)),
+		instant("clusterversion/version", "reason/UpdateStarted from/4.11.0 to/4.11.1 started update", start.Add(2*time.Hour)),
+	}
+
+	actual := IntervalsFromEvents_ClusterVersionUpdates(events, nil, start, start.Add(3*time.Hour))
+	if len(actual) != 2 {
+		t.Fatalf("unexpected intervals: %v", actual.Strings())
+	}
+	if actual[0].Message != "reason/ClusterVersionUpdate from/4.10.20 to/4.11.0 completed" || actual[0].To.Sub(actual[0].From) != time.Hour {
+		t.Errorf("unexpected interval: %v", actual[0].String())
+	}
+	if actual[1].Message != "reason/ClusterVersionUpdate from/4.11.0 to/4.11.1 never completed" || actual[1].Level != monitorapi.Warning {
+		t.Errorf("unexpected interval: %v", actual[1].String())
+	}
+}
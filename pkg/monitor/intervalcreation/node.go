@@ -122,20 +122,29 @@ func IntervalsFromNodeLogs(ctx context.Context, kubeClient kubernetes.Interface,
 
 	collectionStart := time.Now()
 	lock := sync.Mutex{}
-	errCh := make(chan error, len(allNodes.Items))
+	errCh := make(chan error, len(allNodes.Items)*len(defaultNodeLogParsers()))
 	wg := sync.WaitGroup{}
 	for _, node := range allNodes.Items {
 		wg.Add(1)
 		go func(ctx context.Context, nodeName string) {
 			defer wg.Done()
 
-			// TODO limit by begin/end here instead of post-processing
-			nodeLogs, err := nodedetails.GetNodeLog(ctx, kubeClient, nodeName, "kubelet")
-			if err != nil {
-				errCh <- err
-				return
+			newEvents := monitorapi.Intervals{}
+			for _, parser := range defaultNodeLogParsers() {
+				// TODO limit by begin/end here instead of post-processing
+				nodeLogs, err := nodedetails.GetNodeLog(ctx, kubeClient, nodeName, parser.unit)
+				if err != nil {
+					err = fmt.Errorf("failed reading %q logs on node/%s: %w", parser.unit, nodeName, err)
+					if parser.bestEffort {
+						fmt.Fprintf(os.Stderr, "Skipping node logs: %v\n", err)
+						continue
+					}
+					errCh <- err
+					continue
+				}
+				newEvents = append(newEvents, parser.parse(nodeName, nodeLogs)...)
+				newEvents = append(newEvents, intervalsFromSystemdLogs(nodeName, nodeLogs)...)
 			}
-			newEvents := eventsFromKubeletLogs(nodeName, nodeLogs)
 
 			lock.Lock()
 			defer lock.Unlock()
@@ -152,7 +161,16 @@ func IntervalsFromNodeLogs(ctx context.Context, kubeClient kubernetes.Interface,
 		errs = append(errs, err)
 	}
 
-	return ret, utilerrors.NewAggregate(errs)
+	return cutToRun(ret, beginning, end), utilerrors.NewAggregate(errs)
+}
+
+// cutToRun drops the intervals that are entirely outside of the run, like the ones read from logs written before it
+// started.  Nothing is dropped when the run is not known.
+func cutToRun(intervals monitorapi.Intervals, beginning, end time.Time) monitorapi.Intervals {
+	if beginning.IsZero() || end.IsZero() || len(intervals) == 0 {
+		return intervals
+	}
+	return intervals.Cut(beginning, end)
 }
 
 // eventsFromKubeletLogs returns the produced intervals.  Any errors during this creation are logged, but
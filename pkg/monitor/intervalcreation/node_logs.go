@@ -0,0 +1,225 @@
+package intervalcreation
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// nodeLogParser reads the journal of a single systemd unit on a node and produces intervals from it.
+type nodeLogParser struct {
+	// unit is the systemd unit whose journal is read from each node.
+	unit string
+	// parse produces intervals from the entire journal of the unit.  Problems are logged, not returned,
+	// because this is a best effort step.
+	parse func(nodeName string, logBytes []byte) monitorapi.Intervals
+	// bestEffort units are logged when they cannot be read instead of failing the collection, so that a missing
+	// journal does not discard the intervals from every other source.
+	bestEffort bool
+}
+
+// defaultNodeLogParsers is the list of units we read from every node.  systemd unit restarts are detected on
+// all of these journals since systemd logs about a unit into that unit's journal.
+func defaultNodeLogParsers() []nodeLogParser {
+	return []nodeLogParser{
+		{unit: "kubelet", parse: eventsFromKubeletLogs},
+		{unit: "crio", parse: intervalsFromCRIOLogs, bestEffort: true},
+		{unit: "NetworkManager", parse: intervalsFromNetworkManagerLogs, bestEffort: true},
+		{unit: "ovs-vswitchd", parse: intervalsFromOVSLogs, bestEffort: true},
+	}
+}
+
+var (
+	crioSandboxCreatedRegex = regexp.MustCompile(`msg="Ran pod sandbox (?P<ID>[0-9a-f]+) with infra container: (?P<NS>[a-z0-9.-]+)/(?P<POD>[a-z0-9.-]+)/POD"`)
+	crioSandboxStoppedRegex = regexp.MustCompile(`msg="Stopped pod sandbox(?: \(already stopped\))?: (?P<ID>[0-9a-f]+)"`)
+)
+
+// intervalsFromCRIOLogs produces an instant for every pod sandbox creation and destruction.  CRI-O only logs the
+// pod for a sandbox on creation, so destruction of sandboxes created before the journal begins cannot be attributed.
+func intervalsFromCRIOLogs(nodeName string, crioLog []byte) monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+	sandboxIDToLocator := map[string]string{}
+
+	scanner := bufio.NewScanner(bytes.NewBuffer(crioLog))
+	for scanner.Scan() {
+		currLine := scanner.Text()
+		if subMatches := crioSandboxCreatedRegex.FindStringSubmatch(currLine); subMatches != nil {
+			sandboxID := subMatches[crioSandboxCreatedRegex.SubexpIndex("ID")]
+			locator := fmt.Sprintf("ns/%s pod/%s node/%s",
+				subMatches[crioSandboxCreatedRegex.SubexpIndex("NS")],
+				subMatches[crioSandboxCreatedRegex.SubexpIndex("POD")],
+				nodeName)
+			sandboxIDToLocator[sandboxID] = locator
+
+			createTime := kubeletLogTime(currLine)
+			ret = append(ret, monitorapi.EventInterval{
+				Condition: monitorapi.Condition{
+					Level:   monitorapi.Info,
+					Locator: locator,
+					Message: monitorapi.ReasonedMessagef(monitorapi.PodReasonSandboxCreated, "sandbox/%s created", sandboxID),
+				},
+				From: createTime,
+				To:   createTime,
+			})
+			continue
+		}
+
+		if subMatches := crioSandboxStoppedRegex.FindStringSubmatch(currLine); subMatches != nil {
+			sandboxID := subMatches[crioSandboxStoppedRegex.SubexpIndex("ID")]
+			locator, ok := sandboxIDToLocator[sandboxID]
+			if !ok {
+				continue
+			}
+			// only report the first stop, CRI-O is asked to stop the same sandbox several times.
+			delete(sandboxIDToLocator, sandboxID)
+
+			stopTime := kubeletLogTime(currLine)
+			ret = append(ret, monitorapi.EventInterval{
+				Condition: monitorapi.Condition{
+					Level:   monitorapi.Info,
+					Locator: locator,
+					Message: monitorapi.ReasonedMessagef(monitorapi.PodReasonSandboxDestroyed, "sandbox/%s stopped", sandboxID),
+				},
+				From: stopTime,
+				To:   stopTime,
+			})
+		}
+	}
+
+	return ret
+}
+
+var (
+	networkManagerCarrierRegex = regexp.MustCompile(`device \((?P<DEVICE>[^)]+)\): carrier: link (?P<STATE>connected|disconnected)`)
+	networkManagerUnavailable  = regexp.MustCompile(`device \((?P<DEVICE>[^)]+)\): state change: \S+ -> unavailable \(reason 'carrier-changed'`)
+	ovsLinkStateRegex          = regexp.MustCompile(`\|(?:member|interface) (?P<DEVICE>[^:\s]+): link state (?P<STATE>up|down)`)
+)
+
+// intervalsFromNetworkManagerLogs produces an interval for every time a link lost carrier on the node.
+func intervalsFromNetworkManagerLogs(nodeName string, networkManagerLog []byte) monitorapi.Intervals {
+	return linkFlapIntervals(nodeName, "NetworkManager", networkManagerLog, func(logLine string) (string, bool, bool) {
+		if subMatches := networkManagerCarrierRegex.FindStringSubmatch(logLine); subMatches != nil {
+			device := subMatches[networkManagerCarrierRegex.SubexpIndex("DEVICE")]
+			return device, subMatches[networkManagerCarrierRegex.SubexpIndex("STATE")] == "connected", true
+		}
+		if subMatches := networkManagerUnavailable.FindStringSubmatch(logLine); subMatches != nil {
+			return subMatches[networkManagerUnavailable.SubexpIndex("DEVICE")], false, true
+		}
+		return "", false, false
+	})
+}
+
+// intervalsFromOVSLogs produces an interval for every time an OVS port reported its link down.
+func intervalsFromOVSLogs(nodeName string, ovsLog []byte) monitorapi.Intervals {
+	return linkFlapIntervals(nodeName, "ovs-vswitchd", ovsLog, func(logLine string) (string, bool, bool) {
+		subMatches := ovsLinkStateRegex.FindStringSubmatch(logLine)
+		if subMatches == nil {
+			return "", false, false
+		}
+		return subMatches[ovsLinkStateRegex.SubexpIndex("DEVICE")], subMatches[ovsLinkStateRegex.SubexpIndex("STATE")] == "up", true
+	})
+}
+
+// linkStateFunc returns the device, whether the link is up, and whether the line described a link state at all.
+type linkStateFunc func(logLine string) (device string, up bool, ok bool)
+
+func linkFlapIntervals(nodeName, source string, logBytes []byte, linkState linkStateFunc) monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+	deviceToDownTime := map[string]time.Time{}
+	lastLogTime := time.Time{}
+
+	scanner := bufio.NewScanner(bytes.NewBuffer(logBytes))
+	for scanner.Scan() {
+		currLine := scanner.Text()
+		device, up, ok := linkState(currLine)
+		if !ok {
+			continue
+		}
+		lastLogTime = kubeletLogTime(currLine)
+
+		downTime, isDown := deviceToDownTime[device]
+		switch {
+		case !up && !isDown:
+			deviceToDownTime[device] = lastLogTime
+		case up && isDown:
+			delete(deviceToDownTime, device)
+			ret = append(ret, monitorapi.EventInterval{
+				Condition: monitorapi.Condition{
+					Level:   monitorapi.Warning,
+					Locator: monitorapi.NodeDeviceLocator(nodeName, device),
+					Message: monitorapi.ReasonedMessagef(monitorapi.NodeReasonLinkDown, "source/%s link was down", source),
+				},
+				From: downTime,
+				To:   lastLogTime,
+			})
+		}
+	}
+
+	// links that never came back up are held open until the last link change we saw.
+	devices := []string{}
+	for device := range deviceToDownTime {
+		devices = append(devices, device)
+	}
+	sort.Strings(devices)
+	for _, device := range devices {
+		ret = append(ret, monitorapi.EventInterval{
+			Condition: monitorapi.Condition{
+				Level:   monitorapi.Error,
+				Locator: monitorapi.NodeDeviceLocator(nodeName, device),
+				Message: monitorapi.ReasonedMessagef(monitorapi.NodeReasonLinkDown, "source/%s link did not come back up", source),
+			},
+			From: deviceToDownTime[device],
+			To:   lastLogTime,
+		})
+	}
+
+	return ret
+}
+
+var (
+	systemdRestartRegex = regexp.MustCompile(`systemd\[1\]: (?P<UNIT>[a-zA-Z0-9@._-]+\.service): Scheduled restart job, restart counter is at (?P<COUNT>\d+)`)
+	systemdFailedRegex  = regexp.MustCompile(`systemd\[1\]: (?P<UNIT>[a-zA-Z0-9@._-]+\.service): Failed with result '(?P<RESULT>[^']+)'`)
+)
+
+// intervalsFromSystemdLogs produces an instant for every failure and restart systemd reports for a unit.  Node
+// reboots are already covered by the NodeUpdate intervals, so clean stops and starts are not reported.
+func intervalsFromSystemdLogs(nodeName string, unitLog []byte) monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+
+	scanner := bufio.NewScanner(bytes.NewBuffer(unitLog))
+	for scanner.Scan() {
+		currLine := scanner.Text()
+		if subMatches := systemdFailedRegex.FindStringSubmatch(currLine); subMatches != nil {
+			failTime := kubeletLogTime(currLine)
+			ret = append(ret, monitorapi.EventInterval{
+				Condition: monitorapi.Condition{
+					Level:   monitorapi.Warning,
+					Locator: monitorapi.NodeUnitLocator(nodeName, subMatches[systemdFailedRegex.SubexpIndex("UNIT")]),
+					Message: monitorapi.ReasonedMessagef(monitorapi.NodeReasonUnitFailed, "result/%s", subMatches[systemdFailedRegex.SubexpIndex("RESULT")]),
+				},
+				From: failTime,
+				To:   failTime,
+			})
+			continue
+		}
+		if subMatches := systemdRestartRegex.FindStringSubmatch(currLine); subMatches != nil {
+			restartTime := kubeletLogTime(currLine)
+			ret = append(ret, monitorapi.EventInterval{
+				Condition: monitorapi.Condition{
+					Level:   monitorapi.Warning,
+					Locator: monitorapi.NodeUnitLocator(nodeName, subMatches[systemdRestartRegex.SubexpIndex("UNIT")]),
+					Message: monitorapi.ReasonedMessagef(monitorapi.NodeReasonUnitRestarted, "restartCount/%s", subMatches[systemdRestartRegex.SubexpIndex("COUNT")]),
+				},
+				From: restartTime,
+				To:   restartTime,
+			})
+		}
+	}
+
+	return ret
+}
@@ -0,0 +1,70 @@
+package intervalcreation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func Test_intervalsFromCRIOLogs(t *testing.T) {
+	crioLog := `Jul 05 17:40:01.000000 master-0 crio[1334]: time="2022-07-05 17:40:01.000000000Z" level=info msg="Ran pod sandbox 5b3c7d0bd2e4 with infra container: openshift-etcd/etcd-guard-master-0/POD" id=0c5a3c2a name=/runtime.v1.RuntimeService/RunPodSandbox
+Jul 05 17:40:02.000000 master-0 crio[1334]: time="2022-07-05 17:40:02.000000000Z" level=info msg="Stopped pod sandbox: 0123456789ab" id=aa name=/runtime.v1.RuntimeService/StopPodSandbox
+Jul 05 17:45:01.000000 master-0 crio[1334]: time="2022-07-05 17:45:01.000000000Z" level=info msg="Stopped pod sandbox: 5b3c7d0bd2e4" id=bb name=/runtime.v1.RuntimeService/StopPodSandbox
+Jul 05 17:45:02.000000 master-0 crio[1334]: time="2022-07-05 17:45:02.000000000Z" level=info msg="Stopped pod sandbox (already stopped): 5b3c7d0bd2e4" id=cc name=/runtime.v1.RuntimeService/StopPodSandbox
+`
+	actual := intervalsFromCRIOLogs("master-0", []byte(crioLog))
+	if len(actual) != 2 {
+		t.Fatalf("unexpected intervals: %v", actual.Strings())
+	}
+	if actual[0].Locator != "ns/openshift-etcd pod/etcd-guard-master-0 node/master-0" {
+		t.Errorf("unexpected locator: %v", actual[0].Locator)
+	}
+	if actual[0].Message != "reason/SandboxCreated sandbox/5b3c7d0bd2e4 created" {
+		t.Errorf("unexpected message: %v", actual[0].Message)
+	}
+	if actual[1].Message != "reason/SandboxDestroyed sandbox/5b3c7d0bd2e4 stopped" {
+		t.Errorf("unexpected message: %v", actual[1].Message)
+	}
+	if actual[1].From.Sub(actual[0].From) != 5*time.Minute {
+		t.Errorf("unexpected times: %v", actual.Strings())
+	}
+}
+
+func Test_intervalsFromNetworkManagerLogs(t *testing.T) {
+	networkManagerLog := `Jul 05 17:40:00.000000 worker-0 NetworkManager[1102]: <info>  [1657042800.0000] device (ens3): carrier: link connected
+Jul 05 17:40:10.000000 worker-0 NetworkManager[1102]: <info>  [1657042810.0000] device (ens3): state change: activated -> unavailable (reason 'carrier-changed', sys-iface-state: 'managed')
+Jul 05 17:40:20.000000 worker-0 NetworkManager[1102]: <info>  [1657042820.0000] device (ens3): carrier: link connected
+Jul 05 17:41:00.000000 worker-0 NetworkManager[1102]: <info>  [1657042860.0000] device (ens4): carrier: link disconnected
+`
+	actual := intervalsFromNetworkManagerLogs("worker-0", []byte(networkManagerLog))
+	if len(actual) != 2 {
+		t.Fatalf("unexpected intervals: %v", actual.Strings())
+	}
+	if actual[0].Locator != "node/worker-0 device/ens3" || actual[0].Level != monitorapi.Warning {
+		t.Errorf("unexpected interval: %v", actual[0].String())
+	}
+	if actual[0].To.Sub(actual[0].From) != 10*time.Second {
+		t.Errorf("unexpected duration: %v", actual[0].String())
+	}
+	if actual[1].Locator != "node/worker-0 device/ens4" || actual[1].Level != monitorapi.Error {
+		t.Errorf("unexpected interval: %v", actual[1].String())
+	}
+}
+
+func Test_intervalsFromSystemdLogs(t *testing.T) {
+	kubeletLog := `Jul 05 17:40:00.000000 master-0 systemd[1]: kubelet.service: Main process exited, code=exited, status=255/EXCEPTION
+Jul 05 17:40:00.100000 master-0 systemd[1]: kubelet.service: Failed with result 'exit-code'.
+Jul 05 17:40:10.000000 master-0 systemd[1]: kubelet.service: Scheduled restart job, restart counter is at 1.
+`
+	actual := intervalsFromSystemdLogs("master-0", []byte(kubeletLog))
+	if len(actual) != 2 {
+		t.Fatalf("unexpected intervals: %v", actual.Strings())
+	}
+	if actual[0].Locator != "node/master-0 unit/kubelet.service" || actual[0].Message != "reason/UnitFailed result/exit-code" {
+		t.Errorf("unexpected interval: %v", actual[0].String())
+	}
+	if actual[1].Message != "reason/UnitRestarted restartCount/1" {
+		t.Errorf("unexpected interval: %v", actual[1].String())
+	}
+}
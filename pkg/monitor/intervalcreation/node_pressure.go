@@ -0,0 +1,81 @@
+package intervalcreation
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// IntervalsFromEvents_NodePressure creates an interval for every time a node reported MemoryPressure, DiskPressure or
+// PIDPressure, from when the condition became true until it became false or the run ended.
+func IntervalsFromEvents_NodePressure(events monitorapi.Intervals, _ monitorapi.ResourcesMap, beginning, end time.Time) monitorapi.Intervals {
+	var intervals monitorapi.Intervals
+	type openPressure struct {
+		from   time.Time
+		reason string
+		roles  string
+	}
+	nodeToOpenPressure := map[string]map[corev1.NodeConditionType]openPressure{}
+
+	closePressure := func(node string, conditionType corev1.NodeConditionType, to time.Time) {
+		open, ok := nodeToOpenPressure[node][conditionType]
+		if !ok {
+			return
+		}
+		delete(nodeToOpenPressure[node], conditionType)
+		intervals = append(intervals, monitorapi.EventInterval{
+			Condition: monitorapi.Condition{
+				Level:   monitorapi.Warning,
+				Locator: monitorapi.NodeLocator(node),
+				Message: fmt.Sprintf("reason/%s condition/%s kubeletReason/%s roles/%s", monitorapi.NodeReasonResourcePressure, conditionType, open.reason, open.roles),
+			},
+			From: open.from,
+			To:   to,
+		})
+	}
+
+	for _, event := range events {
+		node, ok := monitorapi.NodeFromLocator(event.Locator)
+		if !ok || event.Locator != monitorapi.NodeLocator(node) {
+			continue
+		}
+		annotations := monitorapi.AnnotationsFromMessage(event.Message)
+		conditionType := corev1.NodeConditionType(annotations["condition"])
+		if !monitorapi.IsNodePressureCondition(conditionType) {
+			continue
+		}
+
+		switch corev1.ConditionStatus(annotations["status"]) {
+		case corev1.ConditionTrue:
+			if _, ok := nodeToOpenPressure[node][conditionType]; ok {
+				continue
+			}
+			if _, ok := nodeToOpenPressure[node]; !ok {
+				nodeToOpenPressure[node] = map[corev1.NodeConditionType]openPressure{}
+			}
+			nodeToOpenPressure[node][conditionType] = openPressure{
+				from:   event.From,
+				reason: annotations["reason"],
+				roles:  monitorapi.GetNodeRoles(event),
+			}
+		case corev1.ConditionFalse, corev1.ConditionUnknown:
+			closePressure(node, conditionType, event.From)
+		}
+	}
+
+	nodes := []string{}
+	for node := range nodeToOpenPressure {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+	for _, node := range nodes {
+		for _, conditionType := range monitorapi.NodePressureConditions {
+			closePressure(node, conditionType, end)
+		}
+	}
+
+	return intervals
+}
@@ -0,0 +1,38 @@
+package intervalcreation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestIntervalsFromEvents_NodePressure(t *testing.T) {
+	start := timeFor("2022-07-05T17:00:00Z")
+	events := monitorapi.Intervals{
+		instant("node/master-0", "condition/MemoryPressure status/True reason/KubeletHasInsufficientMemory roles/master observed", start),
+		instant("node/master-0", "condition/Ready status/False reason/KubeletNotReady roles/master changed", start.Add(time.Minute)),
+		instant("node/master-0", "condition/MemoryPressure status/True reason/KubeletHasInsufficientMemory roles/master changed", start.Add(2*time.Minute)),
+		instant("node/master-0", "condition/MemoryPressure status/False reason/KubeletHasSufficientMemory roles/master changed", start.Add(3*time.Minute)),
+		instant("node/worker-0", "condition/DiskPressure status/True reason/KubeletHasDiskPressure roles/worker changed", start.Add(4*time.Minute)),
+		instant("node/worker-0 unit/kubelet.service", "condition/DiskPressure status/False reason/Unrelated", start.Add(5*time.Minute)),
+	}
+
+	actual := IntervalsFromEvents_NodePressure(events, nil, start, start.Add(10*time.Minute))
+	expected := []struct {
+		locator  string
+		message  string
+		duration time.Duration
+	}{
+		{"node/master-0", "reason/NodeResourcePressure condition/MemoryPressure kubeletReason/KubeletHasInsufficientMemory roles/master", 3 * time.Minute},
+		{"node/worker-0", "reason/NodeResourcePressure condition/DiskPressure kubeletReason/KubeletHasDiskPressure roles/worker", 6 * time.Minute},
+	}
+	if len(actual) != len(expected) {
+		t.Fatalf("unexpected intervals: %v", actual.Strings())
+	}
+	for i := range expected {
+		if actual[i].Locator != expected[i].locator || actual[i].Message != expected[i].message || actual[i].To.Sub(actual[i].From) != expected[i].duration {
+			t.Errorf("unexpected interval %d: %v", i, actual[i].String())
+		}
+	}
+}
@@ -158,3 +158,27 @@ func Test_readinessFailure(t *testing.T) {
 		})
 	}
 }
+
+func Test_cutToRun(t *testing.T) {
+	beginning := time.Date(2022, 7, 5, 17, 0, 0, 0, time.UTC)
+	end := beginning.Add(time.Hour)
+	instant := func(at time.Time) monitorapi.EventInterval {
+		return monitorapi.EventInterval{
+			Condition: monitorapi.Condition{Level: monitorapi.Info, Locator: "node/worker-0", Message: "reason/Test"},
+			From:      at,
+			To:        at,
+		}
+	}
+	intervals := monitorapi.Intervals{
+		instant(beginning.Add(-time.Hour)),
+		instant(beginning.Add(time.Minute)),
+		instant(end.Add(time.Minute)),
+	}
+
+	if actual := cutToRun(intervals, beginning, end); len(actual) != 1 || !actual[0].From.Equal(beginning.Add(time.Minute)) {
+		t.Errorf("expected only the interval during the run, got %v", actual)
+	}
+	if actual := cutToRun(intervals, time.Time{}, time.Time{}); len(actual) != len(intervals) {
+		t.Errorf("expected every interval without a run, got %v", actual)
+	}
+}
@@ -0,0 +1,95 @@
+package intervalcreation
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+// IntervalsFromEvents_PodDisruptionBudgetViolations creates an interval for every time fewer pods were healthy than
+// a pod disruption budget requires, from the status that dropped below the budget until a status that met it again.
+// The message carries the lowest number of healthy pods seen during the interval.
+func IntervalsFromEvents_PodDisruptionBudgetViolations(events monitorapi.Intervals, _ monitorapi.ResourcesMap, beginning, end time.Time) monitorapi.Intervals {
+	var intervals monitorapi.Intervals
+	type openViolation struct {
+		from           time.Time
+		currentHealthy int
+		desiredHealthy int
+		budget         string
+	}
+	locatorToViolation := map[string]*openViolation{}
+
+	closeViolation := func(locator string, to time.Time) {
+		open, ok := locatorToViolation[locator]
+		if !ok {
+			return
+		}
+		delete(locatorToViolation, locator)
+		intervals = append(intervals, monitorapi.EventInterval{
+			Condition: monitorapi.Condition{
+				Level:   monitorapi.Error,
+				Locator: locator,
+				Message: fmt.Sprintf("reason/%s currentHealthy/%d desiredHealthy/%d%s",
+					monitorapi.PodDisruptionBudgetReasonViolated, open.currentHealthy, open.desiredHealthy, open.budget),
+			},
+			From: open.from,
+			To:   to,
+		})
+	}
+
+	for _, event := range events {
+		if !monitorapi.IsPodDisruptionBudget(event.Locator) {
+			continue
+		}
+		if monitorapi.ReasonFrom(event.Message) != monitorapi.PodDisruptionBudgetReasonStatus {
+			continue
+		}
+		annotations := monitorapi.AnnotationsFromMessage(event.Message)
+		currentHealthy, err := strconv.Atoi(annotations["currentHealthy"])
+		if err != nil {
+			continue
+		}
+		desiredHealthy, err := strconv.Atoi(annotations["desiredHealthy"])
+		if err != nil {
+			continue
+		}
+
+		if currentHealthy >= desiredHealthy {
+			closeViolation(event.Locator, event.From)
+			continue
+		}
+		if open, ok := locatorToViolation[event.Locator]; ok {
+			if currentHealthy < open.currentHealthy {
+				open.currentHealthy = currentHealthy
+			}
+			continue
+		}
+		budget := ""
+		if minAvailable, ok := annotations["minAvailable"]; ok {
+			budget += " minAvailable/" + minAvailable
+		}
+		if maxUnavailable, ok := annotations["maxUnavailable"]; ok {
+			budget += " maxUnavailable/" + maxUnavailable
+		}
+		locatorToViolation[event.Locator] = &openViolation{
+			from:           event.From,
+			currentHealthy: currentHealthy,
+			desiredHealthy: desiredHealthy,
+			budget:         budget,
+		}
+	}
+
+	locators := []string{}
+	for locator := range locatorToViolation {
+		locators = append(locators, locator)
+	}
+	sort.Strings(locators)
+	for _, locator := range locators {
+		closeViolation(locator, end)
+	}
+
+	return intervals
+}
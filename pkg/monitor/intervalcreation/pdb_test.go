@@ -0,0 +1,39 @@
+package intervalcreation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestIntervalsFromEvents_PodDisruptionBudgetViolations(t *testing.T) {
+	start := timeFor("2022-07-05T17:00:00Z")
+	etcd := monitorapi.PodDisruptionBudgetLocator("openshift-etcd", "etcd-quorum-guard")
+	router := monitorapi.PodDisruptionBudgetLocator("openshift-ingress", "router-default")
+	events := monitorapi.Intervals{
+		instant(etcd, "reason/PodDisruptionBudgetStatus currentHealthy/3 desiredHealthy/2 expectedPods/3 disruptionsAllowed/1 maxUnavailable/1", start),
+		instant(etcd, "reason/PodDisruptionBudgetStatus currentHealthy/1 desiredHealthy/2 expectedPods/3 disruptionsAllowed/0 maxUnavailable/1", start.Add(time.Minute)),
+		instant(etcd, "reason/PodDisruptionBudgetStatus currentHealthy/0 desiredHealthy/2 expectedPods/3 disruptionsAllowed/0 maxUnavailable/1", start.Add(2*time.Minute)),
+		instant(etcd, "reason/PodDisruptionBudgetStatus currentHealthy/2 desiredHealthy/2 expectedPods/3 disruptionsAllowed/0 maxUnavailable/1", start.Add(4*time.Minute)),
+		instant(router, "reason/PodDisruptionBudgetStatus currentHealthy/0 desiredHealthy/1 expectedPods/2 disruptionsAllowed/0 minAvailable/50%", start.Add(5*time.Minute)),
+	}
+
+	actual := IntervalsFromEvents_PodDisruptionBudgetViolations(events, nil, start, start.Add(10*time.Minute))
+	expected := []struct {
+		locator  string
+		message  string
+		duration time.Duration
+	}{
+		{etcd, "reason/PodDisruptionBudgetViolated currentHealthy/0 desiredHealthy/2 maxUnavailable/1", 3 * time.Minute},
+		{router, "reason/PodDisruptionBudgetViolated currentHealthy/0 desiredHealthy/1 minAvailable/50%", 5 * time.Minute},
+	}
+	if len(actual) != len(expected) {
+		t.Fatalf("unexpected intervals: %v", actual.Strings())
+	}
+	for i := range expected {
+		if actual[i].Locator != expected[i].locator || actual[i].Message != expected[i].message || actual[i].To.Sub(actual[i].From) != expected[i].duration {
+			t.Errorf("unexpected interval %d: %v", i, actual[i].String())
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package intervalcreation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// SimpleIntervalCreationFunc calculates new intervals from the known intervals and recorded resources.
+type SimpleIntervalCreationFunc func(intervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) monitorapi.Intervals
+
+// ClusterIntervalCreationFunc contacts the cluster and creates intervals from what it finds.
+type ClusterIntervalCreationFunc func(ctx context.Context, kubeClient kubernetes.Interface, intervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) (monitorapi.Intervals, error)
+
+// IntervalCreator is a named interval calculation.  Creators without dependencies are passed the starting
+// intervals.  Creators with dependencies are passed the starting intervals plus the intervals created by each
+// of their dependencies, so they can build on derived intervals like pod lifecycles or node update phases.
+type IntervalCreator struct {
+	Name            string
+	DependsOn       []string
+	CreateIntervals SimpleIntervalCreationFunc
+}
+
+// ClusterIntervalCreator is a named interval creator that reads from the cluster at the end of a run.
+type ClusterIntervalCreator struct {
+	Name            string
+	CreateIntervals ClusterIntervalCreationFunc
+}
+
+// IntervalCreatorRegistry holds the interval creators run at the end of monitoring and when rendering timelines.
+// Creators run in registration order, and a creator may only depend on creators registered before it, so the
+// registration order is always a valid ordering.
+type IntervalCreatorRegistry struct {
+	creators        []IntervalCreator
+	clusterCreators []ClusterIntervalCreator
+}
+
+// NewIntervalCreatorRegistry returns a registry with no creators.
+func NewIntervalCreatorRegistry() *IntervalCreatorRegistry {
+	return &IntervalCreatorRegistry{}
+}
+
+// NewDefaultIntervalCreatorRegistry returns a registry with the creators origin always runs.
+func NewDefaultIntervalCreatorRegistry() *IntervalCreatorRegistry {
+	r := NewIntervalCreatorRegistry()
+	r.MustRegister(IntervalCreator{Name: "operator-available", CreateIntervals: IntervalsFromEvents_OperatorAvailable})
+	r.MustRegister(IntervalCreator{Name: "operator-progressing", CreateIntervals: IntervalsFromEvents_OperatorProgressing})
+	r.MustRegister(IntervalCreator{Name: "operator-degraded", CreateIntervals: IntervalsFromEvents_OperatorDegraded})
+	r.MustRegister(IntervalCreator{Name: "e2e-tests", CreateIntervals: IntervalsFromEvents_E2ETests})
+	r.MustRegister(IntervalCreator{Name: "e2e-test-namespaces", DependsOn: []string{"e2e-tests"}, CreateIntervals: IntervalsFromEvents_E2ETestNamespaces})
+	r.MustRegister(IntervalCreator{Name: "node-changes", CreateIntervals: IntervalsFromEvents_NodeChanges})
+	r.MustRegister(IntervalCreator{Name: "pod-lifecycle", CreateIntervals: CreatePodIntervalsFromInstants})
+	r.MustRegister(IntervalCreator{Name: "machine-config-pools", CreateIntervals: IntervalsFromEvents_MachineConfigPoolUpdating})
+	r.MustRegister(IntervalCreator{Name: "machine-config-nodes", CreateIntervals: IntervalsFromEvents_MachineConfigNodeUpdates})
+	r.MustRegister(IntervalCreator{Name: "node-pressure", CreateIntervals: IntervalsFromEvents_NodePressure})
+	r.MustRegister(IntervalCreator{Name: "cluster-version-updates", CreateIntervals: IntervalsFromEvents_ClusterVersionUpdates})
+	r.MustRegister(IntervalCreator{Name: "lease-holders", CreateIntervals: IntervalsFromEvents_LeaseHolders})
+	r.MustRegister(IntervalCreator{Name: "pod-disruption-budget-violations", CreateIntervals: IntervalsFromEvents_PodDisruptionBudgetViolations})
+	r.MustRegister(IntervalCreator{Name: "certificate-rotations", CreateIntervals: IntervalsFromEvents_CertificateRotations})
+
+	r.MustRegisterFromCluster(ClusterIntervalCreator{Name: "node-logs", CreateIntervals: intervalsFromNodeLogs})
+	r.MustRegisterFromCluster(ClusterIntervalCreator{Name: "static-pod-logs", CreateIntervals: intervalsFromStaticPodLogs})
+	r.MustRegisterFromCluster(ClusterIntervalCreator{Name: "audit-logs", CreateIntervals: intervalsFromAuditLogs})
+	return r
+}
+
+func (r *IntervalCreatorRegistry) names() sets.String {
+	ret := sets.NewString()
+	for _, creator := range r.creators {
+		ret.Insert(creator.Name)
+	}
+	for _, creator := range r.clusterCreators {
+		ret.Insert(creator.Name)
+	}
+	return ret
+}
+
+// Register adds a creator.  Names must be unique and every dependency must already be registered.
+func (r *IntervalCreatorRegistry) Register(creator IntervalCreator) error {
+	if len(creator.Name) == 0 {
+		return fmt.Errorf("interval creator must have a name")
+	}
+	if creator.CreateIntervals == nil {
+		return fmt.Errorf("interval creator %q must have a creation func", creator.Name)
+	}
+	if r.names().Has(creator.Name) {
+		return fmt.Errorf("interval creator %q is already registered", creator.Name)
+	}
+
+	knownCalculated := sets.NewString()
+	for _, existing := range r.creators {
+		knownCalculated.Insert(existing.Name)
+	}
+	for _, dependency := range creator.DependsOn {
+		if !knownCalculated.Has(dependency) {
+			return fmt.Errorf("interval creator %q depends on %q, which must be registered first", creator.Name, dependency)
+		}
+	}
+
+	r.creators = append(r.creators, creator)
+	return nil
+}
+
+// MustRegister is Register, but panics on error.
+func (r *IntervalCreatorRegistry) MustRegister(creator IntervalCreator) {
+	if err := r.Register(creator); err != nil {
+		panic(err)
+	}
+}
+
+// RegisterFromCluster adds a creator that reads from the cluster.  Names must be unique.
+func (r *IntervalCreatorRegistry) RegisterFromCluster(creator ClusterIntervalCreator) error {
+	if len(creator.Name) == 0 {
+		return fmt.Errorf("interval creator must have a name")
+	}
+	if creator.CreateIntervals == nil {
+		return fmt.Errorf("interval creator %q must have a creation func", creator.Name)
+	}
+	if r.names().Has(creator.Name) {
+		return fmt.Errorf("interval creator %q is already registered", creator.Name)
+	}
+
+	r.clusterCreators = append(r.clusterCreators, creator)
+	return nil
+}
+
+// MustRegisterFromCluster is RegisterFromCluster, but panics on error.
+func (r *IntervalCreatorRegistry) MustRegisterFromCluster(creator ClusterIntervalCreator) {
+	if err := r.RegisterFromCluster(creator); err != nil {
+		panic(err)
+	}
+}
+
+// InsertCalculatedIntervals calculates intervals from the currently known interval set and saves them into the same list
+func (r *IntervalCreatorRegistry) InsertCalculatedIntervals(startingIntervals []monitorapi.EventInterval, recordedResources monitorapi.ResourcesMap, from, to time.Time) monitorapi.Intervals {
+	ret := make([]monitorapi.EventInterval, len(startingIntervals))
+	copy(ret, startingIntervals)
+
+	createdIntervals := map[string]monitorapi.Intervals{}
+	for _, creator := range r.creators {
+		input := startingIntervals
+		if len(creator.DependsOn) > 0 {
+			input = make([]monitorapi.EventInterval, len(startingIntervals))
+			copy(input, startingIntervals)
+			for _, dependency := range creator.DependsOn {
+				input = append(input, createdIntervals[dependency]...)
+			}
+			sort.Sort(monitorapi.Intervals(input))
+		}
+
+		created := creator.CreateIntervals(input, recordedResources, from, to)
+		createdIntervals[creator.Name] = created
+		ret = append(ret, created...)
+	}
+
+	// we must sort the result
+	sort.Sort(monitorapi.Intervals(ret))
+
+	return ret
+}
+
+// InsertIntervalsFromCluster contacts the cluster, retrieves information deemed pertinent, and creates intervals for them.
+func (r *IntervalCreatorRegistry) InsertIntervalsFromCluster(ctx context.Context, kubeConfig *rest.Config, startingIntervals []monitorapi.EventInterval, recordedResources monitorapi.ResourcesMap, from, to time.Time) (monitorapi.Intervals, error) {
+	ret := make([]monitorapi.EventInterval, len(startingIntervals))
+	copy(ret, startingIntervals)
+
+	kubeClient, err := kubernetes.NewForConfig(kubeConfig)
+	if err != nil {
+		return ret, err
+	}
+
+	allErrors := []error{}
+	for _, creator := range r.clusterCreators {
+		created, err := creator.CreateIntervals(ctx, kubeClient, startingIntervals, recordedResources, from, to)
+		if err != nil {
+			allErrors = append(allErrors, fmt.Errorf("%s: %w", creator.Name, err))
+		}
+		ret = append(ret, created...)
+	}
+
+	// we must sort the result
+	sort.Sort(monitorapi.Intervals(ret))
+
+	return ret, utilerrors.NewAggregate(allErrors)
+}
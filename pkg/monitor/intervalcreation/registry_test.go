@@ -0,0 +1,67 @@
+package intervalcreation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestIntervalCreatorRegistry(t *testing.T) {
+	start := timeFor("2021-03-29T15:56:00Z")
+	startingIntervals := monitorapi.Intervals{
+		{
+			Condition: monitorapi.Condition{Level: monitorapi.Info, Locator: "node/a", Message: "starting"},
+			From:      start,
+			To:        start,
+		},
+	}
+
+	r := NewIntervalCreatorRegistry()
+	r.MustRegister(IntervalCreator{
+		Name: "first",
+		CreateIntervals: func(intervals monitorapi.Intervals, _ monitorapi.ResourcesMap, _, _ time.Time) monitorapi.Intervals {
+			if len(intervals) != 1 {
+				t.Errorf("first should only see starting intervals, got %v", intervals.Strings())
+			}
+			return monitorapi.Intervals{
+				{
+					Condition: monitorapi.Condition{Level: monitorapi.Info, Locator: "node/a", Message: "first"},
+					From:      start.Add(time.Second),
+					To:        start.Add(time.Second),
+				},
+			}
+		},
+	})
+	r.MustRegister(IntervalCreator{
+		Name:      "second",
+		DependsOn: []string{"first"},
+		CreateIntervals: func(intervals monitorapi.Intervals, _ monitorapi.ResourcesMap, _, _ time.Time) monitorapi.Intervals {
+			if len(intervals) != 2 || intervals[1].Message != "first" {
+				t.Errorf("second should see intervals from first, got %v", intervals.Strings())
+			}
+			return monitorapi.Intervals{
+				{
+					Condition: monitorapi.Condition{Level: monitorapi.Info, Locator: "node/a", Message: "second"},
+					From:      start.Add(2 * time.Second),
+					To:        start.Add(2 * time.Second),
+				},
+			}
+		},
+	})
+
+	actual := r.InsertCalculatedIntervals(startingIntervals, nil, time.Time{}, time.Time{})
+	if len(actual) != 3 || actual[0].Message != "starting" || actual[1].Message != "first" || actual[2].Message != "second" {
+		t.Fatalf("unexpected intervals: %v", actual.Strings())
+	}
+
+	noop := func(intervals monitorapi.Intervals, _ monitorapi.ResourcesMap, _, _ time.Time) monitorapi.Intervals {
+		return nil
+	}
+	if err := r.Register(IntervalCreator{Name: "first", CreateIntervals: noop}); err == nil {
+		t.Errorf("expected duplicate name to fail")
+	}
+	if err := r.Register(IntervalCreator{Name: "third", DependsOn: []string{"missing"}, CreateIntervals: noop}); err == nil {
+		t.Errorf("expected unknown dependency to fail")
+	}
+}
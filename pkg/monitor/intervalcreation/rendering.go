@@ -55,16 +55,12 @@ func (r eventIntervalRenderer) writeEventData(artifactDir, filenameBase string,
 		errs = append(errs, err)
 	}
 
-	eventIntervalsJSON, err := monitorserialization.EventsIntervalsToJSON(interestingEvents)
+	e2eChartHTML, err := E2EChartHTML(fmt.Sprintf("Intervals - %s%s", r.name, timeSuffix), interestingEvents)
 	if err != nil {
 		errs = append(errs, err)
 		return utilerrors.NewAggregate(errs)
 
 	}
-	e2eChartTemplate := testdata.MustAsset("e2echart/e2e-chart-template.html")
-	e2eChartTitle := fmt.Sprintf("Intervals - %s%s", r.name, timeSuffix)
-	e2eChartHTML := bytes.ReplaceAll(e2eChartTemplate, []byte("EVENT_INTERVAL_TITLE_GOES_HERE"), []byte(e2eChartTitle))
-	e2eChartHTML = bytes.ReplaceAll(e2eChartHTML, []byte("EVENT_INTERVAL_JSON_GOES_HERE"), eventIntervalsJSON)
 	e2eChartHTMLPath := filepath.Join(artifactDir, fmt.Sprintf("%s.html", filenameBase))
 	if err := ioutil.WriteFile(e2eChartHTMLPath, e2eChartHTML, 0644); err != nil {
 		errs = append(errs, err)
@@ -73,6 +69,25 @@ func (r eventIntervalRenderer) writeEventData(artifactDir, filenameBase string,
 	return utilerrors.NewAggregate(errs)
 }
 
+// E2EChartHTML renders the events into the spyglass chart template.  The namespace groups are included so the
+// chart can filter by them client-side.
+func E2EChartHTML(title string, events monitorapi.Intervals) ([]byte, error) {
+	eventIntervalsJSON, err := monitorserialization.EventsIntervalsToJSON(events)
+	if err != nil {
+		return nil, err
+	}
+	namespaceGroupsJSON, err := namespaceGroupsToJSON(wellKnownNamespaceGroups())
+	if err != nil {
+		return nil, err
+	}
+
+	e2eChartTemplate := testdata.MustAsset("e2echart/e2e-chart-template.html")
+	e2eChartHTML := bytes.ReplaceAll(e2eChartTemplate, []byte("EVENT_INTERVAL_TITLE_GOES_HERE"), []byte(title))
+	e2eChartHTML = bytes.ReplaceAll(e2eChartHTML, []byte("EVENT_INTERVAL_JSON_GOES_HERE"), eventIntervalsJSON)
+	e2eChartHTML = bytes.ReplaceAll(e2eChartHTML, []byte("NAMESPACE_GROUPS_JSON_GOES_HERE"), namespaceGroupsJSON)
+	return e2eChartHTML, nil
+}
+
 func BelongsInEverything(eventInterval monitorapi.EventInterval) bool {
 	if IsPodLifecycle(eventInterval) { // there are just too many
 		return false
@@ -1,6 +1,7 @@
 package intervalcreation
 
 import (
+	"encoding/json"
 	"strings"
 
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -90,6 +91,35 @@ func wellKnownNamespaceGroups() []relatedNamespaces {
 	}
 }
 
+// NamespaceGroupFor returns the well known namespace group the namespace belongs to.  Namespaces outside of every
+// group belong to e2e-namespaces or everything-else, the same way the chart and WriteRunData group them.
+func NamespaceGroupFor(namespace string) string {
+	for _, nsGroup := range wellKnownNamespaceGroups() {
+		if nsGroup.namespaces.Has(namespace) {
+			return nsGroup.name
+		}
+	}
+	if strings.HasPrefix(namespace, "e2e-") {
+		return "e2e-namespaces"
+	}
+	return "everything-else"
+}
+
+type namespaceGroupJSON struct {
+	Name       string   `json:"name"`
+	Namespaces []string `json:"namespaces"`
+}
+
+// namespaceGroupsToJSON serializes the namespace groups for the chart.  Namespaces outside of every group are
+// grouped into e2e-namespaces and everything-else by the chart itself, the same way WriteRunData does.
+func namespaceGroupsToJSON(namespaceGroups []relatedNamespaces) ([]byte, error) {
+	out := []namespaceGroupJSON{}
+	for _, nsGroup := range namespaceGroups {
+		out = append(out, namespaceGroupJSON{Name: nsGroup.name, Namespaces: nsGroup.namespaces.List()})
+	}
+	return json.Marshal(out)
+}
+
 type podRendering struct {
 	name string
 }
@@ -2,6 +2,7 @@ package intervalcreation
 
 import (
 	_ "embed"
+	"strings"
 	"testing"
 
 	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
@@ -27,3 +28,23 @@ func TestBelongsInKubeAPIServer(t *testing.T) {
 		}
 	}
 }
+
+func TestE2EChartHTML(t *testing.T) {
+	inputIntervals, err := monitorserialization.EventsFromJSON(skipE2e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual, err := E2EChartHTML("Timeline", inputIntervals)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, placeholder := range []string{"EVENT_INTERVAL_TITLE_GOES_HERE", "EVENT_INTERVAL_JSON_GOES_HERE", "NAMESPACE_GROUPS_JSON_GOES_HERE"} {
+		if strings.Contains(string(actual), placeholder) {
+			t.Errorf("%s was not replaced", placeholder)
+		}
+	}
+	if !strings.Contains(string(actual), `{"name":"kube-control-plane","namespaces":[`) {
+		t.Errorf("missing namespace groups")
+	}
+}
@@ -0,0 +1,270 @@
+package intervalcreation
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// staticPodLogParser reads the logs of a container in every static pod matching the selector and produces intervals.
+type staticPodLogParser struct {
+	namespace     string
+	labelSelector string
+	container     string
+	// previous also reads the logs of the previous container instance, which is where shutdowns are logged.
+	previous bool
+	parse    func(pod *corev1.Pod, logBytes []byte) monitorapi.Intervals
+}
+
+func defaultStaticPodLogParsers() []staticPodLogParser {
+	return []staticPodLogParser{
+		{
+			namespace:     "openshift-etcd",
+			labelSelector: "app=etcd",
+			container:     "etcd",
+			parse:         intervalsFromEtcdLogs,
+		},
+		{
+			namespace:     "openshift-kube-apiserver",
+			labelSelector: "app=openshift-kube-apiserver",
+			container:     "kube-apiserver",
+			previous:      true,
+			parse:         intervalsFromKubeAPIServerLogs,
+		},
+	}
+}
+
+// IntervalsFromStaticPodLogs reads the logs of control plane static pods and produces the intervals that overlap the
+// run.  The logs are a best effort source, so pods or logs that cannot be read are logged and never fail the collection.
+func IntervalsFromStaticPodLogs(ctx context.Context, kubeClient kubernetes.Interface, beginning, end time.Time) (monitorapi.Intervals, error) {
+	ret := monitorapi.Intervals{}
+
+	collectionStart := time.Now()
+	lock := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	for _, parser := range defaultStaticPodLogParsers() {
+		pods, err := kubeClient.CoreV1().Pods(parser.namespace).List(ctx, metav1.ListOptions{LabelSelector: parser.labelSelector})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Skipping static pod logs in %s: %v\n", parser.namespace, err)
+			continue
+		}
+
+		for i := range pods.Items {
+			wg.Add(1)
+			go func(ctx context.Context, parser staticPodLogParser, pod *corev1.Pod) {
+				defer wg.Done()
+
+				newEvents := monitorapi.Intervals{}
+				var sinceTime *metav1.Time
+				if !beginning.IsZero() {
+					sinceTime = &metav1.Time{Time: beginning}
+				}
+				logOptions := []*corev1.PodLogOptions{{Container: parser.container, SinceTime: sinceTime}}
+				if parser.previous {
+					logOptions = append(logOptions, &corev1.PodLogOptions{Container: parser.container, Previous: true, SinceTime: sinceTime})
+				}
+				for _, opts := range logOptions {
+					podLogs, err := kubeClient.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, opts).DoRaw(ctx)
+					if err != nil {
+						// there is no previous container for pods that have not restarted.
+						if !opts.Previous {
+							fmt.Fprintf(os.Stderr, "Skipping static pod logs for ns/%s pod/%s: %v\n", pod.Namespace, pod.Name, err)
+						}
+						continue
+					}
+					newEvents = append(newEvents, parser.parse(pod, podLogs)...)
+				}
+
+				lock.Lock()
+				defer lock.Unlock()
+				ret = append(ret, newEvents...)
+			}(ctx, parser, &pods.Items[i])
+		}
+	}
+	wg.Wait()
+	collectionEnd := time.Now()
+	fmt.Fprintf(os.Stderr, "Collection of static pod logs and analysis took: %v\n", collectionEnd.Sub(collectionStart))
+
+	return cutToRun(ret, beginning, end), nil
+}
+
+type etcdLogLine struct {
+	Level string `json:"level"`
+	TS    string `json:"ts"`
+	Msg   string `json:"msg"`
+}
+
+var (
+	etcdLeaderElectedRegex = regexp.MustCompile(`raft\.node: (?P<MEMBER>[0-9a-f]+) elected leader (?P<LEADER>[0-9a-f]+) at term (?P<TERM>\d+)`)
+	etcdLeaderChangedRegex = regexp.MustCompile(`raft\.node: (?P<MEMBER>[0-9a-f]+) changed leader from (?P<OLD>[0-9a-f]+) to (?P<LEADER>[0-9a-f]+) at term (?P<TERM>\d+)`)
+	etcdLeaderLostRegex    = regexp.MustCompile(`raft\.node: (?P<MEMBER>[0-9a-f]+) lost leader (?P<OLD>[0-9a-f]+) at term (?P<TERM>\d+)`)
+)
+
+// intervalsFromEtcdLogs produces an instant for every leader election this member observed and an interval for
+// every time this member had no leader.
+func intervalsFromEtcdLogs(pod *corev1.Pod, etcdLog []byte) monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+	locator := monitorapi.LocatePod(pod)
+	lostLeaderTime := time.Time{}
+	lostLeaderMessage := ""
+
+	scanner := bufio.NewScanner(bytes.NewBuffer(etcdLog))
+	for scanner.Scan() {
+		currLine := scanner.Bytes()
+		if !bytes.HasPrefix(currLine, []byte("{")) {
+			continue
+		}
+		logLine := etcdLogLine{}
+		if err := json.Unmarshal(currLine, &logLine); err != nil {
+			continue
+		}
+		if !strings.HasPrefix(logLine.Msg, "raft.node: ") {
+			continue
+		}
+		logTime, err := time.Parse(time.RFC3339Nano, logLine.TS)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failure parsing time format: %v for %q\n", err, logLine.TS)
+			continue
+		}
+
+		var message string
+		switch {
+		case etcdLeaderLostRegex.MatchString(logLine.Msg):
+			subMatches := etcdLeaderLostRegex.FindStringSubmatch(logLine.Msg)
+			if lostLeaderTime.IsZero() {
+				lostLeaderTime = logTime
+				lostLeaderMessage = fmt.Sprintf("lost leader %s at term %s",
+					subMatches[etcdLeaderLostRegex.SubexpIndex("OLD")], subMatches[etcdLeaderLostRegex.SubexpIndex("TERM")])
+			}
+			continue
+
+		case etcdLeaderElectedRegex.MatchString(logLine.Msg):
+			subMatches := etcdLeaderElectedRegex.FindStringSubmatch(logLine.Msg)
+			message = monitorapi.ReasonedMessagef(monitorapi.EtcdReasonLeaderElected, "leader/%s term/%s",
+				subMatches[etcdLeaderElectedRegex.SubexpIndex("LEADER")], subMatches[etcdLeaderElectedRegex.SubexpIndex("TERM")])
+
+		case etcdLeaderChangedRegex.MatchString(logLine.Msg):
+			subMatches := etcdLeaderChangedRegex.FindStringSubmatch(logLine.Msg)
+			message = monitorapi.ReasonedMessagef(monitorapi.EtcdReasonLeaderChanged, "leader/%s term/%s changed from %s",
+				subMatches[etcdLeaderChangedRegex.SubexpIndex("LEADER")], subMatches[etcdLeaderChangedRegex.SubexpIndex("TERM")],
+				subMatches[etcdLeaderChangedRegex.SubexpIndex("OLD")])
+
+		default:
+			continue
+		}
+
+		if !lostLeaderTime.IsZero() {
+			ret = append(ret, monitorapi.EventInterval{
+				Condition: monitorapi.Condition{
+					Level:   monitorapi.Warning,
+					Locator: locator,
+					Message: monitorapi.ReasonedMessage(monitorapi.EtcdReasonLeaderMissing, lostLeaderMessage),
+				},
+				From: lostLeaderTime,
+				To:   logTime,
+			})
+			lostLeaderTime = time.Time{}
+		}
+		ret = append(ret, monitorapi.EventInterval{
+			Condition: monitorapi.Condition{
+				Level:   monitorapi.Info,
+				Locator: locator,
+				Message: message,
+			},
+			From: logTime,
+			To:   logTime,
+		})
+	}
+
+	return ret
+}
+
+var (
+	kubeAPIServerShutdownEventRegex = regexp.MustCompile(`\[graceful-termination\] shutdown event"? name="?(?P<NAME>[A-Za-z]+)`)
+	klogTimeRegex                   = regexp.MustCompile(`^[IWEF](?P<MONTH>\d{2})(?P<DAY>\d{2}) (?P<TIME>\d{2}:\d{2}:\d{2}\.\d+)`)
+)
+
+// intervalsFromKubeAPIServerLogs produces an interval for every graceful shutdown of the kube-apiserver, from
+// ShutdownInitiated to the last shutdown phase logged.  The message lists when each phase was reached.
+func intervalsFromKubeAPIServerLogs(pod *corev1.Pod, kubeAPIServerLog []byte) monitorapi.Intervals {
+	ret := monitorapi.Intervals{}
+	locator := monitorapi.LocatePod(pod)
+
+	shutdownStart := time.Time{}
+	lastPhaseTime := time.Time{}
+	phases := []string{}
+	closeShutdown := func() {
+		if shutdownStart.IsZero() {
+			return
+		}
+		ret = append(ret, monitorapi.EventInterval{
+			Condition: monitorapi.Condition{
+				Level:   monitorapi.Info,
+				Locator: locator,
+				Message: monitorapi.ReasonedMessage(monitorapi.KubeAPIServerReasonGracefulShutdown, strings.Join(phases, " ")),
+			},
+			From: shutdownStart,
+			To:   lastPhaseTime,
+		})
+		shutdownStart = time.Time{}
+		phases = []string{}
+	}
+
+	scanner := bufio.NewScanner(bytes.NewBuffer(kubeAPIServerLog))
+	for scanner.Scan() {
+		currLine := scanner.Text()
+		subMatches := kubeAPIServerShutdownEventRegex.FindStringSubmatch(currLine)
+		if subMatches == nil {
+			continue
+		}
+		phase := subMatches[kubeAPIServerShutdownEventRegex.SubexpIndex("NAME")]
+		phaseTime, ok := klogTime(currLine)
+		if !ok {
+			continue
+		}
+
+		if phase == "ShutdownInitiated" {
+			closeShutdown()
+			shutdownStart = phaseTime
+			lastPhaseTime = phaseTime
+			phases = append(phases, "phase/"+phase)
+			continue
+		}
+		if shutdownStart.IsZero() {
+			continue
+		}
+		lastPhaseTime = phaseTime
+		phases = append(phases, fmt.Sprintf("phase/%s@%v", phase, phaseTime.Sub(shutdownStart).Round(time.Second)))
+	}
+	closeShutdown()
+
+	return ret
+}
+
+// klogTime reads the klog header time.  klog doesn't include the year, so the current year is assumed.
+func klogTime(logLine string) (time.Time, bool) {
+	subMatches := klogTimeRegex.FindStringSubmatch(logLine)
+	if subMatches == nil {
+		return time.Time{}, false
+	}
+	timeString := fmt.Sprintf("%d-%s-%sT%sZ", time.Now().Year(),
+		subMatches[klogTimeRegex.SubexpIndex("MONTH")], subMatches[klogTimeRegex.SubexpIndex("DAY")], subMatches[klogTimeRegex.SubexpIndex("TIME")])
+	ret, err := time.Parse(time.RFC3339Nano, timeString)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failure parsing time format: %v for %q\n", err, timeString)
+		return time.Time{}, false
+	}
+	return ret, true
+}
@@ -0,0 +1,60 @@
+package intervalcreation
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_intervalsFromEtcdLogs(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-etcd", Name: "etcd-master-0", UID: "uid-1"},
+		Spec:       corev1.PodSpec{NodeName: "master-0"},
+	}
+	etcdLog := `{"level":"info","ts":"2022-07-05T17:40:00.000Z","logger":"raft","caller":"etcdserver/zap_raft.go:77","msg":"raft.node: 8e9e05c52164694d elected leader 8e9e05c52164694d at term 2"}
+{"level":"info","ts":"2022-07-05T17:40:05.000Z","caller":"etcdserver/server.go:2042","msg":"published local member to cluster through raft"}
+{"level":"info","ts":"2022-07-05T17:50:00.000Z","logger":"raft","caller":"etcdserver/zap_raft.go:77","msg":"raft.node: 8e9e05c52164694d lost leader 8e9e05c52164694d at term 3"}
+{"level":"info","ts":"2022-07-05T17:50:02.500Z","logger":"raft","caller":"etcdserver/zap_raft.go:77","msg":"raft.node: 8e9e05c52164694d elected leader 91bc3c398fb3c146 at term 3"}
+`
+	actual := intervalsFromEtcdLogs(pod, []byte(etcdLog))
+	if len(actual) != 3 {
+		t.Fatalf("unexpected intervals: %v", actual.Strings())
+	}
+	if actual[0].Locator != "ns/openshift-etcd pod/etcd-master-0 node/master-0 uid/uid-1" {
+		t.Errorf("unexpected locator: %v", actual[0].Locator)
+	}
+	if actual[0].Message != "reason/LeaderElected leader/8e9e05c52164694d term/2" {
+		t.Errorf("unexpected message: %v", actual[0].Message)
+	}
+	if actual[1].Message != "reason/LeaderMissing lost leader 8e9e05c52164694d at term 3" || actual[1].To.Sub(actual[1].From) != 2500*time.Millisecond {
+		t.Errorf("unexpected interval: %v", actual[1].String())
+	}
+	if actual[2].Message != "reason/LeaderElected leader/91bc3c398fb3c146 term/3" {
+		t.Errorf("unexpected message: %v", actual[2].Message)
+	}
+}
+
+func Test_intervalsFromKubeAPIServerLogs(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-kube-apiserver", Name: "kube-apiserver-master-0", UID: "uid-1"},
+		Spec:       corev1.PodSpec{NodeName: "master-0"},
+	}
+	kubeAPIServerLog := `I0705 17:40:00.000000      16 genericapiserver.go:709] "[graceful-termination] shutdown event" name="ShutdownInitiated"
+I0705 17:40:00.000010      16 genericapiserver.go:712] "[graceful-termination] shutdown event" name="AfterShutdownDelayDuration"
+I0705 17:41:10.000000      16 genericapiserver.go:745] "[graceful-termination] shutdown event" name="InFlightRequestsDrained"
+I0705 17:41:15.000000      16 genericapiserver.go:475] "[graceful-termination] shutdown event" name="TerminationGracefulTerminationFinished"
+`
+	actual := intervalsFromKubeAPIServerLogs(pod, []byte(kubeAPIServerLog))
+	if len(actual) != 1 {
+		t.Fatalf("unexpected intervals: %v", actual.Strings())
+	}
+	expectedMessage := "reason/GracefulShutdown phase/ShutdownInitiated phase/AfterShutdownDelayDuration@0s phase/InFlightRequestsDrained@1m10s phase/TerminationGracefulTerminationFinished@1m15s"
+	if actual[0].Message != expectedMessage {
+		t.Errorf("unexpected message: %v", actual[0].Message)
+	}
+	if actual[0].To.Sub(actual[0].From) != 75*time.Second {
+		t.Errorf("unexpected duration: %v", actual[0].String())
+	}
+}
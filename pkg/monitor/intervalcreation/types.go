@@ -2,64 +2,33 @@ package intervalcreation
 
 import (
 	"context"
-	"sort"
 	"time"
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
-	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 )
 
-type simpleIntervalCreationFunc func(intervals monitorapi.Intervals, recordedResources monitorapi.ResourcesMap, beginning, end time.Time) monitorapi.Intervals
-
-func defaultIntervalCreationFns() []simpleIntervalCreationFunc {
-	return []simpleIntervalCreationFunc{
-		IntervalsFromEvents_OperatorAvailable,
-		IntervalsFromEvents_OperatorProgressing,
-		IntervalsFromEvents_OperatorDegraded,
-		IntervalsFromEvents_E2ETests,
-		IntervalsFromEvents_NodeChanges,
-		CreatePodIntervalsFromInstants,
-	}
-}
-
 // InsertCalculatedIntervals calculates intervals from the currently known interval set and saves them into the same list
+// using the default interval creators.
 func InsertCalculatedIntervals(startingIntervals []monitorapi.EventInterval, recordedResources monitorapi.ResourcesMap, from, to time.Time) monitorapi.Intervals {
-	ret := make([]monitorapi.EventInterval, len(startingIntervals))
-	copy(ret, startingIntervals)
-
-	intervalCreationFns := defaultIntervalCreationFns()
-	// create additional intervals from events
-	for _, createIntervals := range intervalCreationFns {
-		ret = append(ret, createIntervals(startingIntervals, recordedResources, from, to)...)
-	}
-
-	// we must sort the result
-	sort.Sort(monitorapi.Intervals(ret))
-
-	return ret
+	return NewDefaultIntervalCreatorRegistry().InsertCalculatedIntervals(startingIntervals, recordedResources, from, to)
 }
 
-// InsertIntervalsFromCluster contacts the cluster, retrieves information deemed pertinent, and creates intervals for them.
+// InsertIntervalsFromCluster contacts the cluster, retrieves information deemed pertinent, and creates intervals for them
+// using the default interval creators.
 func InsertIntervalsFromCluster(ctx context.Context, kubeConfig *rest.Config, startingIntervals []monitorapi.EventInterval, recordedResources monitorapi.ResourcesMap, from, to time.Time) (monitorapi.Intervals, error) {
-	ret := make([]monitorapi.EventInterval, len(startingIntervals))
-	copy(ret, startingIntervals)
-
-	kubeClient, err := kubernetes.NewForConfig(kubeConfig)
-	if err != nil {
-		return ret, err
-	}
+	return NewDefaultIntervalCreatorRegistry().InsertIntervalsFromCluster(ctx, kubeConfig, startingIntervals, recordedResources, from, to)
+}
 
-	allErrors := []error{}
-	nodeEvents, err := IntervalsFromNodeLogs(ctx, kubeClient, from, to)
-	if err != nil {
-		allErrors = append(allErrors, err)
-	}
-	ret = append(ret, nodeEvents...)
+func intervalsFromNodeLogs(ctx context.Context, kubeClient kubernetes.Interface, _ monitorapi.Intervals, _ monitorapi.ResourcesMap, from, to time.Time) (monitorapi.Intervals, error) {
+	return IntervalsFromNodeLogs(ctx, kubeClient, from, to)
+}
 
-	// we must sort the result
-	sort.Sort(monitorapi.Intervals(ret))
+func intervalsFromStaticPodLogs(ctx context.Context, kubeClient kubernetes.Interface, _ monitorapi.Intervals, _ monitorapi.ResourcesMap, from, to time.Time) (monitorapi.Intervals, error) {
+	return IntervalsFromStaticPodLogs(ctx, kubeClient, from, to)
+}
 
-	return ret, utilerrors.NewAggregate(allErrors)
+func intervalsFromAuditLogs(ctx context.Context, kubeClient kubernetes.Interface, _ monitorapi.Intervals, _ monitorapi.ResourcesMap, from, to time.Time) (monitorapi.Intervals, error) {
+	return IntervalsFromAuditLogs(ctx, kubeClient, from, to)
 }
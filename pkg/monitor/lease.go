@@ -0,0 +1,114 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// kubeSystemNamespace holds the leases of the kube-controller-manager and the kube-scheduler.
+const kubeSystemNamespace = "kube-system"
+
+// startLeaseMonitoring records the holder of every lease used for leader election by the platform and every time
+// the holder changes.  Only kube-system and the openshift-* namespaces are watched, e2e tests create leases of their
+// own.  Leases are renewed every few seconds, so renewals are ignored.  A holder shutting down gracefully releases the
+// lease by clearing the holder, so the next holder is recorded as taking over from the last holder the lease had.
+func startLeaseMonitoring(ctx context.Context, m Recorder, client kubernetes.Interface, namespaceInformers *openshiftNamespaceInformers) {
+	// leaseToLastHolder is shared by the informers of every namespace.
+	lock := sync.Mutex{}
+	leaseToLastHolder := map[string]string{}
+
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			lease, ok := obj.(*coordinationv1.Lease)
+			if !ok {
+				return
+			}
+			holder := leaseHolder(lease)
+			if len(holder) == 0 {
+				return
+			}
+			lock.Lock()
+			leaseToLastHolder[lease.Namespace+"/"+lease.Name] = holder
+			lock.Unlock()
+			m.Record(monitorapi.Condition{
+				Level:   monitorapi.Info,
+				Locator: monitorapi.LeaseLocator(lease.Namespace, lease.Name),
+				Message: fmt.Sprintf("reason/%s holder/%s transitions/%d", monitorapi.LeaseReasonHolderObserved, holder, leaseTransitions(lease)),
+			})
+		},
+		UpdateFunc: func(old, obj interface{}) {
+			lease, ok := obj.(*coordinationv1.Lease)
+			if !ok {
+				return
+			}
+			oldLease, ok := old.(*coordinationv1.Lease)
+			if !ok {
+				return
+			}
+			holder := leaseHolder(lease)
+			oldHolder := leaseHolder(oldLease)
+			if holder == oldHolder {
+				return
+			}
+			key := lease.Namespace + "/" + lease.Name
+			lock.Lock()
+			defer lock.Unlock()
+			lastHolder := leaseToLastHolder[key]
+			if len(holder) == 0 {
+				if len(oldHolder) == 0 {
+					return
+				}
+				m.Record(monitorapi.Condition{
+					Level:   monitorapi.Info,
+					Locator: monitorapi.LeaseLocator(lease.Namespace, lease.Name),
+					Message: fmt.Sprintf("reason/%s previousHolder/%s transitions/%d", monitorapi.LeaseReasonHolderReleased, oldHolder, leaseTransitions(lease)),
+				})
+				return
+			}
+
+			leaseToLastHolder[key] = holder
+			previous := ""
+			if len(lastHolder) > 0 {
+				previous = fmt.Sprintf(" previousHolder/%s", lastHolder)
+			}
+			m.Record(monitorapi.Condition{
+				Level:   monitorapi.Warning,
+				Locator: monitorapi.LeaseLocator(lease.Namespace, lease.Name),
+				Message: fmt.Sprintf("reason/%s holder/%s%s transitions/%d", monitorapi.LeaseReasonHolderChanged, holder, previous, leaseTransitions(lease)),
+			})
+		},
+	}
+
+	namespaceInformers.AddHandler(func(namespace string, factory informers.SharedInformerFactory) {
+		namespaceInformers.LeaseInformer(namespace, factory).AddEventHandler(handler)
+	})
+
+	kubeSystemInformers := informers.NewSharedInformerFactoryWithOptions(client, time.Hour, informers.WithNamespace(kubeSystemNamespace))
+	namespaceInformers.LeaseInformer(kubeSystemNamespace, kubeSystemInformers).AddEventHandler(handler)
+	kubeSystemInformers.Start(ctx.Done())
+}
+
+func leaseHolder(lease *coordinationv1.Lease) string {
+	if lease.Spec.HolderIdentity == nil {
+		return ""
+	}
+	// holders are usually <node>_<uuid>, but some put spaces in them.  Keep the message parseable.
+	return strings.ReplaceAll(*lease.Spec.HolderIdentity, " ", "_")
+}
+
+func leaseTransitions(lease *coordinationv1.Lease) int32 {
+	if lease.Spec.LeaseTransitions == nil {
+		return 0
+	}
+	return *lease.Spec.LeaseTransitions
+}
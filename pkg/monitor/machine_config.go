@@ -0,0 +1,160 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+)
+
+var machineConfigPoolResource = schema.GroupVersionResource{Group: "machineconfiguration.openshift.io", Version: "v1", Resource: "machineconfigpools"}
+
+// startMachineConfigPoolMonitoring records MachineConfigPool condition and rendered config changes.  The MCO
+// clientset is not vendored here, so pools are read as unstructured.
+func startMachineConfigPoolMonitoring(ctx context.Context, m Recorder, client dynamic.Interface) {
+	poolInformer := cache.NewSharedIndexInformer(
+		NewErrorRecordingListWatcher(m, &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return client.Resource(machineConfigPoolResource).List(ctx, options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return client.Resource(machineConfigPoolResource).Watch(ctx, options)
+			},
+		}),
+		&unstructured.Unstructured{},
+		time.Hour,
+		nil,
+	)
+
+	poolChangeFns := []func(pool, oldPool *unstructured.Unstructured) []monitorapi.Condition{
+		func(pool, oldPool *unstructured.Unstructured) []monitorapi.Condition {
+			var conditions []monitorapi.Condition
+			oldConditions := machineConfigPoolConditions(oldPool)
+			for _, c := range machineConfigPoolConditions(pool) {
+				previous, ok := oldConditions[c.Type]
+				if ok && previous.Status == c.Status {
+					continue
+				}
+				conditions = append(conditions, monitorapi.Condition{
+					Level:   c.level(),
+					Locator: monitorapi.MachineConfigPoolLocator(pool.GetName()),
+					Message: fmt.Sprintf("condition/%s status/%s reason/%s changed: %s", c.Type, c.Status, c.Reason, c.Message),
+				})
+			}
+			return conditions
+		},
+		func(pool, oldPool *unstructured.Unstructured) []monitorapi.Condition {
+			oldConfig, _, _ := unstructured.NestedString(oldPool.Object, "spec", "configuration", "name")
+			newConfig, _, _ := unstructured.NestedString(pool.Object, "spec", "configuration", "name")
+			if oldConfig == newConfig {
+				return nil
+			}
+			return []monitorapi.Condition{
+				{
+					Level:   monitorapi.Info,
+					Locator: monitorapi.MachineConfigPoolLocator(pool.GetName()),
+					Message: fmt.Sprintf("reason/MachineConfigChange config/%s previous config/%s", newConfig, oldConfig),
+				},
+			}
+		},
+	}
+
+	poolInformer.AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				pool, ok := obj.(*unstructured.Unstructured)
+				if !ok {
+					return
+				}
+				// pools already updating or degraded when we start will never report a transition, so record them now
+				for _, c := range machineConfigPoolConditions(pool) {
+					if c.Status != "True" || (c.Type != "Updating" && !c.isDegraded()) {
+						continue
+					}
+					m.Record(monitorapi.Condition{
+						Level:   c.level(),
+						Locator: monitorapi.MachineConfigPoolLocator(pool.GetName()),
+						Message: fmt.Sprintf("condition/%s status/%s reason/%s observed: %s", c.Type, c.Status, c.Reason, c.Message),
+					})
+				}
+			},
+			DeleteFunc: func(obj interface{}) {
+				pool, ok := obj.(*unstructured.Unstructured)
+				if !ok {
+					return
+				}
+				m.Record(monitorapi.Condition{
+					Level:   monitorapi.Warning,
+					Locator: monitorapi.MachineConfigPoolLocator(pool.GetName()),
+					Message: "deleted",
+				})
+			},
+			UpdateFunc: func(old, obj interface{}) {
+				pool, ok := obj.(*unstructured.Unstructured)
+				if !ok {
+					return
+				}
+				oldPool, ok := old.(*unstructured.Unstructured)
+				if !ok {
+					return
+				}
+				if pool.GetUID() != oldPool.GetUID() {
+					return
+				}
+				for _, fn := range poolChangeFns {
+					m.Record(fn(pool, oldPool)...)
+				}
+			},
+		},
+	)
+
+	go poolInformer.Run(ctx.Done())
+}
+
+type machineConfigPoolCondition struct {
+	Type    string
+	Status  string
+	Reason  string
+	Message string
+}
+
+func (c machineConfigPoolCondition) isDegraded() bool {
+	return c.Type == "Degraded" || c.Type == "NodeDegraded" || c.Type == "RenderDegraded"
+}
+
+func (c machineConfigPoolCondition) level() monitorapi.EventLevel {
+	if c.isDegraded() && c.Status == "True" {
+		return monitorapi.Error
+	}
+	return monitorapi.Info
+}
+
+func machineConfigPoolConditions(pool *unstructured.Unstructured) map[string]machineConfigPoolCondition {
+	ret := map[string]machineConfigPoolCondition{}
+	conditions, _, _ := unstructured.NestedSlice(pool.Object, "status", "conditions")
+	for _, uncastCondition := range conditions {
+		condition, ok := uncastCondition.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		c := machineConfigPoolCondition{}
+		c.Type, _, _ = unstructured.NestedString(condition, "type")
+		c.Status, _, _ = unstructured.NestedString(condition, "status")
+		c.Reason, _, _ = unstructured.NestedString(condition, "reason")
+		c.Message, _, _ = unstructured.NestedString(condition, "message")
+		if len(c.Type) == 0 {
+			continue
+		}
+		ret[c.Type] = c
+	}
+	return ret
+}
@@ -0,0 +1,63 @@
+package monitor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func TestStartMachineConfigPoolMonitoring_existingPools(t *testing.T) {
+	pool := func(name string, conditions ...interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "machineconfiguration.openshift.io/v1",
+			"kind":       "MachineConfigPool",
+			"metadata":   map[string]interface{}{"name": name},
+			"status":     map[string]interface{}{"conditions": conditions},
+		}}
+	}
+	condition := func(conditionType, status string) map[string]interface{} {
+		return map[string]interface{}{"type": conditionType, "status": status, "reason": "Test", "message": "test message"}
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{machineConfigPoolResource: "MachineConfigPoolList"},
+		pool("master", condition("Updating", "True"), condition("Updated", "False"), condition("Degraded", "False")),
+		pool("worker", condition("Updating", "False"), condition("Updated", "True"), condition("Degraded", "True")),
+		pool("infra", condition("Updating", "False"), condition("Updated", "True")),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m := NewMonitor()
+	startMachineConfigPoolMonitoring(ctx, m, client)
+
+	var intervals monitorapi.Intervals
+	if err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		intervals = m.Intervals(time.Time{}, time.Time{})
+		return len(intervals) >= 2, nil
+	}); err != nil {
+		t.Fatalf("expected the updating and degraded pools to be recorded, got %v", intervals)
+	}
+	actual := map[string]string{}
+	for _, interval := range intervals {
+		actual[interval.Locator] = interval.Message
+	}
+	expected := map[string]string{
+		monitorapi.MachineConfigPoolLocator("master"): "condition/Updating status/True reason/Test observed: test message",
+		monitorapi.MachineConfigPoolLocator("worker"): "condition/Degraded status/True reason/Test observed: test message",
+	}
+	if len(actual) != len(expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+	for locator, message := range expected {
+		if actual[locator] != message {
+			t.Errorf("expected %q for %v, got %q", message, locator, actual[locator])
+		}
+	}
+}
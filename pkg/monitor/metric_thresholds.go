@@ -0,0 +1,238 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	routeclient "github.com/openshift/client-go/route/clientset/versioned"
+	"github.com/openshift/library-go/test/library/metrics"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	prometheustypes "github.com/prometheus/common/model"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// metricThresholdStep is the resolution of the range queries.  The queries are usually rates over several minutes,
+// so a finer step would only add points, and this allows runs of up to three days before Prometheus refuses the query.
+const metricThresholdStep = 30 * time.Second
+
+// MetricThreshold is a PromQL query to record while the tests run.  Every series of the query gets an interval for
+// each time it is above the threshold.
+type MetricThreshold struct {
+	// Name is in the locator of the intervals, so it must not contain spaces.
+	Name string `json:"name"`
+	// Query is evaluated over the whole run.
+	Query string `json:"query"`
+	// Threshold is the value a series must be above to create an interval.
+	Threshold float64 `json:"threshold"`
+	// Level is Info, Warning, or Error.  Warning is used when it is empty.
+	Level string `json:"level,omitempty"`
+	// Description explains the query to the people editing the list.
+	Description string `json:"description,omitempty"`
+}
+
+// DefaultMetricThresholds are recorded by every run unless a different list is configured.
+func DefaultMetricThresholds() []MetricThreshold {
+	return []MetricThreshold{
+		{
+			Name:        "apiserver-request-latency-p99",
+			Query:       `histogram_quantile(0.99, sum by (le, verb) (rate(apiserver_request_duration_seconds_bucket{job="apiserver",verb!~"WATCH|CONNECT"}[5m])))`,
+			Threshold:   1,
+			Description: "99th percentile of the kube-apiserver request latency in seconds, by verb.",
+		},
+		{
+			Name:        "etcd-wal-fsync-latency-p99",
+			Query:       `histogram_quantile(0.99, sum by (instance, le) (rate(etcd_disk_wal_fsync_duration_seconds_bucket{job="etcd"}[5m])))`,
+			Threshold:   0.5,
+			Description: "99th percentile of the etcd WAL fsync latency in seconds, by member.",
+		},
+		{
+			Name:        "node-cpu-usage",
+			Query:       `1 - avg by (instance) (rate(node_cpu_seconds_total{mode="idle"}[5m]))`,
+			Threshold:   0.9,
+			Description: "Fraction of the CPU of each node that is not idle.",
+		},
+	}
+}
+
+// LoadMetricThresholds reads a JSON list of MetricThreshold from a file.
+func LoadMetricThresholds(filename string) ([]MetricThreshold, error) {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	ret := []MetricThreshold{}
+	if err := json.Unmarshal(content, &ret); err != nil {
+		return nil, fmt.Errorf("unable to read metric thresholds from %v: %w", filename, err)
+	}
+	for _, threshold := range ret {
+		if err := threshold.validate(); err != nil {
+			return nil, fmt.Errorf("invalid metric threshold in %v: %w", filename, err)
+		}
+	}
+	return ret, nil
+}
+
+func (t MetricThreshold) validate() error {
+	if len(t.Name) == 0 || strings.ContainsAny(t.Name, " \t\n") {
+		return fmt.Errorf("name %q must be set and must not contain spaces", t.Name)
+	}
+	if len(t.Query) == 0 {
+		return fmt.Errorf("%v: query must be set", t.Name)
+	}
+	if _, err := t.eventLevel(); err != nil {
+		return fmt.Errorf("%v: %w", t.Name, err)
+	}
+	return nil
+}
+
+func (t MetricThreshold) eventLevel() (monitorapi.EventLevel, error) {
+	if len(t.Level) == 0 {
+		return monitorapi.Warning, nil
+	}
+	return monitorapi.EventLevelFromString(t.Level)
+}
+
+// FetchEventIntervalsForMetricThresholds creates the intervals for the thresholds from the in-cluster Prometheus.
+func FetchEventIntervalsForMetricThresholds(ctx context.Context, restConfig *rest.Config, startTime time.Time, thresholds []MetricThreshold) ([]monitorapi.EventInterval, error) {
+	if len(thresholds) == 0 {
+		return nil, nil
+	}
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	routeClient, err := routeclient.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	prometheusClient, err := metrics.NewPrometheusClient(ctx, kubeClient, routeClient)
+	if err != nil {
+		return nil, err
+	}
+	return FetchEventIntervalsForMetricThresholdsFromPrometheus(ctx, prometheusClient, startTime, time.Now(), thresholds)
+}
+
+// FetchEventIntervalsForMetricThresholdsFromPrometheus creates the intervals for the thresholds from any Prometheus.
+// A query that fails does not stop the others, all of the errors are returned with the intervals that could be created.
+func FetchEventIntervalsForMetricThresholdsFromPrometheus(ctx context.Context, prometheusClient prometheusv1.API, startTime, endTime time.Time, thresholds []MetricThreshold) ([]monitorapi.EventInterval, error) {
+	timeRange := prometheusv1.Range{
+		Start: startTime,
+		End:   endTime,
+		Step:  metricThresholdStep,
+	}
+
+	ret := []monitorapi.EventInterval{}
+	errs := []string{}
+	for _, threshold := range thresholds {
+		result, warningsForQuery, err := prometheusClient.QueryRange(ctx, threshold.Query, timeRange)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%v: %v", threshold.Name, err))
+			continue
+		}
+		if len(warningsForQuery) > 0 {
+			fmt.Printf("#### warnings \n\t%v\n", strings.Join(warningsForQuery, "\n\t"))
+		}
+		intervals, err := CreateEventIntervalsForMetricThreshold(threshold, result, metricThresholdStep)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%v: %v", threshold.Name, err))
+			continue
+		}
+		ret = append(ret, intervals...)
+	}
+	if len(errs) > 0 {
+		return ret, fmt.Errorf("unable to query metric thresholds: %v", strings.Join(errs, "; "))
+	}
+	return ret, nil
+}
+
+// CreateEventIntervalsForMetricThreshold turns every run of samples above the threshold into an interval.  A sample
+// missing for longer than a step ends the interval, like a sample at or below the threshold does.  The interval lasts
+// until the step after its last sample, since the value is only known to drop somewhere in between.
+func CreateEventIntervalsForMetricThreshold(threshold MetricThreshold, result prometheustypes.Value, step time.Duration) ([]monitorapi.EventInterval, error) {
+	if result.Type() != prometheustypes.ValMatrix {
+		return nil, fmt.Errorf("expected a matrix, got %q", result.Type().String())
+	}
+	level, err := threshold.eventLevel()
+	if err != nil {
+		return nil, err
+	}
+
+	ret := []monitorapi.EventInterval{}
+	for _, series := range result.(prometheustypes.Matrix) {
+		locator := "metric/" + threshold.Name
+		if instance := series.Metric["instance"]; len(instance) > 0 {
+			locator += " " + metricInstanceLocator(string(instance))
+		}
+		if namespace := series.Metric["namespace"]; len(namespace) > 0 {
+			locator += " ns/" + string(namespace)
+		}
+		if pod := series.Metric["pod"]; len(pod) > 0 {
+			locator += " pod/" + string(pod)
+		}
+
+		var from, last time.Time
+		var maxValue float64
+		closeInterval := func() {
+			if from.IsZero() {
+				return
+			}
+			ret = append(ret, monitorapi.EventInterval{
+				Condition: monitorapi.Condition{
+					Level:   level,
+					Locator: locator,
+					Message: fmt.Sprintf("reason/%s threshold/%s max/%s %s",
+						monitorapi.MetricReasonThresholdExceeded, formatMetricValue(threshold.Threshold), formatMetricValue(maxValue), series.Metric.String()),
+				},
+				From: from,
+				To:   last.Add(step),
+			})
+			from, last, maxValue = time.Time{}, time.Time{}, 0
+		}
+
+		for _, sample := range series.Values {
+			currTime := sample.Timestamp.Time()
+			currValue := float64(sample.Value)
+			if !from.IsZero() && currTime.Sub(last) > step+step/2 {
+				closeInterval()
+			}
+			if !(currValue > threshold.Threshold) {
+				closeInterval()
+				continue
+			}
+			if from.IsZero() {
+				from, maxValue = currTime, currValue
+			}
+			if currValue > maxValue {
+				maxValue = currValue
+			}
+			last = currTime
+		}
+		closeInterval()
+	}
+	return ret, nil
+}
+
+// metricInstanceLocator returns the locator of the instance label.  The node exporter sets it to the node name, but
+// most targets, like etcd, keep the address they were scraped at, which is not a node.
+func metricInstanceLocator(instance string) string {
+	host, _, err := net.SplitHostPort(instance)
+	if err != nil {
+		host = instance
+	}
+	if host != instance || net.ParseIP(host) != nil {
+		return "instance/" + instance
+	}
+	return "node/" + instance
+}
+
+func formatMetricValue(value float64) string {
+	return strconv.FormatFloat(value, 'g', 4, 64)
+}
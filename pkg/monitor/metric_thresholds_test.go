@@ -0,0 +1,117 @@
+package monitor
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	prometheustypes "github.com/prometheus/common/model"
+)
+
+func TestCreateEventIntervalsForMetricThreshold(t *testing.T) {
+	start := time.Unix(1657040400, 0)
+	step := 30 * time.Second
+	samples := func(values ...float64) []prometheustypes.SamplePair {
+		ret := []prometheustypes.SamplePair{}
+		for i, value := range values {
+			if value < 0 { // missing sample
+				continue
+			}
+			ret = append(ret, prometheustypes.SamplePair{
+				Timestamp: prometheustypes.TimeFromUnixNano(start.Add(time.Duration(i) * step).UnixNano()),
+				Value:     prometheustypes.SampleValue(value),
+			})
+		}
+		return ret
+	}
+	threshold := MetricThreshold{Name: "etcd-wal-fsync-latency-p99", Query: "unused", Threshold: 0.5}
+
+	matrix := prometheustypes.Matrix{
+		{
+			// above twice, and the second time is broken up by a missing sample
+			Metric: prometheustypes.Metric{"instance": "10.0.0.1:9979"},
+			Values: samples(0.1, 0.6, 0.8, 0.2, 0.7, -1, 0.9),
+		},
+		{
+			// exactly at the threshold is not above it
+			Metric: prometheustypes.Metric{"instance": "10.0.0.2:9979"},
+			Values: samples(0.5, 0.5),
+		},
+	}
+
+	intervals, err := CreateEventIntervalsForMetricThreshold(threshold, matrix, step)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []string{}
+	for _, interval := range intervals {
+		got = append(got, interval.String())
+		if interval.Level != monitorapi.Warning {
+			t.Errorf("expected the default level to be Warning, got %v", interval.Level)
+		}
+		if !monitorapi.IsMetricThresholdExceeded(interval) {
+			t.Errorf("expected a metric threshold interval, got %v", interval)
+		}
+		if name, _ := monitorapi.MetricFromLocator(interval.Locator); name != threshold.Name {
+			t.Errorf("expected metric %v, got %v", threshold.Name, name)
+		}
+	}
+	want := []string{
+		monitorapi.EventInterval{
+			Condition: monitorapi.Condition{Level: monitorapi.Warning, Locator: "metric/etcd-wal-fsync-latency-p99 instance/10.0.0.1:9979",
+				Message: `reason/MetricThresholdExceeded threshold/0.5 max/0.8 {instance="10.0.0.1:9979"}`},
+			From: start.Add(1 * step), To: start.Add(3 * step),
+		}.String(),
+		monitorapi.EventInterval{
+			Condition: monitorapi.Condition{Level: monitorapi.Warning, Locator: "metric/etcd-wal-fsync-latency-p99 instance/10.0.0.1:9979",
+				Message: `reason/MetricThresholdExceeded threshold/0.5 max/0.7 {instance="10.0.0.1:9979"}`},
+			From: start.Add(4 * step), To: start.Add(5 * step),
+		}.String(),
+		monitorapi.EventInterval{
+			Condition: monitorapi.Condition{Level: monitorapi.Warning, Locator: "metric/etcd-wal-fsync-latency-p99 instance/10.0.0.1:9979",
+				Message: `reason/MetricThresholdExceeded threshold/0.5 max/0.9 {instance="10.0.0.1:9979"}`},
+			From: start.Add(6 * step), To: start.Add(7 * step),
+		}.String(),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected\n%v\ngot\n%v", want, got)
+	}
+
+	if _, err := CreateEventIntervalsForMetricThreshold(threshold, prometheustypes.Vector{}, step); err == nil {
+		t.Errorf("expected an instant query to be refused")
+	}
+}
+
+func Test_metricInstanceLocator(t *testing.T) {
+	tests := map[string]string{
+		"ip-10-0-136-49.us-east-2.compute.internal": "node/ip-10-0-136-49.us-east-2.compute.internal",
+		"10.0.0.1:9979":  "instance/10.0.0.1:9979",
+		"10.0.0.1":       "instance/10.0.0.1",
+		"[fd00::1]:9979": "instance/[fd00::1]:9979",
+		"master-0:9100":  "instance/master-0:9100",
+	}
+	for instance, want := range tests {
+		if got := metricInstanceLocator(instance); got != want {
+			t.Errorf("%v: expected %v, got %v", instance, want, got)
+		}
+	}
+}
+
+func TestMetricThreshold_validate(t *testing.T) {
+	for _, threshold := range DefaultMetricThresholds() {
+		if err := threshold.validate(); err != nil {
+			t.Errorf("default threshold is invalid: %v", err)
+		}
+	}
+	invalid := []MetricThreshold{
+		{Name: "has spaces", Query: "up"},
+		{Name: "no-query"},
+		{Name: "bad-level", Query: "up", Level: "Critical"},
+	}
+	for _, threshold := range invalid {
+		if err := threshold.validate(); err == nil {
+			t.Errorf("expected %v to be invalid", threshold.Name)
+		}
+	}
+}
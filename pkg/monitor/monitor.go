@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 )
@@ -112,7 +113,9 @@ func (m *Monitor) RecordResource(resourceType string, obj runtime.Object) {
 		UID:       fmt.Sprintf("%v", newMetadata.GetUID()),
 	}
 
+	// the counts are kept on the stored copy, the caller's object is usually shared with an informer cache
 	toStore := obj.DeepCopyObject()
+	newMetadata, _ = meta.Accessor(toStore)
 	// without metadata, just stomp in the new value, we can't add annotations
 	if newMetadata == nil {
 		recordedResource[key] = toStore
@@ -128,6 +131,9 @@ func (m *Monitor) RecordResource(resourceType string, obj runtime.Object) {
 		if newMetadata != nil {
 			newAnnotations[monitorapi.ObservedUpdateCountAnnotation] = "1"
 			newAnnotations[monitorapi.ObservedRecreationCountAnnotation] = "0"
+			if pod, ok := toStore.(*corev1.Pod); ok {
+				newAnnotations[monitorapi.ObservedInitialRestartCountAnnotation] = fmt.Sprintf("%d", podRestartCount(pod))
+			}
 			newMetadata.SetAnnotations(newAnnotations)
 		}
 		recordedResource[key] = toStore
@@ -153,7 +159,7 @@ func (m *Monitor) RecordResource(resourceType string, obj runtime.Object) {
 	}
 
 	// set the recreate count. increment if the UIDs don't match
-	existingRecreateCountStr := existingAnnotations[monitorapi.ObservedUpdateCountAnnotation]
+	existingRecreateCountStr := existingAnnotations[monitorapi.ObservedRecreationCountAnnotation]
 	if existingMetadata.GetUID() != newMetadata.GetUID() {
 		if existingRecreateCount, err := strconv.ParseInt(existingRecreateCountStr, 10, 32); err != nil {
 			newAnnotations[monitorapi.ObservedRecreationCountAnnotation] = existingRecreateCountStr
@@ -164,11 +170,27 @@ func (m *Monitor) RecordResource(resourceType string, obj runtime.Object) {
 		newAnnotations[monitorapi.ObservedRecreationCountAnnotation] = existingRecreateCountStr
 	}
 
+	if initialRestartCount, ok := existingAnnotations[monitorapi.ObservedInitialRestartCountAnnotation]; ok {
+		newAnnotations[monitorapi.ObservedInitialRestartCountAnnotation] = initialRestartCount
+	}
+
 	newMetadata.SetAnnotations(newAnnotations)
 	recordedResource[key] = toStore
 	return
 }
 
+// podRestartCount is the sum of the restart counts of every container of the pod.
+func podRestartCount(pod *corev1.Pod) int32 {
+	var restarts int32
+	for _, containerStatus := range pod.Status.InitContainerStatuses {
+		restarts += containerStatus.RestartCount
+	}
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		restarts += containerStatus.RestartCount
+	}
+	return restarts
+}
+
 // Record captures one or more conditions at the current time. All conditions are recorded
 // in monotonic order as EventInterval objects.
 func (m *Monitor) Record(conditions ...monitorapi.Condition) {
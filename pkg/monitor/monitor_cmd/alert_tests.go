@@ -0,0 +1,170 @@
+package monitor_cmd
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/openshift/origin/pkg/synthetictests"
+	"github.com/openshift/origin/pkg/synthetictests/allowedalerts"
+	"github.com/openshift/origin/pkg/synthetictests/platformidentification"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+const (
+	// monitorEventsFilePrefix starts the name of the file the monitor events of a run are written to.
+	monitorEventsFilePrefix = "e2e-events"
+)
+
+type AlertTestsOptions struct {
+	MonitorEventFilename string
+	JobTypeFilename      string
+	PodResourceFilename  string
+	// AlertFilenames and PrometheusURL replace the alerts in the monitor events, see replaceAlerts.
+	AlertFilenames []string
+	PrometheusURL  string
+
+	IOStreams genericclioptions.IOStreams
+}
+
+func NewAlertTestsOptions(ioStreams genericclioptions.IOStreams) *AlertTestsOptions {
+	return &AlertTestsOptions{
+		IOStreams: ioStreams,
+	}
+}
+
+func NewAlertTestsCommand(ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewAlertTestsOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:   "alert-tests",
+		Short: "Run the alert invariants against the data of a past run",
+		Long: `
+		Run the alert invariants of a past run without its cluster, and write the results as a junit test suite.
+
+		The job type is read from the job-type file written with the monitor events, unless --job-type is set.  The
+		alerts can be read from ALERTS series exported before the cluster was torn down, or from a running Prometheus
+		that still has them, instead of from the monitor events.
+
+		openshift-tests alert-tests -f e2e-events_20220705-170000.json
+		openshift-tests alert-tests -f e2e-events_20220705-170000.json --alerts=alerts-query-range.json
+		openshift-tests alert-tests -f e2e-events_20220705-170000.json --prometheus-url=http://localhost:9090
+		`,
+
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Complete(); err != nil {
+				return err
+			}
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	o.Bind(cmd.Flags())
+
+	return cmd
+}
+
+func (o *AlertTestsOptions) Bind(flagset *pflag.FlagSet) {
+	flagset.StringVarP(&o.MonitorEventFilename, "filename", "f", o.MonitorEventFilename, "e2e-events_<timestamp>.json file")
+	flagset.StringVar(&o.JobTypeFilename, "job-type", o.JobTypeFilename, "job-type_<timestamp>.json file.  Defaults to the one written with the monitor events.")
+	flagset.StringVar(&o.PodResourceFilename, "known-pods", o.PodResourceFilename, "resource-pods_<timestamp>.zip filename from openshift-tests.")
+	flagset.StringSliceVar(&o.AlertFilenames, "alerts", o.AlertFilenames, "ALERTS series exported with a range query with a step under five seconds, as the query_range response or its result.  Directories are read as all of the .json files in them.  Replaces the alerts in the monitor events.")
+	flagset.StringVar(&o.PrometheusURL, "prometheus-url", o.PrometheusURL, "The URL of a running Prometheus to read the alerts from.  Replaces the alerts in the monitor events.")
+}
+
+func (o *AlertTestsOptions) Complete() error {
+	if len(o.JobTypeFilename) > 0 || len(o.MonitorEventFilename) == 0 {
+		return nil
+	}
+	// the run data of a run shares the time suffix, like e2e-events_20220705-170000.json and job-type_20220705-170000.json
+	dir, filename := filepath.Split(o.MonitorEventFilename)
+	if strings.HasPrefix(filename, monitorEventsFilePrefix+"_") {
+		o.JobTypeFilename = filepath.Join(dir, platformidentification.JobTypeFilePrefix+strings.TrimPrefix(filename, monitorEventsFilePrefix))
+	}
+	return nil
+}
+
+func (o *AlertTestsOptions) Validate() error {
+	if len(o.MonitorEventFilename) == 0 {
+		return fmt.Errorf("missing -f")
+	}
+	if len(o.JobTypeFilename) == 0 {
+		return fmt.Errorf("missing --job-type")
+	}
+	if len(o.AlertFilenames) > 0 && len(o.PrometheusURL) > 0 {
+		return fmt.Errorf("--alerts and --prometheus-url are mutually exclusive")
+	}
+	return nil
+}
+
+func (o *AlertTestsOptions) Run() error {
+	events, err := monitorserialization.EventsFromFile(o.MonitorEventFilename)
+	if err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return fmt.Errorf("no monitor events were found in %v", o.MonitorEventFilename)
+	}
+	events, err = replaceAlerts(events, o.AlertFilenames, o.PrometheusURL)
+	if err != nil {
+		return err
+	}
+	jobType, err := platformidentification.ReadJobType(o.JobTypeFilename)
+	if err != nil {
+		return err
+	}
+	recordedResources := monitorapi.ResourcesMap{}
+	if len(o.PodResourceFilename) > 0 {
+		recordedResources, err = loadKnownPods(o.PodResourceFilename)
+		if err != nil {
+			return err
+		}
+	}
+
+	// without a cluster only the allowances that need nothing but the intervals are used
+	from, to := events.Span()
+	alertTests := allowedalerts.AllAlertTests(context.TODO(), nil, to.Sub(from))
+	testCases := synthetictests.RunAlertInvariants(alertTests, *jobType, events, recordedResources)
+
+	suite := &junitapi.JUnitTestSuite{
+		Name:     "alert invariants",
+		Duration: to.Sub(from).Seconds(),
+	}
+	failing, passing := sets.NewString(), sets.NewString()
+	for _, testCase := range testCases {
+		suite.NumTests++
+		if testCase.FailureOutput != nil {
+			suite.NumFailed++
+			failing.Insert(testCase.Name)
+		} else {
+			passing.Insert(testCase.Name)
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+	output, err := xml.MarshalIndent(suite, "", "    ")
+	if err != nil {
+		return err
+	}
+	if _, err := o.IOStreams.Out.Write(append(output, '\n')); err != nil {
+		return err
+	}
+
+	// a failure with a success of the same name is a flake
+	if failed := failing.Difference(passing); failed.Len() > 0 {
+		return fmt.Errorf("%d alert invariants failed:\n%s", failed.Len(), strings.Join(failed.List(), "\n"))
+	}
+	return nil
+}
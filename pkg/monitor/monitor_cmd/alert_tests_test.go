@@ -0,0 +1,68 @@
+package monitor_cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/openshift/origin/pkg/synthetictests/platformidentification"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+func TestAlertTestsOptions_Run(t *testing.T) {
+	artifactDir := t.TempDir()
+	start := time.Date(2022, 7, 5, 17, 0, 0, 0, time.UTC)
+	events := monitorapi.Intervals{
+		{
+			Condition: monitorapi.Condition{
+				Level:   monitorapi.Info,
+				Locator: "alert/Watchdog ns/openshift-monitoring",
+				Message: `alertstate="firing" severity="none"`,
+			},
+			From: start,
+			To:   start.Add(time.Hour),
+		},
+		{
+			Condition: monitorapi.Condition{
+				Level:   monitorapi.Error,
+				Locator: "alert/KubeAPIErrorBudgetBurn ns/openshift-kube-apiserver",
+				Message: `alertstate="firing" severity="critical"`,
+			},
+			From: start.Add(10 * time.Minute),
+			To:   start.Add(50 * time.Minute),
+		},
+	}
+	eventsFilename := filepath.Join(artifactDir, "e2e-events_20220705-170000.json")
+	if err := monitorserialization.EventsToFile(eventsFilename, events); err != nil {
+		t.Fatal(err)
+	}
+	jobType := &platformidentification.JobType{Release: "4.11", Platform: "aws", Architecture: "amd64", Network: "sdn", Topology: "ha"}
+	if err := platformidentification.WriteJobTypeForJobRun(artifactDir, jobType, "_20220705-170000"); err != nil {
+		t.Fatal(err)
+	}
+
+	out := &bytes.Buffer{}
+	o := NewAlertTestsOptions(genericclioptions.IOStreams{Out: out, ErrOut: &bytes.Buffer{}})
+	o.MonitorEventFilename = eventsFilename
+	if err := o.Complete(); err != nil {
+		t.Fatal(err)
+	}
+	if o.JobTypeFilename != filepath.Join(artifactDir, "job-type_20220705-170000.json") {
+		t.Errorf("expected the job type written with the events, got %v", o.JobTypeFilename)
+	}
+	if err := o.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	err := o.Run()
+	if err == nil || !strings.Contains(err.Error(), "KubeAPIErrorBudgetBurn") {
+		t.Errorf("expected KubeAPIErrorBudgetBurn to fail, got %v", err)
+	}
+	if !strings.Contains(out.String(), "alert/Watchdog must have no gaps or changes") {
+		t.Errorf("expected the watchdog invariant in the junit, got %v", out.String())
+	}
+}
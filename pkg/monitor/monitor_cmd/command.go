@@ -2,11 +2,12 @@ package monitor_cmd
 
 import (
 	"archive/zip"
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -15,10 +16,10 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
+	"github.com/openshift/origin/pkg/monitor"
 	"github.com/openshift/origin/pkg/monitor/intervalcreation"
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
-	"github.com/openshift/origin/test/extended/testdata"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -29,6 +30,9 @@ type TimelineOptions struct {
 	MonitorEventFilename string
 	PodResourceFilename  string
 	TimelineType         string
+	// AlertFilenames and PrometheusURL replace the alerts in the monitor events, see Timeline.
+	AlertFilenames []string
+	PrometheusURL  string
 
 	LocatorMatchers []string
 	Namespaces      []string
@@ -37,11 +41,16 @@ type TimelineOptions struct {
 
 	KnownRenderers map[string]RenderFunc
 	KnownTimelines map[string]monitorapi.EventIntervalMatchesFunc
-	IOStreams      genericclioptions.IOStreams
+	// KnownTransforms are applied to the calculated intervals of the timeline type with the same name.
+	KnownTransforms  map[string]TransformFunc
+	IntervalCreators *intervalcreation.IntervalCreatorRegistry
+	IOStreams        genericclioptions.IOStreams
 }
 
 type RenderFunc func(intervals monitorapi.Intervals) ([]byte, error)
 
+type TransformFunc func(intervals monitorapi.Intervals) monitorapi.Intervals
+
 func NewTimelineOptions(ioStreams genericclioptions.IOStreams) *TimelineOptions {
 	return &TimelineOptions{
 		TimelineType: "spyglass",
@@ -54,12 +63,19 @@ func NewTimelineOptions(ioStreams genericclioptions.IOStreams) *TimelineOptions
 			"html": renderHTML,
 		},
 		KnownTimelines: map[string]monitorapi.EventIntervalMatchesFunc{
-			"everything":    intervalcreation.BelongsInEverything,
-			"operators":     intervalcreation.BelongsInOperatorRollout,
-			"apiserver":     intervalcreation.BelongsInKubeAPIServer,
-			"spyglass":      intervalcreation.BelongsInSpyglass,
-			"pod-lifecycle": intervalcreation.IsOriginalPodEvent,
+			"everything":     intervalcreation.BelongsInEverything,
+			"operators":      intervalcreation.BelongsInOperatorRollout,
+			"apiserver":      intervalcreation.BelongsInKubeAPIServer,
+			"spyglass":       intervalcreation.BelongsInSpyglass,
+			"pod-lifecycle":  intervalcreation.IsOriginalPodEvent,
+			"machine-config": intervalcreation.BelongsInMachineConfig,
+			"test":           intervalcreation.BelongsInE2ETest,
+			"metrics":        monitorapi.IsMetricThresholdExceeded,
+		},
+		KnownTransforms: map[string]TransformFunc{
+			"test": intervalcreation.GroupByE2ETest,
 		},
+		IntervalCreators: intervalcreation.NewDefaultIntervalCreatorRegistry(),
 	}
 }
 
@@ -73,6 +89,12 @@ func NewTimelineCommand(ioStreams genericclioptions.IOStreams) *cobra.Command {
 		Create a timeline html page based on the provided monitor events.
 
 		openshift-tests timeline --type=pod -f raw-monitor-events.json --namespace=openshift-kube-apiserver --namespace=openshift-kube-apiserver-operator -ojson 
+
+		The alerts can be read from ALERTS series exported before the cluster was torn down, or from a running
+		Prometheus that still has them, instead of from the monitor events.
+
+		openshift-tests timeline -f raw-monitor-events.json --alerts=alerts-query-range.json
+		openshift-tests timeline -f raw-monitor-events.json --prometheus-url=http://localhost:9090
 		`,
 
 		SilenceUsage:  true,
@@ -101,6 +123,8 @@ func (o *TimelineOptions) Bind(flagset *pflag.FlagSet) error {
 	flagset.StringSliceVar(&o.Namespaces, "namespace", o.Namespaces, "namespaces to filter.  No entry is no filtering.")
 	flagset.StringVarP(&o.OutputType, "output", "o", o.OutputType, fmt.Sprintf("type of output: [%s]", strings.Join(sets.StringKeySet(o.KnownRenderers).List(), ",")))
 	flagset.StringVar(&o.TimelineType, "type", o.TimelineType, "type of timeline to produce: "+strings.Join(sets.StringKeySet(o.KnownTimelines).List(), ","))
+	flagset.StringSliceVar(&o.AlertFilenames, "alerts", o.AlertFilenames, "ALERTS series exported with a range query with a step under five seconds, as the query_range response or its result.  Directories are read as all of the .json files in them.  Replaces the alerts in the monitor events.")
+	flagset.StringVar(&o.PrometheusURL, "prometheus-url", o.PrometheusURL, "The URL of a running Prometheus to read the alerts from.  Replaces the alerts in the monitor events.")
 	flagset.StringVar(&o.PodResourceFilename, "known-pods", o.PodResourceFilename, "resource-pods_<timestamp>.zip filename from openshift-tests.")
 	flagset.StringSliceVarP(&o.LocatorMatchers, "locator", "l", o.LocatorMatchers, "key=value selector for monitor event locators (where value is a regex).  for instance -lpod=openshift-etcd-installer.  The same key listed multiple times means an OR.  Each separate key is logically ANDed.  Precede value with a dash for anti-match")
 	flagset.StringVarP(&o.EndDate, "end-date", "e", o.EndDate, fmt.Sprintf("End date (default is one hour after latest event) in RFC3399 format in UTC timezone: %s", time.RFC3339))
@@ -130,6 +154,10 @@ func (o *TimelineOptions) Validate() error {
 		return fmt.Errorf("unknown --type")
 	}
 
+	if len(o.AlertFilenames) > 0 && len(o.PrometheusURL) > 0 {
+		return fmt.Errorf("--alerts and --prometheus-url are mutually exclusive")
+	}
+
 	for _, matcher := range o.LocatorMatchers {
 		if !strings.Contains(matcher, "=") {
 			return fmt.Errorf("invalid --locator format, must be key=value")
@@ -173,29 +201,40 @@ func (o *TimelineOptions) ToTimeline() *Timeline {
 	return &Timeline{
 		MonitorEventFilename: o.MonitorEventFilename,
 		PodResourceFilename:  o.PodResourceFilename,
+		AlertFilenames:       o.AlertFilenames,
+		PrometheusURL:        o.PrometheusURL,
 
 		LocatorMatcher:        locatorMatcher,
 		RemovedLocatorMatcher: inverseLocatorMatcher,
 		Namespaces:            o.Namespaces,
 		EndDate:               endDateTime,
 
-		Renderer:       o.KnownRenderers[o.OutputType],
-		TimelineFilter: o.KnownTimelines[o.TimelineType],
-		IOStreams:      o.IOStreams,
+		Renderer:         o.KnownRenderers[o.OutputType],
+		TimelineFilter:   o.KnownTimelines[o.TimelineType],
+		Transform:        o.KnownTransforms[o.TimelineType],
+		IntervalCreators: o.IntervalCreators,
+		IOStreams:        o.IOStreams,
 	}
 }
 
 type Timeline struct {
 	MonitorEventFilename string
 	PodResourceFilename  string
+	// AlertFilenames are exported ALERTS series to read the alerts from instead of the monitor events.
+	AlertFilenames []string
+	// PrometheusURL is a Prometheus to read the alerts from instead of the monitor events, for the time the events
+	// cover.
+	PrometheusURL string
 
 	LocatorMatcher        map[string][]*regexp.Regexp
 	RemovedLocatorMatcher map[string][]*regexp.Regexp
 	Namespaces            []string
 	EndDate               *time.Time
 
-	Renderer       RenderFunc
-	TimelineFilter monitorapi.EventIntervalMatchesFunc
+	Renderer         RenderFunc
+	TimelineFilter   monitorapi.EventIntervalMatchesFunc
+	Transform        TransformFunc
+	IntervalCreators *intervalcreation.IntervalCreatorRegistry
 
 	IOStreams genericclioptions.IOStreams
 }
@@ -206,6 +245,11 @@ func (o *Timeline) Run() error {
 		return err
 	}
 
+	consumedEvents, err = o.replaceAlerts(consumedEvents)
+	if err != nil {
+		return err
+	}
+
 	recordedResources := monitorapi.ResourcesMap{}
 	if len(o.PodResourceFilename) > 0 {
 		recordedResources, err = loadKnownPods(o.PodResourceFilename)
@@ -245,7 +289,10 @@ func (o *Timeline) Run() error {
 		to = *o.EndDate
 	}
 
-	filteredEvents = intervalcreation.InsertCalculatedIntervals(filteredEvents, recordedResources, from, to)
+	filteredEvents = o.IntervalCreators.InsertCalculatedIntervals(filteredEvents, recordedResources, from, to)
+	if o.Transform != nil {
+		filteredEvents = o.Transform(filteredEvents)
+	}
 
 	output, err := o.Renderer(filteredEvents)
 	if err != nil {
@@ -259,18 +306,52 @@ func (o *Timeline) Run() error {
 	return nil
 }
 
-func renderHTML(events monitorapi.Intervals) ([]byte, error) {
-	eventIntervalsJSON, err := monitorserialization.EventsIntervalsToJSON(events)
-	if err != nil {
-		return nil, err
+// replaceAlerts reads the alerts from the exported series or the Prometheus when either is set, so that the alerts of a
+// cluster that no longer exists can be shown even when the run could not fetch them.
+func (o *Timeline) replaceAlerts(events monitorapi.Intervals) (monitorapi.Intervals, error) {
+	return replaceAlerts(events, o.AlertFilenames, o.PrometheusURL)
+}
 
+// replaceAlerts replaces the alerts in the events with the alerts in the exported ALERTS series, or with the alerts the
+// Prometheus had while the events were recorded.  The events are returned as they are when neither is set.
+func replaceAlerts(events monitorapi.Intervals, alertFilenames []string, prometheusURL string) (monitorapi.Intervals, error) {
+	var alerts []monitorapi.EventInterval
+	switch {
+	case len(alertFilenames) > 0:
+		var err error
+		alerts, err = monitor.EventIntervalsForAlertsFromFiles(context.TODO(), alertFilenames...)
+		if err != nil {
+			return nil, err
+		}
+
+	case len(prometheusURL) > 0:
+		if len(events) == 0 {
+			return events, nil
+		}
+		from, to := events.Span()
+		prometheusClient, err := monitor.NewPrometheusClientForURL(prometheusURL)
+		if err != nil {
+			return nil, err
+		}
+		alerts, err = monitor.FetchEventIntervalsForAllAlertsFromPrometheus(context.TODO(), prometheusClient, from, to)
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		return events, nil
 	}
-	e2eChartTemplate := testdata.MustAsset("e2echart/e2e-chart-template.html")
-	e2eChartTitle := "Timeline"
-	e2eChartHTML := bytes.ReplaceAll(e2eChartTemplate, []byte("EVENT_INTERVAL_TITLE_GOES_HERE"), []byte(e2eChartTitle))
-	e2eChartHTML = bytes.ReplaceAll(e2eChartHTML, []byte("EVENT_INTERVAL_JSON_GOES_HERE"), eventIntervalsJSON)
 
-	return e2eChartHTML, nil
+	ret := events.Filter(func(eventInterval monitorapi.EventInterval) bool {
+		return !strings.HasPrefix(eventInterval.Locator, "alert/")
+	})
+	ret = append(ret, alerts...)
+	sort.Sort(ret)
+	return ret, nil
+}
+
+func renderHTML(events monitorapi.Intervals) ([]byte, error) {
+	return intervalcreation.E2EChartHTML("Timeline", events)
 }
 
 func loadKnownPods(filename string) (monitorapi.ResourcesMap, error) {
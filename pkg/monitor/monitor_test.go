@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/diff"
 )
 
@@ -110,3 +112,49 @@ func TestMonitor_Events(t *testing.T) {
 		})
 	}
 }
+
+func TestMonitor_RecordResource(t *testing.T) {
+	m := NewMonitor()
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-etcd", Name: "etcd-endpoints", UID: "uid-1"}}
+	for i := 0; i < 3; i++ {
+		m.RecordResource("configmaps", configMap)
+	}
+	if len(configMap.Annotations) > 0 {
+		t.Errorf("expected the recorded object to be left alone, got %v", configMap.Annotations)
+	}
+
+	recorded := m.CurrentResourceState()["configmaps"][monitorapi.InstanceKey{Namespace: "openshift-etcd", Name: "etcd-endpoints", UID: "uid-1"}]
+	if recorded == nil {
+		t.Fatalf("missing recorded configmap")
+	}
+	annotations := recorded.(*corev1.ConfigMap).Annotations
+	if got := annotations[monitorapi.ObservedUpdateCountAnnotation]; got != "3" {
+		t.Errorf("expected 3 updates, got %q", got)
+	}
+	if got := annotations[monitorapi.ObservedRecreationCountAnnotation]; got != "0" {
+		t.Errorf("expected 0 recreations, got %q", got)
+	}
+}
+
+func TestMonitor_RecordResourceInitialRestartCount(t *testing.T) {
+	m := NewMonitor()
+	pod := func(restarts int32) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-etcd", Name: "etcd-master-0", UID: "uid-1"},
+			Status: corev1.PodStatus{
+				InitContainerStatuses: []corev1.ContainerStatus{{Name: "setup", RestartCount: 1}},
+				ContainerStatuses:     []corev1.ContainerStatus{{Name: "etcd", RestartCount: restarts}},
+			},
+		}
+	}
+	m.RecordResource("pods", pod(2))
+	m.RecordResource("pods", pod(5))
+
+	recorded := m.CurrentResourceState()["pods"][monitorapi.InstanceKey{Namespace: "openshift-etcd", Name: "etcd-master-0", UID: "uid-1"}]
+	if recorded == nil {
+		t.Fatalf("missing recorded pod")
+	}
+	if got := recorded.(*corev1.Pod).Annotations[monitorapi.ObservedInitialRestartCountAnnotation]; got != "3" {
+		t.Errorf("expected the restarts when the pod was first seen, got %q", got)
+	}
+}
@@ -6,6 +6,9 @@ import (
 	"strings"
 )
 
+// E2ETestReasonNamespaceOwner is the calculated interval attributing an e2e namespace to the test that created it.
+const E2ETestReasonNamespaceOwner = "E2ETestNamespace"
+
 func E2ETestLocator(testName string) string {
 	return fmt.Sprintf("e2e-test/%q", testName)
 }
@@ -32,6 +35,16 @@ func NodeLocator(testName string) string {
 	return fmt.Sprintf("node/%v", testName)
 }
 
+// NodeDeviceLocator identifies a network device on a node.
+func NodeDeviceLocator(nodeName, device string) string {
+	return fmt.Sprintf("node/%v device/%v", nodeName, device)
+}
+
+// NodeUnitLocator identifies a systemd unit on a node.
+func NodeUnitLocator(nodeName, unit string) string {
+	return fmt.Sprintf("node/%v unit/%v", nodeName, unit)
+}
+
 func IsNode(locator string) bool {
 	_, ret := NodeFromLocator(locator)
 	return ret
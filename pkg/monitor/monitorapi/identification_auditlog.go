@@ -0,0 +1,39 @@
+package monitorapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// AuditLogReasonRequestSummary is the calculated interval holding the request statistics of a user agent for the
+	// entire run, read from the kube-apiserver audit logs.
+	AuditLogReasonRequestSummary = "AuditLogRequestSummary"
+	// AuditLogReasonThrottled is the calculated interval for requests of a user agent being rejected with a 429.
+	AuditLogReasonThrottled = "APIRequestsThrottled"
+	// AuditLogReasonServerErrors is the calculated interval for requests of a user agent failing with a 5xx.
+	AuditLogReasonServerErrors = "APIServerErrors"
+)
+
+// UserAgentFrom shortens a user agent like "kube-controller-manager/v1.24.0 (linux/amd64) kubernetes/abcdef/..." to
+// the name of the client, which is what we group requests by.
+func UserAgentFrom(userAgent string) string {
+	name := strings.SplitN(userAgent, "/", 2)[0]
+	name = strings.ReplaceAll(strings.TrimSpace(name), " ", "_")
+	if len(name) == 0 {
+		return "unknown"
+	}
+	return name
+}
+
+func UserAgentLocator(userAgent string) string {
+	return fmt.Sprintf("user-agent/%v", UserAgentFrom(userAgent))
+}
+
+func UserAgentFromLocator(locator string) (string, bool) {
+	if !strings.HasPrefix(locator, "user-agent/") {
+		return "", false
+	}
+	parts := strings.SplitN(strings.TrimPrefix(locator, "user-agent/"), " ", 2)
+	return parts[0], true
+}
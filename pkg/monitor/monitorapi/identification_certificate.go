@@ -0,0 +1,38 @@
+package monitorapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// CertificateReasonObserved means the monitor saw the serving or client certificate in a TLS secret for the first
+	// time.
+	CertificateReasonObserved = "CertificateObserved"
+	// CertificateReasonRotated means the certificate in a TLS secret was replaced by a different certificate.
+	CertificateReasonRotated = "CertificateRotated"
+	// CertificateReasonRotation is the calculated interval during which both the previous and the new certificate were
+	// valid, or the gap between them if they were not.
+	CertificateReasonRotation = "CertificateRotation"
+
+	// CABundleReasonObserved means the monitor saw a CA bundle for the first time.
+	CABundleReasonObserved = "CABundleObserved"
+	// CABundleReasonRotated means certificates were added to or removed from a CA bundle.
+	CABundleReasonRotated = "CABundleRotated"
+)
+
+func SecretLocator(namespace, name string) string {
+	return fmt.Sprintf("ns/%v secret/%v", namespace, name)
+}
+
+func ConfigMapLocator(namespace, name string) string {
+	return fmt.Sprintf("ns/%v configmap/%v", namespace, name)
+}
+
+// IsCertificateHolder returns true for the secrets and configmaps that certificates are recorded for.
+func IsCertificateHolder(locator string) bool {
+	parts := LocatorParts(locator)
+	_, isSecret := parts["secret"]
+	_, isConfigMap := parts["configmap"]
+	return strings.HasPrefix(locator, "ns/") && (isSecret || isConfigMap)
+}
@@ -0,0 +1,39 @@
+package monitorapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// LeaseReasonHolderObserved means the monitor saw the holder of a lease for the first time.
+	LeaseReasonHolderObserved = "LeaseHolderObserved"
+	// LeaseReasonHolderChanged means a lease was acquired by a different holder.
+	LeaseReasonHolderChanged = "LeaseHolderChanged"
+	// LeaseReasonHolderReleased means the holder of a lease gave it up, usually while shutting down gracefully.
+	LeaseReasonHolderReleased = "LeaseHolderReleased"
+	// LeaseReasonHolder is the calculated interval for a single holder of a lease.
+	LeaseReasonHolder = "LeaseHolder"
+)
+
+func LeaseLocator(namespace, name string) string {
+	return fmt.Sprintf("ns/%v lease/%v", namespace, name)
+}
+
+func IsLease(locator string) bool {
+	_, _, ret := LeaseFromLocator(locator)
+	return ret
+}
+
+// LeaseFromLocator returns the namespace and name of the lease.
+func LeaseFromLocator(locator string) (string, string, bool) {
+	if !strings.Contains(locator, "lease/") {
+		return "", "", false
+	}
+	parts := LocatorParts(locator)
+	name, ok := parts["lease"]
+	if !ok {
+		return "", "", false
+	}
+	return NamespaceFrom(parts), name, true
+}
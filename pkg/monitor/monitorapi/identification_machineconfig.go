@@ -0,0 +1,54 @@
+package monitorapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// NodeReasonMachineConfigState means the machine-config-daemon changed the state annotation on the node.
+	NodeReasonMachineConfigState = "MachineConfigState"
+	// NodeReasonMachineConfigDrainRequested means the machine-config-daemon asked for the node to be drained.
+	NodeReasonMachineConfigDrainRequested = "MachineConfigDrainRequested"
+	// NodeReasonMachineConfigDrainComplete means the requested drain of the node completed.
+	NodeReasonMachineConfigDrainComplete = "MachineConfigDrainComplete"
+	// NodeReasonMachineConfigNodeUpdate is the calculated interval for each phase of a node applying a machine config.
+	NodeReasonMachineConfigNodeUpdate = "MachineConfigNodeUpdate"
+
+	// MachineConfigPoolReasonUpdating is the calculated interval for a pool rolling out a new config.
+	MachineConfigPoolReasonUpdating = "MachineConfigPoolUpdating"
+	// MachineConfigPoolReasonDegraded is the calculated interval for a pool reporting Degraded.
+	MachineConfigPoolReasonDegraded = "MachineConfigPoolDegraded"
+
+	// ClusterVersionReasonUpdateStarted means a new entry was added to the ClusterVersion history.
+	ClusterVersionReasonUpdateStarted = "UpdateStarted"
+	// ClusterVersionReasonUpdateCompleted means the newest ClusterVersion history entry completed.
+	ClusterVersionReasonUpdateCompleted = "UpdateCompleted"
+	// ClusterVersionReasonUpdate is the calculated interval for a ClusterVersion history entry.
+	ClusterVersionReasonUpdate = "ClusterVersionUpdate"
+)
+
+func MachineConfigPoolLocator(poolName string) string {
+	return fmt.Sprintf("machineconfigpool/%v", poolName)
+}
+
+func IsMachineConfigPool(locator string) bool {
+	_, ret := MachineConfigPoolFromLocator(locator)
+	return ret
+}
+
+func MachineConfigPoolFromLocator(locator string) (string, bool) {
+	if !strings.HasPrefix(locator, "machineconfigpool/") {
+		return "", false
+	}
+	parts := strings.SplitN(strings.TrimPrefix(locator, "machineconfigpool/"), " ", 2)
+	return parts[0], true
+}
+
+func ClusterVersionLocator(name string) string {
+	return fmt.Sprintf("clusterversion/%v", name)
+}
+
+func IsClusterVersion(locator string) bool {
+	return strings.HasPrefix(locator, "clusterversion/")
+}
@@ -0,0 +1,24 @@
+package monitorapi
+
+import (
+	"strings"
+)
+
+const (
+	// MetricReasonThresholdExceeded is the calculated interval for a Prometheus series being above a threshold.
+	MetricReasonThresholdExceeded = "MetricThresholdExceeded"
+)
+
+// MetricFromLocator returns the name of the metric threshold, like apiserver-request-latency-p99.
+func MetricFromLocator(locator string) (string, bool) {
+	ret := LocatorParts(locator)["metric"]
+	return ret, len(ret) > 0
+}
+
+// IsMetricThresholdExceeded returns true for the intervals of a metric being above its threshold.
+func IsMetricThresholdExceeded(eventInterval EventInterval) bool {
+	if _, ok := MetricFromLocator(eventInterval.Locator); !ok {
+		return false
+	}
+	return strings.HasPrefix(eventInterval.Message, "reason/"+MetricReasonThresholdExceeded+" ")
+}
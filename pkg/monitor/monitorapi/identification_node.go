@@ -2,6 +2,8 @@ package monitorapi
 
 import (
 	"strings"
+
+	corev1 "k8s.io/api/core/v1"
 )
 
 // GetNodeRoles extract the node roles from the event message.
@@ -16,3 +18,33 @@ func GetNodeRoles(event EventInterval) string {
 
 	return roles
 }
+
+const (
+	// NodeReasonLinkDown means a network link on the node lost carrier.
+	NodeReasonLinkDown = "LinkDown"
+	// NodeReasonUnitFailed means systemd reported a unit on the node as failed.
+	NodeReasonUnitFailed = "UnitFailed"
+	// NodeReasonUnitRestarted means systemd restarted a unit on the node.
+	NodeReasonUnitRestarted = "UnitRestarted"
+	// NodeReasonAllocatableChanged means the allocatable resources reported by the kubelet changed.
+	NodeReasonAllocatableChanged = "AllocatableChanged"
+	// NodeReasonResourcePressure is the calculated interval for a node reporting a memory, disk or PID pressure condition.
+	NodeReasonResourcePressure = "NodeResourcePressure"
+)
+
+// NodePressureConditions are the node conditions the kubelet sets when it starts evicting pods to reclaim resources.
+var NodePressureConditions = []corev1.NodeConditionType{
+	corev1.NodeMemoryPressure,
+	corev1.NodeDiskPressure,
+	corev1.NodePIDPressure,
+}
+
+// IsNodePressureCondition returns true for the node conditions in NodePressureConditions.
+func IsNodePressureCondition(conditionType corev1.NodeConditionType) bool {
+	for _, pressure := range NodePressureConditions {
+		if conditionType == pressure {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package monitorapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// PodDisruptionBudgetReasonStatus means the monitor saw a new status for a pod disruption budget.
+	PodDisruptionBudgetReasonStatus = "PodDisruptionBudgetStatus"
+	// PodDisruptionBudgetReasonViolated is the calculated interval for fewer pods being healthy than a pod disruption
+	// budget requires.
+	PodDisruptionBudgetReasonViolated = "PodDisruptionBudgetViolated"
+)
+
+func PodDisruptionBudgetLocator(namespace, name string) string {
+	return fmt.Sprintf("ns/%v pdb/%v", namespace, name)
+}
+
+func IsPodDisruptionBudget(locator string) bool {
+	_, _, ret := PodDisruptionBudgetFromLocator(locator)
+	return ret
+}
+
+// PodDisruptionBudgetFromLocator returns the namespace and name of the pod disruption budget.
+func PodDisruptionBudgetFromLocator(locator string) (string, string, bool) {
+	if !strings.Contains(locator, "pdb/") {
+		return "", "", false
+	}
+	parts := LocatorParts(locator)
+	name, ok := parts["pdb"]
+	if !ok {
+		return "", "", false
+	}
+	return NamespaceFrom(parts), name, true
+}
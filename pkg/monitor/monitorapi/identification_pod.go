@@ -112,6 +112,8 @@ const (
 	PodReasonGracefulDeleteStarted = "GracefulDelete"
 	PodReasonDeleted               = "Deleted"
 	PodReasonScheduled             = "Scheduled"
+	PodReasonSandboxCreated        = "SandboxCreated"
+	PodReasonSandboxDestroyed      = "SandboxDestroyed"
 
 	ContainerReasonContainerExit    = "ContainerExit"
 	ContainerReasonContainerStart   = "ContainerStart"
@@ -123,6 +125,12 @@ const (
 
 	PodReasonDeletedBeforeScheduling = "DeletedBeforeScheduling"
 	PodReasonDeletedAfterCompletion  = "DeletedAfterCompletion"
+
+	EtcdReasonLeaderElected = "LeaderElected"
+	EtcdReasonLeaderChanged = "LeaderChanged"
+	EtcdReasonLeaderMissing = "LeaderMissing"
+
+	KubeAPIServerReasonGracefulShutdown = "GracefulShutdown"
 )
 
 var (
@@ -22,6 +22,11 @@ const (
 	// time a resource has been recreated.  The internal cache doesn't remove an entry on delete.
 	// This is useful during post-processing for determining if we have a hot resource.
 	ObservedRecreationCountAnnotation = "monitor.openshift.io/observed-recreation-count"
+
+	// ObservedInitialRestartCountAnnotation is an annotation added locally (in the monitor only) to pods, that tracks
+	// the sum of the container restart counts when the pod was first seen.  Restarts before the monitor started are
+	// subtracted from the restart counts of the last recorded state when post-processing.
+	ObservedInitialRestartCountAnnotation = "monitor.openshift.io/observed-initial-restart-count"
 )
 
 type EventLevel int
@@ -165,6 +170,20 @@ func (intervals Intervals) Duration(minCurrentDuration time.Duration) time.Durat
 	return totalDuration
 }
 
+// Span returns the earliest start and the latest end of the intervals, or zero times when there are none.
+func (intervals Intervals) Span() (time.Time, time.Time) {
+	var from, to time.Time
+	for i, interval := range intervals {
+		if i == 0 || interval.From.Before(from) {
+			from = interval.From
+		}
+		if interval.To.After(to) {
+			to = interval.To
+		}
+	}
+	return from, to
+}
+
 // EventIntervalMatchesFunc is a function for matching eventIntervales
 type EventIntervalMatchesFunc func(eventInterval EventInterval) bool
 
@@ -106,3 +106,23 @@ func TestIntervals_Duration(t *testing.T) {
 		})
 	}
 }
+
+func TestIntervals_Span(t *testing.T) {
+	start := time.Unix(1000, 0)
+	intervals := Intervals{
+		{From: start.Add(time.Minute), To: start.Add(2 * time.Minute)},
+		{From: start, To: start.Add(time.Minute)},
+		// still open
+		{From: start.Add(3 * time.Minute)},
+		{From: start.Add(time.Minute), To: start.Add(5 * time.Minute)},
+	}
+	from, to := intervals.Span()
+	if !from.Equal(start) || !to.Equal(start.Add(5*time.Minute)) {
+		t.Errorf("unexpected span %v to %v", from, to)
+	}
+
+	from, to = Intervals{}.Span()
+	if !from.IsZero() || !to.IsZero() {
+		t.Errorf("expected zero times, got %v to %v", from, to)
+	}
+}
@@ -0,0 +1,220 @@
+package monitor
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// namespacedInformerHandler adds event handlers to the informers of a single namespace.  It is called once for every
+// watched namespace, before the informers are started.
+type namespacedInformerHandler func(namespace string, factory informers.SharedInformerFactory)
+
+// openshiftNamespaceInformers runs one informer factory for every openshift-* namespace, for as long as the namespace
+// exists, so that the monitors that only care about the platform never list or watch the whole cluster.  Every monitor
+// asking for the same resource in a namespace shares the same informer.
+type openshiftNamespaceInformers struct {
+	recorder Recorder
+	client   kubernetes.Interface
+
+	lock     sync.Mutex
+	handlers []namespacedInformerHandler
+	// namespaceToCancel stops the informers of a namespace when it is deleted.
+	namespaceToCancel map[string]context.CancelFunc
+}
+
+func newOpenShiftNamespaceInformers(recorder Recorder, client kubernetes.Interface) *openshiftNamespaceInformers {
+	return &openshiftNamespaceInformers{
+		recorder:          recorder,
+		client:            client,
+		namespaceToCancel: map[string]context.CancelFunc{},
+	}
+}
+
+// AddHandler must be called before Start.
+func (i *openshiftNamespaceInformers) AddHandler(handler namespacedInformerHandler) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.handlers = append(i.handlers, handler)
+}
+
+// Start watches the namespaces and starts the informers of every openshift-* namespace until the context is done.
+func (i *openshiftNamespaceInformers) Start(ctx context.Context) {
+	namespaceInformer := cache.NewSharedIndexInformer(
+		NewErrorRecordingListWatcher(i.recorder, &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return i.client.CoreV1().Namespaces().List(ctx, options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return i.client.CoreV1().Namespaces().Watch(ctx, options)
+			},
+		}),
+		&corev1.Namespace{},
+		time.Hour,
+		nil,
+	)
+	namespaceInformer.AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				namespace, ok := obj.(*corev1.Namespace)
+				if !ok {
+					return
+				}
+				i.startNamespace(ctx, namespace.Name)
+			},
+			DeleteFunc: func(obj interface{}) {
+				if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+					obj = tombstone.Obj
+				}
+				namespace, ok := obj.(*corev1.Namespace)
+				if !ok {
+					return
+				}
+				i.stopNamespace(namespace.Name)
+			},
+		},
+	)
+
+	go namespaceInformer.Run(ctx.Done())
+}
+
+func (i *openshiftNamespaceInformers) startNamespace(ctx context.Context, namespace string) {
+	if !strings.HasPrefix(namespace, "openshift-") {
+		return
+	}
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	if _, ok := i.namespaceToCancel[namespace]; ok {
+		return
+	}
+
+	namespaceCtx, cancel := context.WithCancel(ctx)
+	i.namespaceToCancel[namespace] = cancel
+	factory := informers.NewSharedInformerFactoryWithOptions(i.client, time.Hour, informers.WithNamespace(namespace))
+	for _, handler := range i.handlers {
+		handler(namespace, factory)
+	}
+	factory.Start(namespaceCtx.Done())
+}
+
+func (i *openshiftNamespaceInformers) stopNamespace(namespace string) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	if cancel, ok := i.namespaceToCancel[namespace]; ok {
+		cancel()
+		delete(i.namespaceToCancel, namespace)
+	}
+}
+
+// ConfigMapInformer returns the configmap informer of the namespace.  Everything but the CA bundle is dropped before
+// the configmaps are cached.
+func (i *openshiftNamespaceInformers) ConfigMapInformer(namespace string, factory informers.SharedInformerFactory) cache.SharedIndexInformer {
+	return factory.InformerFor(&corev1.ConfigMap{}, func(client kubernetes.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+		informer := cache.NewSharedIndexInformer(
+			NewErrorRecordingListWatcher(i.recorder, &cache.ListWatch{
+				ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+					return client.CoreV1().ConfigMaps(namespace).List(context.TODO(), options)
+				},
+				WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+					return client.CoreV1().ConfigMaps(namespace).Watch(context.TODO(), options)
+				},
+			}),
+			&corev1.ConfigMap{},
+			resyncPeriod,
+			cache.Indexers{},
+		)
+		if err := informer.SetTransform(func(obj interface{}) (interface{}, error) {
+			if configMap, ok := obj.(*corev1.ConfigMap); ok {
+				keepOnlyCABundle(configMap)
+			}
+			return obj, nil
+		}); err != nil {
+			// only fails once the informer is started
+			panic(err)
+		}
+		return informer
+	})
+}
+
+// TLSSecretInformer returns the informer of the TLS secrets of the namespace.  Everything but the certificate is
+// dropped before the secrets are cached, the private keys are never kept.
+func (i *openshiftNamespaceInformers) TLSSecretInformer(namespace string, factory informers.SharedInformerFactory) cache.SharedIndexInformer {
+	tlsSecretSelector := fields.OneTermEqualSelector("type", string(corev1.SecretTypeTLS)).String()
+	return factory.InformerFor(&corev1.Secret{}, func(client kubernetes.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+		informer := cache.NewSharedIndexInformer(
+			NewErrorRecordingListWatcher(i.recorder, &cache.ListWatch{
+				ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+					options.FieldSelector = tlsSecretSelector
+					return client.CoreV1().Secrets(namespace).List(context.TODO(), options)
+				},
+				WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+					options.FieldSelector = tlsSecretSelector
+					return client.CoreV1().Secrets(namespace).Watch(context.TODO(), options)
+				},
+			}),
+			&corev1.Secret{},
+			resyncPeriod,
+			cache.Indexers{},
+		)
+		if err := informer.SetTransform(func(obj interface{}) (interface{}, error) {
+			if secret, ok := obj.(*corev1.Secret); ok {
+				keepOnlyCertificate(secret)
+			}
+			return obj, nil
+		}); err != nil {
+			// only fails once the informer is started
+			panic(err)
+		}
+		return informer
+	})
+}
+
+// LeaseInformer returns the lease informer of the namespace.
+func (i *openshiftNamespaceInformers) LeaseInformer(namespace string, factory informers.SharedInformerFactory) cache.SharedIndexInformer {
+	return factory.InformerFor(&coordinationv1.Lease{}, func(client kubernetes.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+		return cache.NewSharedIndexInformer(
+			NewErrorRecordingListWatcher(i.recorder, &cache.ListWatch{
+				ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+					return client.CoordinationV1().Leases(namespace).List(context.TODO(), options)
+				},
+				WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+					return client.CoordinationV1().Leases(namespace).Watch(context.TODO(), options)
+				},
+			}),
+			&coordinationv1.Lease{},
+			resyncPeriod,
+			cache.Indexers{},
+		)
+	})
+}
+
+// PodDisruptionBudgetInformer returns the pod disruption budget informer of the namespace.
+func (i *openshiftNamespaceInformers) PodDisruptionBudgetInformer(namespace string, factory informers.SharedInformerFactory) cache.SharedIndexInformer {
+	return factory.InformerFor(&policyv1.PodDisruptionBudget{}, func(client kubernetes.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+		return cache.NewSharedIndexInformer(
+			NewErrorRecordingListWatcher(i.recorder, &cache.ListWatch{
+				ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+					return client.PolicyV1().PodDisruptionBudgets(namespace).List(context.TODO(), options)
+				},
+				WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+					return client.PolicyV1().PodDisruptionBudgets(namespace).Watch(context.TODO(), options)
+				},
+			}),
+			&policyv1.PodDisruptionBudget{},
+			resyncPeriod,
+			cache.Indexers{},
+		)
+	})
+}
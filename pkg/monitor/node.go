@@ -69,12 +69,88 @@ func startNodeMonitoring(ctx context.Context, m Recorder, client kubernetes.Inte
 			}
 			return conditions
 		},
+		func(node, oldNode *corev1.Node) []monitorapi.Condition {
+			var conditions []monitorapi.Condition
+			roles := nodeRoles(node)
+
+			oldState := oldNode.Annotations["machineconfiguration.openshift.io/state"]
+			newState := node.Annotations["machineconfiguration.openshift.io/state"]
+			if len(newState) > 0 && newState != oldState {
+				level := monitorapi.Info
+				if newState == "Degraded" || newState == "Unreconcilable" {
+					level = monitorapi.Error
+				}
+				conditions = append(conditions, monitorapi.Condition{
+					Level:   level,
+					Locator: monitorapi.NodeLocator(node.Name),
+					Message: strings.TrimSpace(fmt.Sprintf("reason/%s state/%s config/%s roles/%s %s", monitorapi.NodeReasonMachineConfigState,
+						newState, node.Annotations["machineconfiguration.openshift.io/desiredConfig"], roles,
+						node.Annotations["machineconfiguration.openshift.io/reason"])),
+				})
+			}
+
+			// drains are requested by setting desiredDrain to drain-<config> and acknowledged by the controller
+			// copying it to lastAppliedDrain.  uncordon-<config> requests are not interesting on their own.
+			oldDesiredDrain := oldNode.Annotations["machineconfiguration.openshift.io/desiredDrain"]
+			newDesiredDrain := node.Annotations["machineconfiguration.openshift.io/desiredDrain"]
+			oldAppliedDrain := oldNode.Annotations["machineconfiguration.openshift.io/lastAppliedDrain"]
+			newAppliedDrain := node.Annotations["machineconfiguration.openshift.io/lastAppliedDrain"]
+			if newDesiredDrain != oldDesiredDrain && strings.HasPrefix(newDesiredDrain, "drain-") {
+				conditions = append(conditions, monitorapi.Condition{
+					Level:   monitorapi.Info,
+					Locator: monitorapi.NodeLocator(node.Name),
+					Message: fmt.Sprintf("reason/%s drain/%s roles/%s drain requested", monitorapi.NodeReasonMachineConfigDrainRequested, newDesiredDrain, roles),
+				})
+			}
+			if newAppliedDrain != oldAppliedDrain && newAppliedDrain == newDesiredDrain && strings.HasPrefix(newAppliedDrain, "drain-") {
+				conditions = append(conditions, monitorapi.Condition{
+					Level:   monitorapi.Info,
+					Locator: monitorapi.NodeLocator(node.Name),
+					Message: fmt.Sprintf("reason/%s drain/%s roles/%s drain complete", monitorapi.NodeReasonMachineConfigDrainComplete, newAppliedDrain, roles),
+				})
+			}
+			return conditions
+		},
+		func(node, oldNode *corev1.Node) []monitorapi.Condition {
+			var changes []string
+			for _, resourceName := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory, corev1.ResourceEphemeralStorage, corev1.ResourcePods} {
+				oldQuantity, newQuantity := oldNode.Status.Allocatable[resourceName], node.Status.Allocatable[resourceName]
+				if oldQuantity.Cmp(newQuantity) != 0 {
+					changes = append(changes, fmt.Sprintf("%s/%s->%s", resourceName, oldQuantity.String(), newQuantity.String()))
+				}
+			}
+			if len(changes) == 0 {
+				return nil
+			}
+			return []monitorapi.Condition{
+				{
+					Level:   monitorapi.Info,
+					Locator: monitorapi.NodeLocator(node.Name),
+					Message: fmt.Sprintf("reason/%s %s roles/%s allocatable changed", monitorapi.NodeReasonAllocatableChanged, strings.Join(changes, " "), nodeRoles(node)),
+				},
+			}
+		},
 	}
 
 	nodeInformer := informercorev1.NewNodeInformer(client, time.Hour, nil)
 	nodeInformer.AddEventHandler(
 		cache.ResourceEventHandlerFuncs{
-			AddFunc: func(obj interface{}) {},
+			AddFunc: func(obj interface{}) {
+				node, ok := obj.(*corev1.Node)
+				if !ok {
+					return
+				}
+				// nodes already under pressure when we start will never report a transition, so record them now
+				for _, c := range node.Status.Conditions {
+					if monitorapi.IsNodePressureCondition(c.Type) && c.Status == corev1.ConditionTrue {
+						m.Record(monitorapi.Condition{
+							Level:   monitorapi.Warning,
+							Locator: monitorapi.NodeLocator(node.Name),
+							Message: fmt.Sprintf("condition/%s status/%s reason/%s roles/%s observed", c.Type, c.Status, c.Reason, nodeRoles(node)),
+						})
+					}
+				}
+			},
 			DeleteFunc: func(obj interface{}) {
 				node, ok := obj.(*corev1.Node)
 				if !ok {
@@ -2,6 +2,7 @@ package nodedetails
 
 import (
 	"context"
+	"io"
 	"io/ioutil"
 
 	"k8s.io/client-go/kubernetes"
@@ -27,3 +28,15 @@ func GetNodeLog(ctx context.Context, client kubernetes.Interface, nodeName, syst
 
 	return ioutil.ReadAll(in)
 }
+
+// StreamNodeLogFile returns a stream of a file under /var/log on a given node, like kube-apiserver/audit.log.  Unlike
+// GetNodeLog, the file is not read into memory, so this is suitable for large files.
+func StreamNodeLogFile(ctx context.Context, client kubernetes.Interface, nodeName, filePath string) (io.ReadCloser, error) {
+	path := client.CoreV1().RESTClient().Get().
+		Namespace("").Name(nodeName).
+		Resource("nodes").SubResource("proxy", "logs").Suffix(filePath).URL().Path
+
+	req := client.CoreV1().RESTClient().Get().RequestURI(path).
+		SetHeader("Accept", "text/plain, */*")
+	return req.Stream(ctx)
+}
@@ -213,6 +213,7 @@ func startClusterOperatorMonitoring(ctx context.Context, m Recorder, client conf
 			}
 			return conditions
 		},
+		clusterVersionHistoryChanges,
 	}
 
 	cvInformer.AddEventHandler(
@@ -294,7 +295,38 @@ func versionOrImage(h configv1.UpdateHistory) string {
 }
 
 func locateClusterVersion(cv *configv1.ClusterVersion) string {
-	return fmt.Sprintf("clusterversion/%s", cv.Name)
+	return monitorapi.ClusterVersionLocator(cv.Name)
+}
+
+// clusterVersionHistoryChanges records the start and completion of every ClusterVersion history entry so the
+// update can be turned into an interval.
+func clusterVersionHistoryChanges(cv, oldCV *configv1.ClusterVersion) []monitorapi.Condition {
+	if len(cv.Status.History) == 0 {
+		return nil
+	}
+	cvNew := cv.Status.History[0]
+	from := "unknown"
+	if len(cv.Status.History) > 1 {
+		from = versionOrImage(cv.Status.History[1])
+	}
+
+	var conditions []monitorapi.Condition
+	isNewEntry := len(oldCV.Status.History) == 0 || oldCV.Status.History[0].Image != cvNew.Image
+	if isNewEntry {
+		conditions = append(conditions, monitorapi.Condition{
+			Level:   monitorapi.Info,
+			Locator: locateClusterVersion(cv),
+			Message: monitorapi.ReasonedMessagef(monitorapi.ClusterVersionReasonUpdateStarted, "from/%s to/%s started update", from, versionOrImage(cvNew)),
+		})
+	}
+	if cvNew.State == configv1.CompletedUpdate && (isNewEntry || oldCV.Status.History[0].State != configv1.CompletedUpdate) {
+		conditions = append(conditions, monitorapi.Condition{
+			Level:   monitorapi.Info,
+			Locator: locateClusterVersion(cv),
+			Message: monitorapi.ReasonedMessagef(monitorapi.ClusterVersionReasonUpdateCompleted, "from/%s to/%s completed update", from, versionOrImage(cvNew)),
+		})
+	}
+	return conditions
 }
 
 func findOperatorVersionChange(old, new []configv1.OperandVersion) []string {
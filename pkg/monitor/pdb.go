@@ -0,0 +1,74 @@
+package monitor
+
+import (
+	"fmt"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// startPodDisruptionBudgetMonitoring records the status of every pod disruption budget in openshift-* namespaces
+// whenever the number of healthy pods or the number of allowed disruptions changes.  The budgets themselves are
+// recorded when they are first seen and when their selector changes, so that violations can be matched to the nodes
+// of the pods they protect.
+func startPodDisruptionBudgetMonitoring(m Recorder, namespaceInformers *openshiftNamespaceInformers) {
+	namespaceInformers.AddHandler(func(namespace string, factory informers.SharedInformerFactory) {
+		namespaceInformers.PodDisruptionBudgetInformer(namespace, factory).AddEventHandler(
+			cache.ResourceEventHandlerFuncs{
+				AddFunc: func(obj interface{}) {
+					pdb, ok := obj.(*policyv1.PodDisruptionBudget)
+					if !ok {
+						return
+					}
+					m.RecordResource("poddisruptionbudgets", pdb)
+					m.Record(podDisruptionBudgetStatusCondition(pdb))
+				},
+				UpdateFunc: func(old, obj interface{}) {
+					pdb, ok := obj.(*policyv1.PodDisruptionBudget)
+					if !ok {
+						return
+					}
+					oldPDB, ok := old.(*policyv1.PodDisruptionBudget)
+					if !ok {
+						return
+					}
+					if !equality.Semantic.DeepEqual(pdb.Spec.Selector, oldPDB.Spec.Selector) {
+						m.RecordResource("poddisruptionbudgets", pdb)
+					}
+					if pdb.Status.CurrentHealthy == oldPDB.Status.CurrentHealthy &&
+						pdb.Status.DesiredHealthy == oldPDB.Status.DesiredHealthy &&
+						pdb.Status.ExpectedPods == oldPDB.Status.ExpectedPods &&
+						pdb.Status.DisruptionsAllowed == oldPDB.Status.DisruptionsAllowed {
+						return
+					}
+					m.Record(podDisruptionBudgetStatusCondition(pdb))
+				},
+			},
+		)
+	})
+}
+
+func podDisruptionBudgetStatusCondition(pdb *policyv1.PodDisruptionBudget) monitorapi.Condition {
+	level := monitorapi.Info
+	if pdb.Status.CurrentHealthy < pdb.Status.DesiredHealthy {
+		level = monitorapi.Warning
+	}
+	budget := ""
+	if pdb.Spec.MinAvailable != nil {
+		budget += fmt.Sprintf(" minAvailable/%s", pdb.Spec.MinAvailable.String())
+	}
+	if pdb.Spec.MaxUnavailable != nil {
+		budget += fmt.Sprintf(" maxUnavailable/%s", pdb.Spec.MaxUnavailable.String())
+	}
+	return monitorapi.Condition{
+		Level:   level,
+		Locator: monitorapi.PodDisruptionBudgetLocator(pdb.Namespace, pdb.Name),
+		Message: fmt.Sprintf("reason/%s currentHealthy/%d desiredHealthy/%d expectedPods/%d disruptionsAllowed/%d%s",
+			monitorapi.PodDisruptionBudgetReasonStatus, pdb.Status.CurrentHealthy, pdb.Status.DesiredHealthy,
+			pdb.Status.ExpectedPods, pdb.Status.DisruptionsAllowed, budget),
+	}
+}
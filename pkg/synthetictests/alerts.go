@@ -2,6 +2,8 @@ package synthetictests
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
@@ -10,16 +12,93 @@ import (
 
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 	"github.com/openshift/origin/pkg/synthetictests/allowedalerts"
+	"github.com/openshift/origin/pkg/synthetictests/platformidentification"
 )
 
+const discoverAlertTestsTestName = "[sig-instrumentation][invariant] alerting rules should be discoverable to test every alert"
+
+var (
+	runJobTypeFileLock sync.Mutex
+	runJobTypeFile     string
+)
+
+// SetRunJobTypeFile sets the file the job type of this run was written to with the run data, so that the invariants
+// use it instead of asking the cluster again.
+func SetRunJobTypeFile(filename string) {
+	runJobTypeFileLock.Lock()
+	defer runJobTypeFileLock.Unlock()
+	runJobTypeFile = filename
+}
+
+// jobTypeForRun reads the job type written with the run data of this run.  The cluster is only asked when no job type
+// was written, like when the cluster could not be identified at the end of the run.
+func jobTypeForRun(ctx context.Context, restConfig *rest.Config) (*platformidentification.JobType, error) {
+	runJobTypeFileLock.Lock()
+	filename := runJobTypeFile
+	runJobTypeFileLock.Unlock()
+
+	var readErr error
+	if len(filename) > 0 {
+		jobType, err := platformidentification.ReadJobType(filename)
+		if err == nil {
+			return jobType, nil
+		}
+		readErr = err
+	}
+	if restConfig == nil {
+		if readErr != nil {
+			return nil, readErr
+		}
+		return nil, fmt.Errorf("no job type was written with the run data and there is no cluster to ask")
+	}
+	return platformidentification.GetJobType(ctx, restConfig)
+}
+
 func testAlerts(events monitorapi.Intervals, restConfig *rest.Config, duration time.Duration, recordedResource *monitorapi.ResourcesMap) []*junitapi.JUnitTestCase {
 	ret := []*junitapi.JUnitTestCase{}
 
 	alertTests := allowedalerts.AllAlertTests(context.TODO(), restConfig, duration)
+	if allowedalerts.DiscoverAlertTests() && restConfig != nil {
+		discoveredAlertTests, err := allowedalerts.DiscoveredAlertTests(context.TODO(), restConfig, alertTests)
+		if err != nil {
+			ret = append(ret, &junitapi.JUnitTestCase{
+				Name: discoverAlertTestsTestName,
+				FailureOutput: &junitapi.FailureOutput{
+					Output: err.Error(),
+				},
+				SystemOut: err.Error(),
+			})
+		} else {
+			ret = append(ret, &junitapi.JUnitTestCase{Name: discoverAlertTestsTestName})
+			alertTests = append(alertTests, discoveredAlertTests...)
+		}
+	}
+
+	jobType, err := jobTypeForRun(context.TODO(), restConfig)
+	if err != nil {
+		message := fmt.Sprintf("unable to determine the job type: %v", err)
+		for _, alertTest := range alertTests {
+			ret = append(ret, &junitapi.JUnitTestCase{
+				Name: alertTest.InvariantTestName(),
+				FailureOutput: &junitapi.FailureOutput{
+					Output: message,
+				},
+				SystemOut: message,
+			})
+		}
+		return ret
+	}
+
+	return append(ret, RunAlertInvariants(alertTests, *jobType, events, *recordedResource)...)
+}
+
+// RunAlertInvariants checks the alerts in the intervals of a run, which may have been read after the cluster was gone.
+func RunAlertInvariants(alertTests []allowedalerts.AlertTest, jobType platformidentification.JobType, events monitorapi.Intervals, recordedResource monitorapi.ResourcesMap) []*junitapi.JUnitTestCase {
+	ret := []*junitapi.JUnitTestCase{}
 	for i := range alertTests {
 		alertTest := alertTests[i]
 
-		junit, err := alertTest.InvariantCheck(context.TODO(), restConfig, events, *recordedResource)
+		junit, err := alertTest.InvariantCheck(context.TODO(), jobType, events, recordedResource)
 		if err != nil {
 			ret = append(ret, &junitapi.JUnitTestCase{
 				Name: alertTest.InvariantTestName(),
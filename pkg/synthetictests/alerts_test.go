@@ -0,0 +1,36 @@
+package synthetictests
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/openshift/origin/pkg/synthetictests/platformidentification"
+)
+
+func Test_jobTypeForRun(t *testing.T) {
+	defer SetRunJobTypeFile("")
+
+	artifactDir := t.TempDir()
+	SetRunJobTypeFile(platformidentification.JobTypeFilename(artifactDir, "_20220705-170000"))
+	if _, err := jobTypeForRun(context.TODO(), nil); err == nil {
+		t.Errorf("expected an error without a job type or a cluster")
+	}
+
+	expected := &platformidentification.JobType{Release: "4.11", Platform: "aws", Architecture: "amd64", Network: "sdn", Topology: "ha"}
+	if err := platformidentification.WriteJobTypeForJobRun(artifactDir, expected, "_20220705-170000"); err != nil {
+		t.Fatal(err)
+	}
+	// the job type of another run in the same directory is never used
+	other := &platformidentification.JobType{Release: "4.12", Platform: "gcp", Architecture: "amd64", Network: "ovn", Topology: "ha"}
+	if err := platformidentification.WriteJobTypeForJobRun(artifactDir, other, "_20220706-170000"); err != nil {
+		t.Fatal(err)
+	}
+	actual, err := jobTypeForRun(context.TODO(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected the job type written with the run data %v, got %v", expected, actual)
+	}
+}
@@ -27,13 +27,19 @@ func AllAlertTests(ctx context.Context, clientConfig *rest.Config, duration time
 		}
 	}
 
+	// these alerts are expected while control plane nodes reboot and static pods roll out, which happens in every
+	// upgrade.  Only the time outside of that counts against the allowance.
+	etcdMaintenanceAllowance := allowedDuringMaintenance(defaultAllowances, MasterNodeRebootWindow, StaticPodRolloutWindow("etcd"))
+	kubeAPIServerMaintenanceAllowance := allowedDuringMaintenance(defaultAllowances, MasterNodeRebootWindow, StaticPodRolloutWindow("kube-apiserver"))
+	drainMaintenanceAllowance := allowedDuringMaintenance(defaultAllowances, MachineConfigDrainWindow)
+
 	ret := []AlertTest{}
 	ret = append(ret, newWatchdogAlert())
 	ret = append(ret, newNamespacedAlert("KubePodNotReady").pending().neverFail().toTests()...)
 	ret = append(ret, newNamespacedAlert("KubePodNotReady").firing().toTests()...)
 
 	ret = append(ret, newAlert("etcd", "etcdMembersDown").pending().neverFail().toTests()...)
-	ret = append(ret, newAlert("etcd", "etcdMembersDown").firing().toTests()...)
+	ret = append(ret, newAlert("etcd", "etcdMembersDown").withAllowance(etcdMaintenanceAllowance).firing().toTests()...)
 	ret = append(ret, newAlert("etcd", "etcdGRPCRequestsSlow").pending().neverFail().toTests()...)
 	ret = append(ret, newAlert("etcd", "etcdGRPCRequestsSlow").firing().toTests()...)
 	ret = append(ret, newAlert("etcd", "etcdHighNumberOfFailedGRPCRequests").pending().neverFail().toTests()...)
@@ -52,7 +58,7 @@ func AllAlertTests(ctx context.Context, clientConfig *rest.Config, duration time
 	ret = append(ret, newAlert("etcd", "etcdHighNumberOfLeaderChanges").withAllowance(etcdAllowance).firing().toTests()...)
 
 	ret = append(ret, newAlert("kube-apiserver", "KubeAPIErrorBudgetBurn").pending().neverFail().toTests()...)
-	ret = append(ret, newAlert("kube-apiserver", "KubeAPIErrorBudgetBurn").firing().toTests()...)
+	ret = append(ret, newAlert("kube-apiserver", "KubeAPIErrorBudgetBurn").withAllowance(kubeAPIServerMaintenanceAllowance).firing().toTests()...)
 	ret = append(ret, newAlert("kube-apiserver", "KubeClientErrors").pending().neverFail().toTests()...)
 	ret = append(ret, newAlert("kube-apiserver", "KubeClientErrors").firing().toTests()...)
 
@@ -60,7 +66,7 @@ func AllAlertTests(ctx context.Context, clientConfig *rest.Config, duration time
 	ret = append(ret, newAlert("storage", "KubePersistentVolumeErrors").firing().toTests()...)
 
 	ret = append(ret, newAlert("machine config operator", "MCDDrainError").pending().neverFail().toTests()...)
-	ret = append(ret, newAlert("machine config operator", "MCDDrainError").firing().toTests()...)
+	ret = append(ret, newAlert("machine config operator", "MCDDrainError").withAllowance(drainMaintenanceAllowance).firing().toTests()...)
 
 	ret = append(ret, newAlert("monitoring", "PrometheusOperatorWatchErrors").pending().neverFail().toTests()...)
 	ret = append(ret, newAlert("monitoring", "PrometheusOperatorWatchErrors").firing().toTests()...)
@@ -0,0 +1,175 @@
+package allowedalerts
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// MaintenanceWindow is a kind of interval during which some alerts are expected, like a node rebooting.
+type MaintenanceWindow struct {
+	// Name describes the intervals in the junit output, like "master node reboot".
+	Name    string
+	Matches monitorapi.EventIntervalMatchesFunc
+}
+
+// MasterNodeRebootWindow is a master node rebooting, either to update the operating system or to apply a machine
+// config.  Worker reboots do not take down etcd members or apiservers, so they are not expected maintenance for their
+// alerts.
+var MasterNodeRebootWindow = MaintenanceWindow{
+	Name: "master node reboot",
+	Matches: func(eventInterval monitorapi.EventInterval) bool {
+		if !hasNodeRole(eventInterval, "master") {
+			return false
+		}
+		return isNodePhase(eventInterval, "NodeUpdate", "Reboot") ||
+			isNodePhase(eventInterval, monitorapi.NodeReasonMachineConfigNodeUpdate, "Rebooting")
+	},
+}
+
+// MachineConfigDrainWindow is a node being drained before a machine config or an update is applied.
+var MachineConfigDrainWindow = MaintenanceWindow{
+	Name: "MCO drain",
+	Matches: func(eventInterval monitorapi.EventInterval) bool {
+		return isNodePhase(eventInterval, "NodeUpdate", "Drain") ||
+			isNodePhase(eventInterval, monitorapi.NodeReasonMachineConfigNodeUpdate, "Draining")
+	},
+}
+
+// StaticPodRolloutWindow is one of the operators rolling out a new revision of its static pods, which restarts them
+// one node at a time.
+func StaticPodRolloutWindow(operators ...string) MaintenanceWindow {
+	operatorSet := sets.NewString(operators...)
+	return MaintenanceWindow{
+		Name: "static pod revision rollout",
+		Matches: func(eventInterval monitorapi.EventInterval) bool {
+			operator, ok := monitorapi.OperatorFromLocator(eventInterval.Locator)
+			if !ok || !operatorSet.Has(operator) {
+				return false
+			}
+			// the calculated progressing intervals hold the message of the condition, like
+			// NodeInstallerProgressing: 1 nodes are at revision 7; 2 nodes are at revision 8
+			return strings.HasPrefix(eventInterval.Message, "condition/Progressing status/True") &&
+				strings.Contains(eventInterval.Message, "revision")
+		},
+	}
+}
+
+func isNodePhase(eventInterval monitorapi.EventInterval, reason, phase string) bool {
+	if _, ok := monitorapi.NodeFromLocator(eventInterval.Locator); !ok {
+		return false
+	}
+	if monitorapi.ReasonFrom(eventInterval.Message) != reason {
+		return false
+	}
+	return monitorapi.AnnotationsFromMessage(eventInterval.Message)["phase"] == phase
+}
+
+// hasNodeRole returns true when the roles in the message, like roles/master,worker, include the role.  Nodes with
+// unknown roles have none.
+func hasNodeRole(eventInterval monitorapi.EventInterval, role string) bool {
+	roles := monitorapi.GetNodeRoles(eventInterval)
+	if len(roles) == 0 {
+		return false
+	}
+	return sets.NewString(strings.Split(roles, ",")...).Has(role)
+}
+
+// maintenanceAwareAllowance is implemented by allowance calculators that do not count all of the time an alert was at or
+// above its level against the allowance.
+type maintenanceAwareAllowance interface {
+	// durationOutsideMaintenance returns how long the alert counts as at or above its level, and how that was
+	// calculated for the junit output.  intervals are all the intervals of the run.
+	durationOutsideMaintenance(alertIntervals, intervals monitorapi.Intervals) (time.Duration, string)
+}
+
+type maintenanceWindowAllowance struct {
+	AlertTestAllowanceCalculator
+	windows []MaintenanceWindow
+}
+
+var _ maintenanceAwareAllowance = &maintenanceWindowAllowance{}
+
+// allowedDuringMaintenance subtracts the time the alert overlapped any of the maintenance windows from how long it was
+// at or above its level, before comparing it with the allowance of the delegate.
+func allowedDuringMaintenance(delegate AlertTestAllowanceCalculator, windows ...MaintenanceWindow) AlertTestAllowanceCalculator {
+	return &maintenanceWindowAllowance{
+		AlertTestAllowanceCalculator: delegate,
+		windows:                      windows,
+	}
+}
+
+func (d *maintenanceWindowAllowance) durationOutsideMaintenance(alertIntervals, intervals monitorapi.Intervals) (time.Duration, string) {
+	duration := alertIntervals.Duration(1 * time.Second)
+
+	allWindows := monitorapi.Intervals{}
+	details := []string{}
+	for _, window := range d.windows {
+		windowIntervals := intervals.Filter(window.Matches)
+		allWindows = append(allWindows, windowIntervals...)
+		if overlap := overlapDuration(alertIntervals, windowIntervals); overlap > 0 {
+			details = append(details, fmt.Sprintf("%s %s", window.Name, overlap))
+		}
+	}
+	excluded := overlapDuration(alertIntervals, allWindows)
+	if excluded <= 0 {
+		return duration, ""
+	}
+	if excluded > duration {
+		excluded = duration
+	}
+	return duration - excluded, fmt.Sprintf(" (not counting %s overlapping expected maintenance: %s)", excluded, strings.Join(details, ", "))
+}
+
+// overlapDuration sums how long each of the alert intervals overlapped any of the windows.  Overlapping windows are
+// only counted once.
+func overlapDuration(alertIntervals, windows monitorapi.Intervals) time.Duration {
+	var ret time.Duration
+	for _, alertInterval := range alertIntervals {
+		covered := []monitorapi.EventInterval{}
+		for _, window := range windows {
+			from, to := window.From, window.To
+			if from.Before(alertInterval.From) {
+				from = alertInterval.From
+			}
+			if to.After(alertInterval.To) {
+				to = alertInterval.To
+			}
+			if !from.Before(to) {
+				continue
+			}
+			covered = append(covered, monitorapi.EventInterval{From: from, To: to})
+		}
+		ret += unionDuration(covered)
+	}
+	return ret
+}
+
+func unionDuration(intervals monitorapi.Intervals) time.Duration {
+	if len(intervals) == 0 {
+		return 0
+	}
+	sorted := make(monitorapi.Intervals, len(intervals))
+	copy(sorted, intervals)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].From.Before(sorted[j].From)
+	})
+
+	var ret time.Duration
+	from, to := sorted[0].From, sorted[0].To
+	for _, curr := range sorted[1:] {
+		if curr.From.After(to) {
+			ret += to.Sub(from)
+			from, to = curr.From, curr.To
+			continue
+		}
+		if curr.To.After(to) {
+			to = curr.To
+		}
+	}
+	return ret + to.Sub(from)
+}
@@ -0,0 +1,52 @@
+package allowedalerts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestMaintenanceWindowAllowance_durationOutsideMaintenance(t *testing.T) {
+	start := time.Date(2022, 7, 5, 17, 0, 0, 0, time.UTC)
+	interval := func(locator, message string, from, to time.Duration) monitorapi.EventInterval {
+		return monitorapi.EventInterval{
+			Condition: monitorapi.Condition{Level: monitorapi.Info, Locator: locator, Message: message},
+			From:      start.Add(from),
+			To:        start.Add(to),
+		}
+	}
+	// the alert fired for ten minutes in two intervals
+	alertIntervals := monitorapi.Intervals{
+		interval("alert/etcdMembersDown ns/openshift-etcd", `alertstate="firing"`, 0, 6*time.Minute),
+		interval("alert/etcdMembersDown ns/openshift-etcd", `alertstate="firing"`, 20*time.Minute, 24*time.Minute),
+	}
+	intervals := monitorapi.Intervals{
+		// overlaps the first interval by two minutes
+		interval("node/master-0", "reason/NodeUpdate phase/Reboot roles/master rebooted and kubelet started", 4*time.Minute, 8*time.Minute),
+		// overlaps the first interval by three minutes, one of which the reboot already covers
+		interval("node/master-0", "reason/MachineConfigNodeUpdate phase/Rebooting roles/master", 3*time.Minute, 5*time.Minute),
+		// overlaps the second interval by one minute
+		interval("clusteroperator/etcd", "condition/Progressing status/True reason/NodeInstallerProgressing: 1 nodes are at revision 7; 2 nodes are at revision 8", 23*time.Minute, 30*time.Minute),
+		// not a maintenance window of this alert
+		interval("clusteroperator/kube-apiserver", "condition/Progressing status/True reason/NodeInstallerProgressing: 1 nodes are at revision 7; 2 nodes are at revision 8", 0, 30*time.Minute),
+		interval("node/master-1", "reason/NodeUpdate phase/Drain roles/master drained node", 0, 30*time.Minute),
+		interval("node/worker-0", "reason/NodeUpdate phase/Reboot roles/worker rebooted and kubelet started", 0, 30*time.Minute),
+		interval("node/worker-1", "reason/MachineConfigNodeUpdate phase/Rebooting roles/worker", 0, 30*time.Minute),
+		interval("node/worker-2", "reason/MachineConfigNodeUpdate phase/Rebooting roles/", 0, 30*time.Minute),
+	}
+
+	allowance := allowedDuringMaintenance(defaultAllowances, MasterNodeRebootWindow, StaticPodRolloutWindow("etcd")).(maintenanceAwareAllowance)
+	duration, details := allowance.durationOutsideMaintenance(alertIntervals, intervals)
+	if want := 6 * time.Minute; duration != want {
+		t.Errorf("expected %v, got %v", want, duration)
+	}
+	if want := " (not counting 4m0s overlapping expected maintenance: master node reboot 3m0s, static pod revision rollout 1m0s)"; details != want {
+		t.Errorf("expected %q, got %q", want, details)
+	}
+
+	duration, details = allowance.durationOutsideMaintenance(alertIntervals, nil)
+	if want := 10 * time.Minute; duration != want || len(details) > 0 {
+		t.Errorf("expected %v without details, got %v %q", want, duration, details)
+	}
+}
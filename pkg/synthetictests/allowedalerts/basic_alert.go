@@ -34,7 +34,9 @@ type AlertTest interface {
 	AlertState() AlertState
 
 	TestAlert(ctx context.Context, prometheusClient prometheusv1.API, restConfig *rest.Config) error
-	InvariantCheck(ctx context.Context, restConfig *rest.Config, intervals monitorapi.Intervals, r monitorapi.ResourcesMap) ([]*junitapi.JUnitTestCase, error)
+	// InvariantCheck only looks at the intervals, resources and job type of the run, so that it can be evaluated after
+	// the cluster is gone.
+	InvariantCheck(ctx context.Context, jobType platformidentification.JobType, intervals monitorapi.Intervals, r monitorapi.ResourcesMap) ([]*junitapi.JUnitTestCase, error)
 }
 
 // AlertState is the state of the alert. They are logically ordered, so if a test says it limits on "pending", then
@@ -193,7 +195,13 @@ func (a *basicAlertTest) TestAlert(ctx context.Context, prometheusClient prometh
 		return err
 	}
 
-	state, message := a.failOrFlake(ctx, restConfig, firingIntervals, pendingIntervals)
+	jobType, err := platformidentification.GetJobType(ctx, restConfig)
+	if err != nil {
+		return err
+	}
+
+	// the intervals of the run are not available here, so no maintenance is excluded
+	state, message := a.failOrFlake(*jobType, firingIntervals, pendingIntervals, nil)
 	switch state {
 	case pass:
 		return nil
@@ -219,7 +227,9 @@ const (
 	fail
 )
 
-func (a *basicAlertTest) failOrFlake(ctx context.Context, restConfig *rest.Config, firingIntervals, pendingIntervals monitorapi.Intervals) (testState, string) {
+// failOrFlake compares how long the alert was at or above its level with the allowance.  intervals are all the intervals
+// of the run, which allowances use to exclude expected maintenance.
+func (a *basicAlertTest) failOrFlake(jobType platformidentification.JobType, firingIntervals, pendingIntervals, intervals monitorapi.Intervals) (testState, string) {
 	var alertIntervals monitorapi.Intervals
 
 	switch a.AlertState() {
@@ -244,31 +254,35 @@ func (a *basicAlertTest) failOrFlake(ctx context.Context, restConfig *rest.Confi
 
 	describe := alertIntervals.Strings()
 	durationAtOrAboveLevel := alertIntervals.Duration(1 * time.Second)
+	maintenanceDetails := ""
+	if allowance, ok := a.allowanceCalculator.(maintenanceAwareAllowance); ok {
+		durationAtOrAboveLevel, maintenanceDetails = allowance.durationOutsideMaintenance(alertIntervals, intervals)
+	}
 	firingDuration := firingIntervals.Duration(1 * time.Second)
 	pendingDuration := pendingIntervals.Duration(1 * time.Second)
 
-	jobType, err := platformidentification.GetJobType(ctx, restConfig)
-	if err != nil {
-		return fail, err.Error()
-	}
-
 	// TODO for namespaced alerts, we need to query the data on a per-namespace basis.
 	//  For the ones we're starting with, they tend to fail one at a time, so this will hopefully not be an awful starting point until we get there.
 
-	failAfter, err := a.allowanceCalculator.FailAfter(a.alertName, *jobType)
+	failAfter, err := a.allowanceCalculator.FailAfter(a.alertName, jobType)
 	if err != nil {
 		return fail, fmt.Sprintf("unable to calculate allowance for %s which was at %s, err %v\n\n%s", a.AlertName(), a.AlertState(), err, strings.Join(describe, "\n"))
 	}
-	flakeAfter := a.allowanceCalculator.FlakeAfter(a.alertName, *jobType)
+	flakeAfter := a.allowanceCalculator.FlakeAfter(a.alertName, jobType)
+	// the details say where the historical data came from, in case it was not embedded
+	_, historicalDataDetails, _ := getClosestPercentilesValues(a.alertName, jobType)
+	if len(historicalDataDetails) > 0 {
+		historicalDataDetails = " " + historicalDataDetails
+	}
 
 	switch {
 	case durationAtOrAboveLevel > failAfter:
-		return fail, fmt.Sprintf("%s was at or above %s for at least %s on %#v (maxAllowed=%s): pending for %s, firing for %s:\n\n%s",
-			a.AlertName(), a.AlertState(), durationAtOrAboveLevel, *jobType, failAfter, pendingDuration, firingDuration, strings.Join(describe, "\n"))
+		return fail, fmt.Sprintf("%s was at or above %s for at least %s%s on %#v (maxAllowed=%s): pending for %s, firing for %s%s:\n\n%s",
+			a.AlertName(), a.AlertState(), durationAtOrAboveLevel, maintenanceDetails, jobType, failAfter, pendingDuration, firingDuration, historicalDataDetails, strings.Join(describe, "\n"))
 
 	case durationAtOrAboveLevel > flakeAfter:
-		return flake, fmt.Sprintf("%s was at or above %s for at least %s on %#v (maxAllowed=%s): pending for %s, firing for %s:\n\n%s",
-			a.AlertName(), a.AlertState(), durationAtOrAboveLevel, *jobType, flakeAfter, pendingDuration, firingDuration, strings.Join(describe, "\n"))
+		return flake, fmt.Sprintf("%s was at or above %s for at least %s%s on %#v (maxAllowed=%s): pending for %s, firing for %s%s:\n\n%s",
+			a.AlertName(), a.AlertState(), durationAtOrAboveLevel, maintenanceDetails, jobType, flakeAfter, pendingDuration, firingDuration, historicalDataDetails, strings.Join(describe, "\n"))
 	}
 
 	return pass, ""
@@ -363,7 +377,7 @@ func redhatOperatorPodsNotPending(trackedPodResources monitorapi.InstanceMap, fi
 	return true
 }
 
-func (a *basicAlertTest) InvariantCheck(ctx context.Context, restConfig *rest.Config, alertIntervals monitorapi.Intervals, resourcesMap monitorapi.ResourcesMap) ([]*junitapi.JUnitTestCase, error) {
+func (a *basicAlertTest) InvariantCheck(ctx context.Context, jobType platformidentification.JobType, alertIntervals monitorapi.Intervals, resourcesMap monitorapi.ResourcesMap) ([]*junitapi.JUnitTestCase, error) {
 	pendingIntervals := alertIntervals.Filter(
 		monitorapi.And(
 			func(eventInterval monitorapi.EventInterval) bool {
@@ -397,7 +411,7 @@ func (a *basicAlertTest) InvariantCheck(ctx context.Context, restConfig *rest.Co
 		),
 	)
 
-	state, message := a.failOrFlake(ctx, restConfig, firingIntervals, pendingIntervals)
+	state, message := a.failOrFlake(jobType, firingIntervals, pendingIntervals, alertIntervals)
 
 	switch a.alertName {
 	case "KubePodNotReady":
@@ -0,0 +1,173 @@
+package allowedalerts
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/openshift/origin/pkg/synthetictests/platformidentification"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// DiscoverAlertTestsEnvVar enables DiscoverAlertTests when --discover-alert-tests is not set.
+	DiscoverAlertTestsEnvVar = "TEST_DISCOVER_ALERT_TESTS"
+)
+
+var (
+	discoverAlertTestsLock sync.Mutex
+	discoverAlertTests     *bool
+
+	prometheusRuleResource = schema.GroupVersionResource{Group: "monitoring.coreos.com", Version: "v1", Resource: "prometheusrules"}
+)
+
+// SetDiscoverAlertTests overrides DiscoverAlertTestsEnvVar.
+func SetDiscoverAlertTests(discover bool) {
+	discoverAlertTestsLock.Lock()
+	defer discoverAlertTestsLock.Unlock()
+	discoverAlertTests = &discover
+}
+
+// DiscoverAlertTests returns true when every alerting rule in the cluster should get tests, not only the alerts in
+// AllAlertTests.
+func DiscoverAlertTests() bool {
+	discoverAlertTestsLock.Lock()
+	defer discoverAlertTestsLock.Unlock()
+	if discoverAlertTests != nil {
+		return *discoverAlertTests
+	}
+	discover, _ := strconv.ParseBool(os.Getenv(DiscoverAlertTestsEnvVar))
+	return discover
+}
+
+// AlertingRule is an alert defined by a PrometheusRule in the cluster.
+type AlertingRule struct {
+	AlertName string
+	Severity  string
+	// Namespace is the namespace of the PrometheusRule, which tells who owns the alert.  It is not necessarily the
+	// namespace of the alert when it fires.
+	Namespace string
+}
+
+// DiscoverAlertingRules lists the alerts of every PrometheusRule in the cluster.  An alert defined more than once is
+// returned once, with its highest severity.
+func DiscoverAlertingRules(ctx context.Context, dynamicClient dynamic.Interface) ([]AlertingRule, error) {
+	prometheusRules, err := dynamicClient.Resource(prometheusRuleResource).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(prometheusRules.Items, func(i, j int) bool {
+		return prometheusRules.Items[i].GetNamespace()+"/"+prometheusRules.Items[i].GetName() <
+			prometheusRules.Items[j].GetNamespace()+"/"+prometheusRules.Items[j].GetName()
+	})
+
+	alertNameToRule := map[string]AlertingRule{}
+	for _, prometheusRule := range prometheusRules.Items {
+		groups, _, err := unstructured.NestedSlice(prometheusRule.Object, "spec", "groups")
+		if err != nil {
+			return nil, fmt.Errorf("unable to read prometheusrule %s/%s: %w", prometheusRule.GetNamespace(), prometheusRule.GetName(), err)
+		}
+		for _, group := range groups {
+			groupMap, ok := group.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			rules, _, _ := unstructured.NestedSlice(groupMap, "rules")
+			for _, rule := range rules {
+				ruleMap, ok := rule.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				// recording rules have no alert
+				alertName, _, _ := unstructured.NestedString(ruleMap, "alert")
+				if len(alertName) == 0 {
+					continue
+				}
+				severity, _, _ := unstructured.NestedString(ruleMap, "labels", "severity")
+				curr := AlertingRule{
+					AlertName: alertName,
+					Severity:  severity,
+					Namespace: prometheusRule.GetNamespace(),
+				}
+				if existing, ok := alertNameToRule[alertName]; ok && severityRank(existing.Severity) >= severityRank(curr.Severity) {
+					continue
+				}
+				alertNameToRule[alertName] = curr
+			}
+		}
+	}
+
+	ret := []AlertingRule{}
+	for _, rule := range alertNameToRule {
+		ret = append(ret, rule)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].AlertName < ret[j].AlertName
+	})
+	return ret, nil
+}
+
+func severityRank(severity string) int {
+	switch severity {
+	case "critical":
+		return 3
+	case "warning":
+		return 2
+	case "info":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// DiscoveredAlertTests returns tests for the alerting rules in the cluster that the existing tests do not cover.
+func DiscoveredAlertTests(ctx context.Context, clientConfig *rest.Config, existing []AlertTest) ([]AlertTest, error) {
+	dynamicClient, err := dynamic.NewForConfig(clientConfig)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := DiscoverAlertingRules(ctx, dynamicClient)
+	if err != nil {
+		return nil, fmt.Errorf("unable to discover alerting rules: %w", err)
+	}
+	return alertTestsForRules(rules, existing), nil
+}
+
+// alertTestsForRules picks the defaults for each alert from its severity and the namespace that owns it.  Critical
+// alerts fail when they fire for longer than the historical data allows, warnings only flake, and anything less severe
+// is not tested.  Being pending never fails.  Without historical data, the default allowance is used.
+func alertTestsForRules(rules []AlertingRule, existing []AlertTest) []AlertTest {
+	existingAlertNames := map[string]bool{}
+	for _, alertTest := range existing {
+		existingAlertNames[alertTest.AlertName()] = true
+	}
+	namespaceToBugzillaComponent := platformidentification.GetNamespacesToBugzillaComponents()
+
+	ret := []AlertTest{}
+	for _, rule := range rules {
+		if existingAlertNames[rule.AlertName] {
+			continue
+		}
+		bugzillaComponent, ok := namespaceToBugzillaComponent[rule.Namespace]
+		if !ok {
+			bugzillaComponent = "Unknown"
+		}
+
+		switch rule.Severity {
+		case "critical":
+			ret = append(ret, newAlert(bugzillaComponent, rule.AlertName).pending().neverFail().toTests()...)
+			ret = append(ret, newAlert(bugzillaComponent, rule.AlertName).firing().toTests()...)
+		case "warning":
+			ret = append(ret, newAlert(bugzillaComponent, rule.AlertName).pending().neverFail().toTests()...)
+			ret = append(ret, newAlert(bugzillaComponent, rule.AlertName).firing().neverFail().toTests()...)
+		}
+	}
+	return ret
+}
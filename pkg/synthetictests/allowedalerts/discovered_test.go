@@ -0,0 +1,103 @@
+package allowedalerts
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func prometheusRule(namespace, name string, rules ...map[string]interface{}) *unstructured.Unstructured {
+	ruleList := []interface{}{}
+	for _, rule := range rules {
+		ruleList = append(ruleList, rule)
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "monitoring.coreos.com/v1",
+		"kind":       "PrometheusRule",
+		"metadata": map[string]interface{}{
+			"namespace": namespace,
+			"name":      name,
+		},
+		"spec": map[string]interface{}{
+			"groups": []interface{}{
+				map[string]interface{}{
+					"name":  name,
+					"rules": ruleList,
+				},
+			},
+		},
+	}}
+}
+
+func alertingRule(alertName, severity string) map[string]interface{} {
+	return map[string]interface{}{
+		"alert":  alertName,
+		"expr":   "vector(1)",
+		"labels": map[string]interface{}{"severity": severity},
+	}
+}
+
+func TestDiscoverAlertingRules(t *testing.T) {
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{prometheusRuleResource: "PrometheusRuleList"},
+		prometheusRule("openshift-etcd", "etcd",
+			alertingRule("etcdNoLeader", "critical"),
+			alertingRule("etcdDatabaseQuotaLowSpace", "critical"),
+			map[string]interface{}{"record": "etcd:requests:rate", "expr": "vector(1)"},
+		),
+		prometheusRule("openshift-monitoring", "cluster-monitoring",
+			alertingRule("TargetDown", "warning"),
+			alertingRule("AlertmanagerReceiversNotConfigured", "info"),
+			alertingRule("etcdDatabaseQuotaLowSpace", "warning"),
+		),
+		prometheusRule("my-operator", "my-operator",
+			alertingRule("MyOperatorDown", "critical"),
+		),
+	)
+
+	rules, err := DiscoverAlertingRules(context.TODO(), dynamicClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantRules := []AlertingRule{
+		{AlertName: "AlertmanagerReceiversNotConfigured", Severity: "info", Namespace: "openshift-monitoring"},
+		{AlertName: "MyOperatorDown", Severity: "critical", Namespace: "my-operator"},
+		{AlertName: "TargetDown", Severity: "warning", Namespace: "openshift-monitoring"},
+		{AlertName: "etcdDatabaseQuotaLowSpace", Severity: "critical", Namespace: "openshift-etcd"},
+		{AlertName: "etcdNoLeader", Severity: "critical", Namespace: "openshift-etcd"},
+	}
+	if !reflect.DeepEqual(rules, wantRules) {
+		t.Fatalf("expected %#v, got %#v", wantRules, rules)
+	}
+
+	alertTests := alertTestsForRules(rules, AllAlertTests(context.TODO(), nil, 0))
+	got := []string{}
+	for _, alertTest := range alertTests {
+		got = append(got, alertTest.InvariantTestName())
+	}
+	want := []string{
+		"[bz-Unknown][invariant] alert/MyOperatorDown should not be at or above pending",
+		"[bz-Unknown][invariant] alert/MyOperatorDown should not be at or above info",
+		"[bz-Monitoring][invariant] alert/TargetDown should not be at or above pending",
+		"[bz-Monitoring][invariant] alert/TargetDown should not be at or above info",
+		"[bz-Etcd][invariant] alert/etcdDatabaseQuotaLowSpace should not be at or above pending",
+		"[bz-Etcd][invariant] alert/etcdDatabaseQuotaLowSpace should not be at or above info",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	// critical alerts fail when they fire, warnings only flake
+	for _, alertTest := range alertTests {
+		_, neverFails := alertTest.(*basicAlertTest).allowanceCalculator.(*neverFailAllowance)
+		wantNeverFails := alertTest.AlertState() == AlertPending || alertTest.AlertName() == "TargetDown"
+		if neverFails != wantNeverFails {
+			t.Errorf("expected %v to never fail=%v", alertTest.InvariantTestName(), wantNeverFails)
+		}
+	}
+}
@@ -36,23 +36,45 @@ type AlertTestAllowanceCalculator interface {
 	FlakeAfter(alertName string, jobType platformidentification.JobType) time.Duration
 }
 
+// minimumJobRunsForFallback is how many job runs a fuzzy match has to be based on to be used.
+const minimumJobRunsForFallback = 10
+
+// percentileAllowances flake and fail when an alert was at or above its level for longer than the given percentiles of
+// the historical data.
 type percentileAllowances struct {
+	flakePercentile int
+	failPercentile  int
 }
 
-var defaultAllowances = &percentileAllowances{}
+var defaultAllowances = newPercentileAllowances(95, 99)
+
+// newPercentileAllowances uses P95 and P99 instead of percentiles that are missing from the historical data, so that the
+// data embedded with only those percentiles keeps working.
+func newPercentileAllowances(flakePercentile, failPercentile int) *percentileAllowances {
+	return &percentileAllowances{
+		flakePercentile: flakePercentile,
+		failPercentile:  failPercentile,
+	}
+}
 
 func (d *percentileAllowances) FailAfter(alertName string, jobType platformidentification.JobType) (time.Duration, error) {
 	allowed, _, _ := getClosestPercentilesValues(alertName, jobType)
+	if failAfter, ok := allowed.Percentile(d.failPercentile); ok {
+		return failAfter, nil
+	}
 	return allowed.P99, nil
 }
 
 func (d *percentileAllowances) FlakeAfter(alertName string, jobType platformidentification.JobType) time.Duration {
 	allowed, _, _ := getClosestPercentilesValues(alertName, jobType)
+	if flakeAfter, ok := allowed.Percentile(d.flakePercentile); ok {
+		return flakeAfter
+	}
 	return allowed.P95
 }
 
 // getClosestPercentilesValues uses the backend and information about the cluster to choose the best historical p99 to operate against.
 // We enforce "don't get worse" for disruption by watching the aggregate data in CI over many runs.
 func getClosestPercentilesValues(alertName string, jobType platformidentification.JobType) (historicaldata.StatisticalDuration, string, error) {
-	return getCurrentResults().BestMatchDuration(alertName, jobType)
+	return getCurrentResults().BestMatchDurationWithMinimumJobRuns(alertName, jobType, minimumJobRunsForFallback)
 }
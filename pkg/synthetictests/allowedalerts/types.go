@@ -36,6 +36,13 @@ order by
 `
 )
 
+const (
+	// HistoricalDataType is the name of the data in the historical data directory, see historicaldata.LoadOverrides.
+	HistoricalDataType = "alerts"
+	// HistoricalDataNameField is the field holding the name in query_results.json.
+	HistoricalDataNameField = "AlertName"
+)
+
 // queryResults contains point in time results for the current aggregated query from above.
 // Hardcoding this does several things.
 //  1. it ensures that a degradation over time will be caught because this doesn't slip over time
@@ -57,9 +64,12 @@ const defaultReturn = 3.141
 func getCurrentResults() historicaldata.BestMatcher {
 	readResults.Do(
 		func() {
-			var err error
 			genericBytes := bytes.ReplaceAll(queryResults, []byte(`    "AlertName": "`), []byte(`    "Name": "`))
-			historicalData, err = historicaldata.NewMatcher(genericBytes, defaultReturn)
+			overrides, err := historicaldata.LoadOverrides(HistoricalDataType, HistoricalDataNameField)
+			if err != nil {
+				panic(err)
+			}
+			historicalData, err = historicaldata.NewMatcherWithOverrides(genericBytes, defaultReturn, overrides...)
 			if err != nil {
 				panic(err)
 			}
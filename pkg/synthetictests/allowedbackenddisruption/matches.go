@@ -15,6 +15,9 @@ const (
 	// effectively never fail, but will flake if we experience ANY disruption. We can use this
 	// to gather data, and correlate with real disruption in graphs.
 	allowedExternalDisruption = 600 * time.Second
+
+	// minimumJobRunsForFallback is how many job runs a fuzzy match has to be based on to be used.
+	minimumJobRunsForFallback = 10
 )
 
 // GetAllowedDisruption uses the backend and information about the cluster to choose the best historical p95 to operate against.
@@ -25,5 +28,9 @@ func GetAllowedDisruption(backendName string, jobType platformidentification.Job
 		aed := allowedExternalDisruption
 		return &aed, "forgiving limit for disruption to an external service", nil
 	}
-	return getCurrentResults().BestMatchP99(backendName, jobType)
+	allowed, details, err := getCurrentResults().BestMatchDurationWithMinimumJobRuns(backendName, jobType, minimumJobRunsForFallback)
+	if err != nil {
+		return nil, details, err
+	}
+	return &allowed.P99, details, nil
 }
@@ -50,6 +50,13 @@ order by
 `
 )
 
+const (
+	// HistoricalDataType is the name of the data in the historical data directory, see historicaldata.LoadOverrides.
+	HistoricalDataType = "disruptions"
+	// HistoricalDataNameField is the field holding the name in query_results.json.
+	HistoricalDataNameField = "BackendName"
+)
+
 //go:embed query_results.json
 var queryResults []byte
 
@@ -65,9 +72,12 @@ const defaultReturn = 2.718
 func getCurrentResults() historicaldata.BestMatcher {
 	readResults.Do(
 		func() {
-			var err error
 			genericBytes := bytes.ReplaceAll(queryResults, []byte(`    "BackendName": "`), []byte(`    "Name": "`))
-			historicalData, err = historicaldata.NewMatcher(genericBytes, defaultReturn)
+			overrides, err := historicaldata.LoadOverrides(HistoricalDataType, HistoricalDataNameField)
+			if err != nil {
+				panic(err)
+			}
+			historicalData, err = historicaldata.NewMatcherWithOverrides(genericBytes, defaultReturn, overrides...)
 			if err != nil {
 				panic(err)
 			}
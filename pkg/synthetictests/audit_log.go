@@ -0,0 +1,156 @@
+package synthetictests
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// auditLogRequestLimits are the most requests of each kind a single user agent may make per hour.
+type auditLogRequestLimits struct {
+	maxListsWithoutResourceVersionPerHour int
+	maxThrottledPerHour                   int
+	maxShortWatchesPerHour                int
+
+	// minRequestsForServerErrorRate is how many requests a user agent must make before its 5xx rate is checked.
+	minRequestsForServerErrorRate int
+	// maxServerErrorRate is the largest fraction of requests of a user agent that may fail with a 5xx.
+	maxServerErrorRate float64
+
+	// excludedUserAgents are expected to make lots of requests, like the e2e tests themselves.
+	excludedUserAgents sets.String
+}
+
+func defaultAuditLogRequestLimits() auditLogRequestLimits {
+	return auditLogRequestLimits{
+		maxListsWithoutResourceVersionPerHour: 1000,
+		maxThrottledPerHour:                   100,
+		maxShortWatchesPerHour:                100,
+		minRequestsForServerErrorRate:         1000,
+		maxServerErrorRate:                    0.01,
+		excludedUserAgents:                    sets.NewString("openshift-tests", "e2e.test"),
+	}
+}
+
+// auditLogRequestSummary is the parsed form of a AuditLogRequestSummary interval.
+type auditLogRequestSummary struct {
+	userAgent                   string
+	hours                       float64
+	requests                    int
+	listsWithoutResourceVersion int
+	throttled                   int
+	serverErrors                int
+	shortWatches                int
+}
+
+func auditLogRequestSummariesFrom(events monitorapi.Intervals) []auditLogRequestSummary {
+	ret := []auditLogRequestSummary{}
+	for _, event := range events {
+		userAgent, ok := monitorapi.UserAgentFromLocator(event.Locator)
+		if !ok || monitorapi.ReasonFrom(event.Message) != monitorapi.AuditLogReasonRequestSummary {
+			continue
+		}
+		annotations := monitorapi.AnnotationsFromMessage(event.Message)
+		count := func(key string) int {
+			ret, _ := strconv.Atoi(annotations[key])
+			return ret
+		}
+		// short runs are held to the hourly limits
+		hours := event.To.Sub(event.From).Hours()
+		if hours < 1 {
+			hours = 1
+		}
+		ret = append(ret, auditLogRequestSummary{
+			userAgent:                   userAgent,
+			hours:                       hours,
+			requests:                    count("requests"),
+			listsWithoutResourceVersion: count("listsWithoutResourceVersion"),
+			throttled:                   count("throttled"),
+			serverErrors:                count("serverErrors"),
+			shortWatches:                count("shortWatches"),
+		})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].userAgent < ret[j].userAgent
+	})
+	return ret
+}
+
+func (l auditLogRequestLimits) findExcessiveRequests(summaries []auditLogRequestSummary) []string {
+	var messages []string
+	check := func(summary auditLogRequestSummary, kind string, count, maxPerHour int) {
+		if allowed := int(float64(maxPerHour) * summary.hours); count > allowed {
+			messages = append(messages, fmt.Sprintf("user-agent/%s made %d %s, more than the %d allowed at %d/h",
+				summary.userAgent, count, kind, allowed, maxPerHour))
+		}
+	}
+	for _, summary := range summaries {
+		if l.excludedUserAgents.Has(summary.userAgent) {
+			continue
+		}
+		check(summary, "lists without a resourceVersion", summary.listsWithoutResourceVersion, l.maxListsWithoutResourceVersionPerHour)
+		check(summary, "throttled requests", summary.throttled, l.maxThrottledPerHour)
+		check(summary, "watches that were reset", summary.shortWatches, l.maxShortWatchesPerHour)
+	}
+	return messages
+}
+
+func (l auditLogRequestLimits) findServerErrorRates(summaries []auditLogRequestSummary) []string {
+	var messages []string
+	total := auditLogRequestSummary{userAgent: "all user agents"}
+	for _, summary := range summaries {
+		total.requests += summary.requests
+		total.serverErrors += summary.serverErrors
+	}
+	for _, summary := range append(summaries, total) {
+		if summary.requests < l.minRequestsForServerErrorRate {
+			continue
+		}
+		if rate := float64(summary.serverErrors) / float64(summary.requests); rate > l.maxServerErrorRate {
+			messages = append(messages, fmt.Sprintf("%s got a 5xx for %d of %d requests (%.2f%%), more than the %.2f%% allowed",
+				summary.userAgent, summary.serverErrors, summary.requests, rate*100, l.maxServerErrorRate*100))
+		}
+	}
+	return messages
+}
+
+// testAuditLogRequests checks the requests read from the kube-apiserver audit logs for clients that hammer the API
+// and for requests failing with a 5xx.  There are no results when the audit logs could not be read.  Both tests only
+// flake for now.
+func testAuditLogRequests(events monitorapi.Intervals) []*junitapi.JUnitTestCase {
+	const excessiveRequestsTestName = "[sig-api-machinery] clients should not make excessive requests to the kube-apiserver"
+	const serverErrorsTestName = "[sig-api-machinery] kube-apiserver should not fail requests with a 5xx at an excessive rate"
+
+	summaries := auditLogRequestSummariesFrom(events)
+	if len(summaries) == 0 {
+		return nil
+	}
+	limits := defaultAuditLogRequestLimits()
+
+	tests := []*junitapi.JUnitTestCase{}
+	if messages := limits.findExcessiveRequests(summaries); len(messages) > 0 {
+		tests = append(tests, &junitapi.JUnitTestCase{
+			Name: excessiveRequestsTestName,
+			FailureOutput: &junitapi.FailureOutput{
+				Output: fmt.Sprintf("%d excessive request patterns\n\n%v", len(messages), strings.Join(messages, "\n")),
+			},
+		})
+	}
+	tests = append(tests, &junitapi.JUnitTestCase{Name: excessiveRequestsTestName})
+
+	if messages := limits.findServerErrorRates(summaries); len(messages) > 0 {
+		tests = append(tests, &junitapi.JUnitTestCase{
+			Name: serverErrorsTestName,
+			FailureOutput: &junitapi.FailureOutput{
+				Output: fmt.Sprintf("%d excessive 5xx rates\n\n%v", len(messages), strings.Join(messages, "\n")),
+			},
+		})
+	}
+	tests = append(tests, &junitapi.JUnitTestCase{Name: serverErrorsTestName})
+	return tests
+}
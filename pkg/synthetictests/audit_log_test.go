@@ -0,0 +1,68 @@
+package synthetictests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func Test_testAuditLogRequests(t *testing.T) {
+	start := time.Date(2022, 7, 5, 17, 0, 0, 0, time.UTC)
+	summary := func(userAgent, message string, duration time.Duration) monitorapi.EventInterval {
+		return monitorapi.EventInterval{
+			Condition: monitorapi.Condition{Level: monitorapi.Info, Locator: monitorapi.UserAgentLocator(userAgent), Message: message},
+			From:      start,
+			To:        start.Add(duration),
+		}
+	}
+
+	tests := []struct {
+		name                string
+		events              monitorapi.Intervals
+		wantExcessive       int
+		wantServerErrorRate int
+	}{
+		{
+			name: "no audit logs",
+		},
+		{
+			name: "well behaved",
+			events: monitorapi.Intervals{
+				summary("kube-controller-manager", "reason/AuditLogRequestSummary requests/50000 listsWithoutResourceVersion/100 throttled/0 serverErrors/10 shortWatches/5", time.Hour),
+			},
+		},
+		{
+			name: "excessive lists over a long run are allowed",
+			events: monitorapi.Intervals{
+				summary("kube-controller-manager", "reason/AuditLogRequestSummary requests/50000 listsWithoutResourceVersion/2500 throttled/0 serverErrors/0 shortWatches/0", 3*time.Hour),
+			},
+		},
+		{
+			name: "excessive lists, throttling and errors",
+			events: monitorapi.Intervals{
+				summary("cluster-version-operator", "reason/AuditLogRequestSummary requests/5000 listsWithoutResourceVersion/2500 throttled/500 serverErrors/100 shortWatches/0", time.Hour),
+				summary("openshift-tests", "reason/AuditLogRequestSummary requests/100000 listsWithoutResourceVersion/50000 throttled/0 serverErrors/0 shortWatches/0", time.Hour),
+			},
+			wantExcessive:       2,
+			wantServerErrorRate: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summaries := auditLogRequestSummariesFrom(tt.events)
+			limits := defaultAuditLogRequestLimits()
+			if actual := limits.findExcessiveRequests(summaries); len(actual) != tt.wantExcessive {
+				t.Errorf("expected %d excessive requests, got %v", tt.wantExcessive, actual)
+			}
+			if actual := limits.findServerErrorRates(summaries); len(actual) != tt.wantServerErrorRate {
+				t.Errorf("expected %d excessive 5xx rates, got %v", tt.wantServerErrorRate, actual)
+			}
+
+			junits := testAuditLogRequests(tt.events)
+			if len(tt.events) == 0 && len(junits) != 0 {
+				t.Errorf("expected no results without audit logs, got %d", len(junits))
+			}
+		})
+	}
+}
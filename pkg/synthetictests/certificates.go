@@ -0,0 +1,143 @@
+package synthetictests
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const (
+	// CertificateExpiryWindowEnvVar sets the certificate expiry window when --certificate-expiry-window is not set.
+	CertificateExpiryWindowEnvVar = "TEST_CERTIFICATE_EXPIRY_WINDOW"
+
+	// defaultCertificateExpiryWindow is how long after the run a certificate must stay valid.  Clusters that are kept
+	// after a run, like the ones used to gather must-gathers, must not break a day later.
+	defaultCertificateExpiryWindow = 24 * time.Hour
+)
+
+var (
+	certificateExpiryWindowLock sync.Mutex
+	certificateExpiryWindow     *time.Duration
+)
+
+// SetCertificateExpiryWindow overrides CertificateExpiryWindowEnvVar.
+func SetCertificateExpiryWindow(window time.Duration) {
+	certificateExpiryWindowLock.Lock()
+	defer certificateExpiryWindowLock.Unlock()
+	certificateExpiryWindow = &window
+}
+
+// CertificateExpiryWindow returns how long after the end of the run every certificate must still be valid.  Clusters
+// that are thrown away right after the run can use a shorter window.
+func CertificateExpiryWindow() time.Duration {
+	certificateExpiryWindowLock.Lock()
+	defer certificateExpiryWindowLock.Unlock()
+	if certificateExpiryWindow != nil {
+		return *certificateExpiryWindow
+	}
+	if window, err := time.ParseDuration(os.Getenv(CertificateExpiryWindowEnvVar)); err == nil {
+		return window
+	}
+	return defaultCertificateExpiryWindow
+}
+
+// certificateEvaluator checks the certificates and CA bundles recorded by the monitor.
+type certificateEvaluator struct {
+	// expiryWindow is how long after the end of the run every certificate must still be valid.
+	expiryWindow time.Duration
+}
+
+func defaultCertificateEvaluator() certificateEvaluator {
+	return certificateEvaluator{
+		expiryWindow: CertificateExpiryWindow(),
+	}
+}
+
+// findExpiringCertificates returns a message for every secret or CA bundle whose last recorded certificate expires
+// before the end of the run plus the expiry window.
+func (e certificateEvaluator) findExpiringCertificates(events monitorapi.Intervals, end time.Time) []string {
+	locatorToNotAfter := map[string]time.Time{}
+	for _, event := range events {
+		if !monitorapi.IsCertificateHolder(event.Locator) {
+			continue
+		}
+		switch monitorapi.ReasonFrom(event.Message) {
+		case monitorapi.CertificateReasonObserved, monitorapi.CertificateReasonRotated,
+			monitorapi.CABundleReasonObserved, monitorapi.CABundleReasonRotated:
+		default:
+			continue
+		}
+		notAfter, err := time.Parse(time.RFC3339, monitorapi.AnnotationsFromMessage(event.Message)["notAfter"])
+		if err != nil {
+			continue
+		}
+		locatorToNotAfter[event.Locator] = notAfter
+	}
+
+	deadline := end.Add(e.expiryWindow)
+	var messages []string
+	for locator, notAfter := range locatorToNotAfter {
+		if notAfter.After(deadline) {
+			continue
+		}
+		messages = append(messages, fmt.Sprintf("%s expires at %s, less than %v after the end of the run at %s",
+			locator, notAfter.UTC().Format(time.RFC3339), e.expiryWindow, end.UTC().Format(time.RFC3339)))
+	}
+	sort.Strings(messages)
+	return messages
+}
+
+// findCABundlesRotatedWithoutOverlap returns a message for every CA bundle that dropped all of its certificates at
+// the same time it added new ones.
+func findCABundlesRotatedWithoutOverlap(events monitorapi.Intervals) []string {
+	var messages []string
+	for _, event := range events {
+		if !monitorapi.IsCertificateHolder(event.Locator) {
+			continue
+		}
+		if monitorapi.ReasonFrom(event.Message) != monitorapi.CABundleReasonRotated {
+			continue
+		}
+		annotations := monitorapi.AnnotationsFromMessage(event.Message)
+		retained, err := strconv.Atoi(annotations["retained"])
+		if err != nil || retained > 0 {
+			continue
+		}
+		if removed, err := strconv.Atoi(annotations["removed"]); err != nil || removed == 0 {
+			continue
+		}
+		messages = append(messages, fmt.Sprintf("%s was rotated without overlap at %s: %s",
+			event.Locator, event.From.UTC().Format(time.RFC3339), event.Message))
+	}
+	return messages
+}
+
+// testCertificateRotation flags certificates that expire shortly after the run and CA bundles that were rotated
+// without keeping the previous CA.  Both are recorded from the secrets and configmaps in openshift namespaces.  It only
+// flakes until we know which certificates are expected to be short lived.
+func testCertificateRotation(events monitorapi.Intervals) []*junitapi.JUnitTestCase {
+	const testName = "[sig-auth] certificates in openshift namespaces should not expire soon after the run or be rotated without overlap"
+
+	_, end := events.Span()
+	messages := defaultCertificateEvaluator().findExpiringCertificates(events, end)
+	messages = append(messages, findCABundlesRotatedWithoutOverlap(events)...)
+
+	tests := []*junitapi.JUnitTestCase{}
+	if len(messages) > 0 {
+		tests = append(tests, &junitapi.JUnitTestCase{
+			Name: testName,
+			FailureOutput: &junitapi.FailureOutput{
+				Output: fmt.Sprintf("%d certificate problems\n\n%v", len(messages), strings.Join(messages, "\n")),
+			},
+		})
+	}
+	tests = append(tests, &junitapi.JUnitTestCase{Name: testName})
+	return tests
+}
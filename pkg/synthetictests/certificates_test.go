@@ -0,0 +1,71 @@
+package synthetictests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func Test_certificateEvaluator_findExpiringCertificates(t *testing.T) {
+	end := time.Date(2022, 7, 5, 18, 0, 0, 0, time.UTC)
+	event := func(locator, message string) monitorapi.EventInterval {
+		return monitorapi.EventInterval{
+			Condition: monitorapi.Condition{Level: monitorapi.Info, Locator: locator, Message: message},
+			From:      end.Add(-time.Hour),
+			To:        end.Add(-time.Hour),
+		}
+	}
+	events := monitorapi.Intervals{
+		event(monitorapi.SecretLocator("openshift-a", "fine"), "reason/CertificateObserved commonName/a notBefore/2022-07-05T00:00:00Z notAfter/2022-08-05T00:00:00Z"),
+		event(monitorapi.SecretLocator("openshift-a", "expiring"), "reason/CertificateObserved commonName/a notBefore/2022-07-05T00:00:00Z notAfter/2022-07-06T00:00:00Z"),
+		event(monitorapi.SecretLocator("openshift-a", "rotated"), "reason/CertificateObserved commonName/a notBefore/2022-07-05T00:00:00Z notAfter/2022-07-06T00:00:00Z"),
+		event(monitorapi.SecretLocator("openshift-a", "rotated"), "reason/CertificateRotated commonName/a notBefore/2022-07-05T17:00:00Z notAfter/2022-08-05T00:00:00Z previousNotBefore/2022-07-05T00:00:00Z previousNotAfter/2022-07-06T00:00:00Z"),
+		event(monitorapi.ConfigMapLocator("openshift-b", "ca"), "reason/CABundleObserved certificates/2 notAfter/2022-07-06T12:00:00Z"),
+	}
+
+	actual := defaultCertificateEvaluator().findExpiringCertificates(events, end)
+	expected := []string{
+		"ns/openshift-a secret/expiring expires at 2022-07-06T00:00:00Z, less than 24h0m0s after the end of the run at 2022-07-05T18:00:00Z",
+		"ns/openshift-b configmap/ca expires at 2022-07-06T12:00:00Z, less than 24h0m0s after the end of the run at 2022-07-05T18:00:00Z",
+	}
+	if len(actual) != len(expected) {
+		t.Fatalf("unexpected messages: %v", actual)
+	}
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], actual[i])
+		}
+	}
+}
+
+func Test_findCABundlesRotatedWithoutOverlap(t *testing.T) {
+	locator := monitorapi.ConfigMapLocator("openshift-config-managed", "kube-apiserver-client-ca")
+	events := monitorapi.Intervals{
+		{Condition: monitorapi.Condition{Locator: locator, Message: "reason/CABundleRotated certificates/2 added/1 removed/0 retained/1 notAfter/2022-08-05T00:00:00Z"}},
+		{Condition: monitorapi.Condition{Locator: locator, Message: "reason/CABundleRotated certificates/1 added/0 removed/1 retained/1 notAfter/2022-08-05T00:00:00Z"}},
+		{Condition: monitorapi.Condition{Locator: locator, Message: "reason/CABundleRotated certificates/1 added/1 removed/1 retained/0 notAfter/2022-09-05T00:00:00Z"}},
+	}
+	if actual := findCABundlesRotatedWithoutOverlap(events); len(actual) != 1 {
+		t.Errorf("expected a single rotation without overlap, got %v", actual)
+	}
+}
+
+func TestCertificateExpiryWindow(t *testing.T) {
+	defer func() {
+		certificateExpiryWindow = nil
+	}()
+
+	t.Setenv(CertificateExpiryWindowEnvVar, "")
+	if window := CertificateExpiryWindow(); window != defaultCertificateExpiryWindow {
+		t.Errorf("expected the default window, got %v", window)
+	}
+	t.Setenv(CertificateExpiryWindowEnvVar, "2h")
+	if window := CertificateExpiryWindow(); window != 2*time.Hour {
+		t.Errorf("expected the window from the environment, got %v", window)
+	}
+	SetCertificateExpiryWindow(time.Hour)
+	if window := CertificateExpiryWindow(); window != time.Hour {
+		t.Errorf("expected the window that was set, got %v", window)
+	}
+}
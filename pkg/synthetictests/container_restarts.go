@@ -0,0 +1,243 @@
+package synthetictests
+
+import (
+	_ "embed"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	corev1 "k8s.io/api/core/v1"
+)
+
+//go:embed container_restart_budgets.yaml
+var containerRestartBudgetsYAML []byte
+
+// defaultContainerRestartBudgets are the budgets checked in with origin.
+var defaultContainerRestartBudgets = mustParse(parseContainerRestartBudgets, containerRestartBudgetsYAML)
+
+const (
+	// topRestartingOwners is how many of the owners with the most restarts are listed in the output.
+	topRestartingOwners = 10
+)
+
+// containerRestartBudgetFile is the serialized form of container_restart_budgets.yaml.
+type containerRestartBudgetFile struct {
+	versionedDataFile
+	DefaultMaxRestarts int                                `json:"defaultMaxRestarts"`
+	Budgets            []serializedContainerRestartBudget `json:"budgets,omitempty"`
+}
+
+type serializedContainerRestartBudget struct {
+	NamespacePattern string `json:"namespacePattern"`
+	MaxRestarts      int    `json:"maxRestarts"`
+	Owner            string `json:"owner,omitempty"`
+}
+
+// containerRestartBudget raises or lowers the restart budget of the workloads in matching namespaces.
+type containerRestartBudget struct {
+	namespace   *regexp.Regexp
+	maxRestarts int
+}
+
+// containerRestartBudgets are how many times the containers of a single workload may restart during a run.
+type containerRestartBudgets struct {
+	defaultMaxRestarts int
+	// budgets are checked in order, the first matching budget wins.
+	budgets []containerRestartBudget
+}
+
+func parseContainerRestartBudgets(data []byte) (containerRestartBudgets, error) {
+	ret := containerRestartBudgets{}
+
+	file := containerRestartBudgetFile{}
+	if err := parseDataFile("container restart budgets", data, &file); err != nil {
+		return ret, err
+	}
+	if file.DefaultMaxRestarts < 0 {
+		return ret, fmt.Errorf("defaultMaxRestarts must not be negative")
+	}
+	ret.defaultMaxRestarts = file.DefaultMaxRestarts
+
+	for i, budget := range file.Budgets {
+		if len(budget.NamespacePattern) == 0 {
+			return ret, fmt.Errorf("budget %d: namespacePattern is required", i)
+		}
+		if budget.MaxRestarts < 0 {
+			return ret, fmt.Errorf("budget %d: maxRestarts must not be negative", i)
+		}
+		re, err := regexp.Compile(budget.NamespacePattern)
+		if err != nil {
+			return ret, fmt.Errorf("budget %d: invalid namespacePattern: %w", i, err)
+		}
+		ret.budgets = append(ret.budgets, containerRestartBudget{namespace: re, maxRestarts: budget.MaxRestarts})
+	}
+
+	return ret, nil
+}
+
+func (b containerRestartBudgets) budgetFor(namespace string) int {
+	for _, budget := range b.budgets {
+		if budget.namespace.MatchString(namespace) {
+			return budget.maxRestarts
+		}
+	}
+	return b.defaultMaxRestarts
+}
+
+// workloadRestarts are the container restarts of every pod owned by the same workload.
+type workloadRestarts struct {
+	namespace string
+	kind      string
+	name      string
+
+	restarts int
+	// exits counts the container exits by exit code and termination reason.
+	exits map[string]int
+}
+
+func (w workloadRestarts) String() string {
+	return fmt.Sprintf("ns/%s %s/%s", w.namespace, strings.ToLower(w.kind), w.name)
+}
+
+func (w workloadRestarts) exitSummary() string {
+	exits := []string{}
+	for exit := range w.exits {
+		exits = append(exits, exit)
+	}
+	sort.Slice(exits, func(i, j int) bool {
+		if w.exits[exits[i]] != w.exits[exits[j]] {
+			return w.exits[exits[i]] > w.exits[exits[j]]
+		}
+		return exits[i] < exits[j]
+	})
+	for i := range exits {
+		exits[i] = fmt.Sprintf("%dx %s", w.exits[exits[i]], exits[i])
+	}
+	return strings.Join(exits, ", ")
+}
+
+// workloadOwnerOf returns the Deployment, DaemonSet or StatefulSet that owns the pod.  Deployments own pods through a
+// ReplicaSet named after the deployment and the pod template hash, and ReplicaSets are not recorded.
+func workloadOwnerOf(pod *corev1.Pod) (string, string, bool) {
+	for _, owner := range pod.OwnerReferences {
+		if owner.Controller == nil || !*owner.Controller {
+			continue
+		}
+		switch owner.Kind {
+		case "DaemonSet", "StatefulSet":
+			return owner.Kind, owner.Name, true
+		case "ReplicaSet":
+			hash, ok := pod.Labels["pod-template-hash"]
+			if !ok || !strings.HasSuffix(owner.Name, "-"+hash) {
+				return owner.Kind, owner.Name, true
+			}
+			return "Deployment", strings.TrimSuffix(owner.Name, "-"+hash), true
+		}
+	}
+	return "", "", false
+}
+
+// workloadRestartsFrom sums the restarts of every pod in an openshift namespace during the run by the workload that
+// owns it, and adds the exit codes and termination reasons of the container exits of those pods.  The restarts of a
+// pod are the restart counts of its last recorded state minus the restart counts when the monitor first saw it.
+func workloadRestartsFrom(events monitorapi.Intervals, recordedPods monitorapi.InstanceMap) []*workloadRestarts {
+	keyToWorkload := map[string]*workloadRestarts{}
+	podUIDToWorkload := map[string]*workloadRestarts{}
+	for _, obj := range recordedPods {
+		pod, ok := obj.(*corev1.Pod)
+		if !ok || !strings.HasPrefix(pod.Namespace, "openshift-") {
+			continue
+		}
+		kind, name, ok := workloadOwnerOf(pod)
+		if !ok {
+			continue
+		}
+		key := pod.Namespace + "/" + kind + "/" + name
+		workload, ok := keyToWorkload[key]
+		if !ok {
+			workload = &workloadRestarts{namespace: pod.Namespace, kind: kind, name: name, exits: map[string]int{}}
+			keyToWorkload[key] = workload
+		}
+		podUIDToWorkload[string(pod.UID)] = workload
+
+		restarts := 0
+		for _, containerStatus := range append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...) {
+			restarts += int(containerStatus.RestartCount)
+		}
+		if initialRestarts, err := strconv.Atoi(pod.Annotations[monitorapi.ObservedInitialRestartCountAnnotation]); err == nil {
+			restarts -= initialRestarts
+		}
+		if restarts > 0 {
+			workload.restarts += restarts
+		}
+	}
+
+	for _, event := range events {
+		if monitorapi.ReasonFrom(event.Message) != monitorapi.ContainerReasonContainerExit {
+			continue
+		}
+		workload, ok := podUIDToWorkload[monitorapi.PodFrom(event.Locator).UID]
+		if !ok {
+			continue
+		}
+		annotations := monitorapi.AnnotationsFromMessage(event.Message)
+		workload.exits[fmt.Sprintf("code/%s cause/%s", annotations["code"], annotations["cause"])]++
+	}
+
+	ret := []*workloadRestarts{}
+	for _, workload := range keyToWorkload {
+		if workload.restarts > 0 {
+			ret = append(ret, workload)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].restarts != ret[j].restarts {
+			return ret[i].restarts > ret[j].restarts
+		}
+		return ret[i].String() < ret[j].String()
+	})
+	return ret
+}
+
+// testContainerRestartBudget compares the container restarts of every workload in an openshift namespace over the
+// entire run against the budget of its namespace.  Unlike testContainerFailures, which looks at each container, a
+// workload with many replicas that each restart once is caught here.  The owners with the most restarts are always
+// listed to make tuning the budgets easier.
+func testContainerRestartBudget(events monitorapi.Intervals, recordedResources *monitorapi.ResourcesMap, budgets containerRestartBudgets) []*junitapi.JUnitTestCase {
+	const testName = "[sig-architecture] platform workloads should not restart containers more than their budget"
+	if recordedResources == nil {
+		return nil
+	}
+
+	workloads := workloadRestartsFrom(events, (*recordedResources)["pods"])
+	var overBudget, top []string
+	for i, workload := range workloads {
+		budget := budgets.budgetFor(workload.namespace)
+		summary := fmt.Sprintf("%v restarted %d times (budget %d): %s", workload, workload.restarts, budget, workload.exitSummary())
+		if i < topRestartingOwners {
+			top = append(top, summary)
+		}
+		if workload.restarts > budget {
+			overBudget = append(overBudget, summary)
+		}
+	}
+
+	systemOut := fmt.Sprintf("top restarting workloads\n\n%v", strings.Join(top, "\n"))
+	tests := []*junitapi.JUnitTestCase{}
+	if len(overBudget) > 0 {
+		tests = append(tests, &junitapi.JUnitTestCase{
+			Name:      testName,
+			SystemOut: systemOut,
+			FailureOutput: &junitapi.FailureOutput{
+				Output: fmt.Sprintf("%d workloads restarted more than their budget\n\n%v", len(overBudget), strings.Join(overBudget, "\n")),
+			},
+		})
+	}
+	// only flake for now
+	tests = append(tests, &junitapi.JUnitTestCase{Name: testName, SystemOut: systemOut})
+	return tests
+}
@@ -0,0 +1,103 @@
+package synthetictests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const testContainerRestartBudgetsYAML = `
+version: 1
+defaultMaxRestarts: 5
+budgets:
+- namespacePattern: '^openshift-ovn-kubernetes$'
+  maxRestarts: 0
+`
+
+func restartingPod(namespace, name, uid string, owner metav1.OwnerReference, labels map[string]string, restarts ...int32) *corev1.Pod {
+	controller := true
+	owner.Controller = &controller
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       namespace,
+			Name:            name,
+			UID:             types.UID(uid),
+			Labels:          labels,
+			OwnerReferences: []metav1.OwnerReference{owner},
+		},
+	}
+	for _, restartCount := range restarts {
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{RestartCount: restartCount})
+	}
+	return pod
+}
+
+func Test_workloadRestartsFrom(t *testing.T) {
+	rs := metav1.OwnerReference{Kind: "ReplicaSet", Name: "console-7d8f9c6b5"}
+	ds := metav1.OwnerReference{Kind: "DaemonSet", Name: "ovnkube-node"}
+	hash := map[string]string{"pod-template-hash": "7d8f9c6b5"}
+	pods := monitorapi.InstanceMap{
+		{Namespace: "openshift-console", Name: "console-7d8f9c6b5-a", UID: "1"}:   restartingPod("openshift-console", "console-7d8f9c6b5-a", "1", rs, hash, 2),
+		{Namespace: "openshift-console", Name: "console-7d8f9c6b5-b", UID: "2"}:   restartingPod("openshift-console", "console-7d8f9c6b5-b", "2", rs, hash, 3, 1),
+		{Namespace: "openshift-ovn-kubernetes", Name: "ovnkube-node-x", UID: "3"}: restartingPod("openshift-ovn-kubernetes", "ovnkube-node-x", "3", ds, nil, 1),
+		{Namespace: "openshift-ovn-kubernetes", Name: "ovnkube-node-y", UID: "4"}: restartingPod("openshift-ovn-kubernetes", "ovnkube-node-y", "4", ds, nil, 0),
+		{Namespace: "e2e-test", Name: "pod", UID: "5"}:                            restartingPod("e2e-test", "pod", "5", ds, nil, 10),
+	}
+	// restarted before the monitor started
+	restartedBefore := restartingPod("openshift-ovn-kubernetes", "ovnkube-node-z", "6", ds, nil, 7)
+	restartedBefore.Annotations = map[string]string{monitorapi.ObservedInitialRestartCountAnnotation: "7"}
+	pods[monitorapi.InstanceKey{Namespace: "openshift-ovn-kubernetes", Name: "ovnkube-node-z", UID: "6"}] = restartedBefore
+	events := monitorapi.Intervals{
+		{Condition: monitorapi.Condition{Locator: "ns/openshift-console pod/console-7d8f9c6b5-a node/n uid/1 container/console", Message: "reason/ContainerExit code/2 cause/Error panic"}},
+		{Condition: monitorapi.Condition{Locator: "ns/openshift-console pod/console-7d8f9c6b5-b node/n uid/2 container/console", Message: "reason/ContainerExit code/2 cause/Error panic"}},
+		{Condition: monitorapi.Condition{Locator: "ns/openshift-console pod/console-7d8f9c6b5-b node/n uid/2 container/console", Message: "reason/ContainerExit code/137 cause/OOMKilled "}},
+	}
+
+	workloads := workloadRestartsFrom(events, pods)
+	if len(workloads) != 2 {
+		t.Fatalf("expected two restarting workloads, got %v", workloads)
+	}
+	if workloads[0].String() != "ns/openshift-console deployment/console" || workloads[0].restarts != 6 {
+		t.Errorf("unexpected first workload %v with %d restarts", workloads[0], workloads[0].restarts)
+	}
+	if summary := workloads[0].exitSummary(); summary != "2x code/2 cause/Error, 1x code/137 cause/OOMKilled" {
+		t.Errorf("unexpected exit summary %q", summary)
+	}
+	if workloads[1].String() != "ns/openshift-ovn-kubernetes daemonset/ovnkube-node" || workloads[1].restarts != 1 {
+		t.Errorf("unexpected second workload %v with %d restarts", workloads[1], workloads[1].restarts)
+	}
+
+	budgets, err := parseContainerRestartBudgets([]byte(testContainerRestartBudgetsYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	junits := testContainerRestartBudget(events, &monitorapi.ResourcesMap{"pods": pods}, budgets)
+	if len(junits) != 2 || junits[0].FailureOutput == nil {
+		t.Fatalf("expected a flake, got %#v", junits)
+	}
+	for _, expected := range []string{"deployment/console restarted 6 times (budget 5)", "daemonset/ovnkube-node restarted 1 times (budget 0)"} {
+		if !strings.Contains(junits[0].FailureOutput.Output, expected) {
+			t.Errorf("expected the output to contain %q, got %v", expected, junits[0].FailureOutput.Output)
+		}
+	}
+}
+
+func Test_parseContainerRestartBudgets(t *testing.T) {
+	invalid := map[string]string{
+		"missing pattern":  "version: 1\ndefaultMaxRestarts: 5\nbudgets:\n- maxRestarts: 1\n",
+		"invalid pattern":  "version: 1\ndefaultMaxRestarts: 5\nbudgets:\n- namespacePattern: '('\n  maxRestarts: 1\n",
+		"negative budget":  "version: 1\ndefaultMaxRestarts: 5\nbudgets:\n- namespacePattern: '^openshift-etcd$'\n  maxRestarts: -1\n",
+		"negative default": "version: 1\ndefaultMaxRestarts: -1\n",
+		"unknown field":    "version: 1\ndefaultMaxRestarts: 5\nmaxRestarts: 1\n",
+		"unknown version":  "version: 2\n",
+	}
+	for name, data := range invalid {
+		if _, err := parseContainerRestartBudgets([]byte(data)); err == nil {
+			t.Errorf("%v: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package synthetictests
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/yaml"
+)
+
+// dataFileVersion is the version of the data files checked in with origin, like the allowances and budgets of the
+// invariants.  It is bumped on incompatible changes, so that an old binary fails on a new file instead of misreading it.
+const dataFileVersion = 1
+
+// versionedDataFile is embedded in the serialized form of every data file.
+type versionedDataFile struct {
+	Version int `json:"version"`
+}
+
+func (f versionedDataFile) version() int {
+	return f.Version
+}
+
+type dataFile interface {
+	version() int
+}
+
+// parseDataFile strictly unmarshals a data file into file and checks its version.  name describes the file in errors.
+func parseDataFile(name string, data []byte, file dataFile) error {
+	if err := yaml.UnmarshalStrict(data, file); err != nil {
+		return fmt.Errorf("unable to parse %s: %w", name, err)
+	}
+	if file.version() != dataFileVersion {
+		return fmt.Errorf("unsupported %s version %d", name, file.version())
+	}
+	return nil
+}
+
+// mustParse is used to parse the data files checked in with origin at init, so that a bad file fails every unit test
+// instead of a CI run.
+func mustParse[T any](parse func([]byte) (T, error), data []byte) T {
+	ret, err := parse(data)
+	if err != nil {
+		panic(err)
+	}
+	return ret
+}
@@ -38,65 +38,9 @@ func combinedRegexp(arr ...*regexp.Regexp) *regexp.Regexp {
 	return regexp.MustCompile(s)
 }
 
+// allowedRepeatedEventPatterns are patterns that are not exceptions.  Either every repeat is ok, or the repeats are
+// evaluated by a dedicated test.  Exceptions for tests and bugs are in duplicated_events_exceptions.yaml.
 var allowedRepeatedEventPatterns = []*regexp.Regexp{
-	// [sig-apps] StatefulSet Basic StatefulSet functionality [StatefulSetBasic] should not deadlock when a pod's predecessor fails [Suite:openshift/conformance/parallel] [Suite:k8s]
-	// PauseNewPods intentionally causes readiness probe to fail.
-	regexp.MustCompile(`ns/e2e-statefulset-[0-9]+ pod/ss-[0-9] node/[a-z0-9.-]+ - reason/Unhealthy Readiness probe failed: `),
-
-	// [sig-apps] StatefulSet Basic StatefulSet functionality [StatefulSetBasic] should perform rolling updates and roll backs of template modifications [Conformance] [Suite:openshift/conformance/parallel/minimal] [Suite:k8s]
-	// breakPodHTTPProbe intentionally causes readiness probe to fail.
-	regexp.MustCompile(`ns/e2e-statefulset-[0-9]+ pod/ss2-[0-9] node/[a-z0-9.-]+ - reason/Unhealthy Readiness probe failed: HTTP probe failed with statuscode: 404`),
-
-	// [sig-node] Probing container ***
-	// these tests intentionally cause repeated probe failures to ensure good handling
-	regexp.MustCompile(`ns/e2e-container-probe-[0-9]+ .* probe failed: `),
-	regexp.MustCompile(`ns/e2e-container-probe-[0-9]+ .* probe warning: `),
-
-	// Kubectl Port forwarding ***
-	// The same pod name is used many times for all these tests with a tight readiness check to make the tests fast.
-	// This results in hundreds of events while the pod isn't ready.
-	regexp.MustCompile(`ns/e2e-port-forwarding-[0-9]+ pod/pfpod node/[a-z0-9.-]+ - reason/Unhealthy Readiness probe failed:`),
-
-	// should not start app containers if init containers fail on a RestartAlways pod
-	// the init container intentionally fails to start
-	regexp.MustCompile(`ns/e2e-init-container-[0-9]+ pod/pod-init-[a-z0-9.-]+ node/[a-z0-9.-]+ - reason/BackOff Back-off restarting failed container`),
-
-	// TestAllowedSCCViaRBAC and TestPodUpdateSCCEnforcement
-	// The pod is shaped to intentionally not be scheduled.  Looks like an artifact of the old integration testing.
-	regexp.MustCompile(`ns/e2e-test-scc-[a-z0-9]+ pod/.* - reason/FailedScheduling.*`),
-
-	// Security Context ** should not run with an explicit root user ID
-	// Security Context ** should not run without a specified user ID
-	// This container should never run
-	regexp.MustCompile(`ns/e2e-security-context-test-[0-9]+ pod/.*-root-uid node/[a-z0-9.-]+ - reason/Failed Error: container's runAsUser breaks non-root policy.*"`),
-
-	// PersistentVolumes-local tests should not run the pod when there is a volume node
-	// affinity and node selector conflicts.
-	regexp.MustCompile(`ns/e2e-persistent-local-volumes-test-[0-9]+ pod/pod-[a-z0-9.-]+ reason/FailedScheduling`),
-
-	// various DeploymentConfig tests trigger this by canceling multiple rollouts
-	regexp.MustCompile(`reason/DeploymentAwaitingCancellation Deployment of version [0-9]+ awaiting cancellation of older running deployments`),
-
-	// this image is used specifically to be one that cannot be pulled in our tests
-	regexp.MustCompile(`.*reason/BackOff Back-off pulling image "webserver:404"`),
-
-	// If image pulls in e2e namespaces fail catastrophically we'd expect them to lead to test failures
-	// We are deliberately not ignoring image pull failures for core component namespaces
-	regexp.MustCompile(`ns/e2e-.* reason/BackOff Back-off pulling image`),
-
-	// promtail crashlooping as its being started by sideloading manifests.  per @vrutkovs
-	regexp.MustCompile("ns/openshift-e2e-loki pod/loki-promtail.*Readiness probe"),
-
-	// Related to known bug below, but we do not need to report on loki: https://bugzilla.redhat.com/show_bug.cgi?id=1986370
-	regexp.MustCompile("ns/openshift-e2e-loki pod/loki-promtail.*reason/NetworkNotReady"),
-
-	// kube-apiserver guard probe failing due to kube-apiserver operands getting rolled out
-	// multiple times during the bootstrapping phase of a cluster installation
-	regexp.MustCompile("ns/openshift-kube-apiserver pod/kube-apiserver-guard.*ProbeError Readiness probe error"),
-	// the same thing happens for kube-controller-manager and kube-scheduler
-	regexp.MustCompile("ns/openshift-kube-controller-manager pod/kube-controller-manager-guard.*ProbeError Readiness probe error"),
-	regexp.MustCompile("ns/openshift-kube-scheduler pod/kube-scheduler-guard.*ProbeError Readiness probe error"),
-
 	// this is the less specific even sent by the kubelet when a probe was executed successfully but returned false
 	// we ignore this event because openshift has a patch in patch_prober that sends a more specific event about
 	// readiness failures in openshift-* namespaces.  We will catch the more specific ProbeError events.
@@ -118,10 +62,6 @@ var allowedRepeatedEventPatterns = []*regexp.Regexp{
 
 	// Separated out in testErrorUpdatingEndpointSlices
 	regexp.MustCompile(errorUpdatingEndpointSlicesRegex),
-
-	// If you see this error, it means enough was working to get this event which implies enough retries happened to allow initial openshift
-	// installation to succeed. Hence, we can ignore it.
-	regexp.MustCompile(`reason/FailedCreate .* error creating EC2 instance: InsufficientInstanceCapacity: We currently do not have sufficient .* capacity in the Availability Zone you requested`),
 }
 
 var allowedRepeatedEventFns = []isRepeatedEventOKFunc{
@@ -130,88 +70,17 @@ var allowedRepeatedEventFns = []isRepeatedEventOKFunc{
 
 // allowedUpgradeRepeatedEventPatterns are patterns of events that we should only allow during upgrades, not during normal execution.
 var allowedUpgradeRepeatedEventPatterns = []*regexp.Regexp{
-	// Operators that use library-go can report about multiple versions during upgrades.
-	regexp.MustCompile(`ns/openshift-etcd-operator deployment/etcd-operator - reason/MultipleVersions multiple versions found, probably in transition: .*`),
-	regexp.MustCompile(`ns/openshift-kube-apiserver-operator deployment/kube-apiserver-operator - reason/MultipleVersions multiple versions found, probably in transition: .*`),
-	regexp.MustCompile(`ns/openshift-kube-controller-manager-operator deployment/kube-controller-manager-operator - reason/MultipleVersions multiple versions found, probably in transition: .*`),
-	regexp.MustCompile(`ns/openshift-kube-scheduler-operator deployment/openshift-kube-scheduler-operator - reason/MultipleVersions multiple versions found, probably in transition: .*`),
-
-	// etcd-quorum-guard can fail during upgrades.
-	regexp.MustCompile(`ns/openshift-etcd pod/etcd-quorum-guard-[a-z0-9-]+ node/[a-z0-9.-]+ - reason/Unhealthy Readiness probe failed: `),
-	// etcd can have unhealthy members during an upgrade
-	regexp.MustCompile(`ns/openshift-etcd-operator deployment/etcd-operator - reason/UnhealthyEtcdMember unhealthy members: .*`),
-	// etcd-operator began to version etcd-endpoints configmap in 4.10 as part of static-pod-resource. During upgrade existing revisions will not contain the resource.
-	// The condition reconciles with the next revision which the result of the upgrade. TODO(hexfusion) remove in 4.11
-	regexp.MustCompile(`ns/openshift-etcd-operator deployment/etcd-operator - reason/RequiredInstallerResourcesMissing configmaps: etcd-endpoints-[0-9]+`),
 	// There is a separate test to catch this specific case
 	regexp.MustCompile(requiredResourcesMissingRegEx),
 }
 
-var knownEventsBugs = []knownProblem{
-	{
-		Regexp: regexp.MustCompile(`ns/openshift-multus pod/network-metrics-daemon-[a-z0-9]+ node/[a-z0-9.-]+ - reason/NetworkNotReady network is not ready: container runtime network not ready: NetworkReady=false reason:NetworkPluginNotReady message:Network plugin returns error: No CNI configuration file in /etc/kubernetes/cni/net\.d/\. Has your network provider started\?`),
-		BZ:     "https://bugzilla.redhat.com/show_bug.cgi?id=1986370",
-	},
-	{
-		Regexp: regexp.MustCompile(`ns/openshift-e2e-loki pod/loki-promtail-[a-z0-9]+ node/[a-z0-9.-]+ - reason/NetworkNotReady network is not ready: container runtime network not ready: NetworkReady=false reason:NetworkPluginNotReady message:Network plugin returns error: No CNI configuration file in /etc/kubernetes/cni/net\.d/\. Has your network provider started\?`),
-		BZ:     "https://bugzilla.redhat.com/show_bug.cgi?id=1986370",
-	},
-	{
-		Regexp: regexp.MustCompile(`ns/openshift-network-diagnostics pod/network-check-target-[a-z0-9]+ node/[a-z0-9.-]+ - reason/NetworkNotReady network is not ready: container runtime network not ready: NetworkReady=false reason:NetworkPluginNotReady message:Network plugin returns error: No CNI configuration file in /etc/kubernetes/cni/net\.d/\. Has your network provider started\?`),
-		BZ:     "https://bugzilla.redhat.com/show_bug.cgi?id=1986370",
-	},
-	{
-		Regexp: regexp.MustCompile(`ns/.* service/.* - reason/FailedToDeleteOVNLoadBalancer .*`),
-		BZ:     "https://bugzilla.redhat.com/show_bug.cgi?id=1990631",
-	},
-	{
-		Regexp: regexp.MustCompile(`ns/.*horizontalpodautoscaler.*failed to get cpu utilization: unable to get metrics for resource cpu: no metrics returned from resource metrics API.*`),
-		BZ:     "https://bugzilla.redhat.com/show_bug.cgi?id=1993985",
-	},
-	{
-		Regexp: regexp.MustCompile(`ns/.*unable to ensure pod container exists: failed to create container.*slice already exists.*`),
-		BZ:     "https://bugzilla.redhat.com/show_bug.cgi?id=1993980",
-	},
-	{
-		Regexp: regexp.MustCompile(`ns/openshift-etcd pod/etcd-quorum-guard-[a-z0-9-]+ node/[a-z0-9.-]+ - reason/Unhealthy Readiness probe failed: `),
-		BZ:     "https://bugzilla.redhat.com/show_bug.cgi?id=2000234",
-	},
-	{
-		Regexp: regexp.MustCompile(`ns/openshift-etcd pod/etcd-guard-.* node/.* - reason/ProbeError Readiness probe error: .* connect: connection refused`),
-		BZ:     "https://bugzilla.redhat.com/show_bug.cgi?id=2075204",
-	},
-	{
-		Regexp: regexp.MustCompile("ns/openshift-etcd-operator namespace/openshift-etcd-operator -.*rpc error: code = Canceled desc = grpc: the client connection is closing.*"),
-		BZ:     "https://bugzilla.redhat.com/show_bug.cgi?id=2006975",
-	},
-	{
-		Regexp:   regexp.MustCompile("ns/.*reason/.*APICheckFailed.*503.*"),
-		BZ:       "https://bugzilla.redhat.com/show_bug.cgi?id=2017435",
-		Topology: topologyPointer(v1.SingleReplicaTopologyMode),
-	},
-	// builds tests trigger many changes in the config which creates new rollouts -> event for each pod
-	// working as intended (not a bug) and needs to be tolerated
-	{
-		Regexp:    regexp.MustCompile(`ns/openshift-route-controller-manager deployment/route-controller-manager - reason/ScalingReplicaSet \(combined from similar events\): Scaled (down|up) replica set route-controller-manager-[a-z0-9-]+ to [0-9]+`),
-		TestSuite: stringPointer("openshift/build"),
-	},
-	// builds tests trigger many changes in the config which creates new rollouts -> event for each pod
-	// working as intended (not a bug) and needs to be tolerated
-	{
-		Regexp:    regexp.MustCompile(`ns/openshift-controller-manager daemonset/controller-manager - reason/SuccessfulDelete \(combined from similar events\): Deleted pod: controller-manager-[a-z0-9-]+`),
-		TestSuite: stringPointer("openshift/build"),
-	},
-	//{ TODO this should only be skipped for single-node
-	//	name:    "single=node-storage",
-	//  BZ: https://bugzilla.redhat.com/show_bug.cgi?id=1990662
-	//	message: "ns/openshift-cluster-csi-drivers pod/aws-ebs-csi-driver-controller-66469455cd-2thfv node/ip-10-0-161-38.us-east-2.compute.internal - reason/BackOff Back-off restarting failed container",
-	//},
-}
-
 type duplicateEventsEvaluator struct {
 	allowedRepeatedEventPatterns []*regexp.Regexp
 	allowedRepeatedEventFns      []isRepeatedEventOKFunc
 
+	// allowedRepeatedEventExceptions are duplicates that are expected, but only in their scope.
+	allowedRepeatedEventExceptions []knownProblem
+
 	// knownRepeatedEventsBugs are duplicates that are considered bugs and should flake, but not  fail a test
 	knownRepeatedEventsBugs []knownProblem
 
@@ -223,6 +92,12 @@ type duplicateEventsEvaluator struct {
 
 	// testSuite contains the name of the test suite invoked.
 	testSuite string
+
+	// release contains the version of the cluster under test, or the version being upgraded to.
+	release string
+
+	// eventRateThresholds limit how often an event may happen.  Defaults to defaultEventRateThresholds.
+	eventRateThresholds eventRateThresholds
 }
 
 type knownProblem struct {
@@ -237,6 +112,12 @@ type knownProblem struct {
 
 	// TestSuite limits the exception to a specific test suite (e.g. openshift/builds)
 	TestSuite *string
+
+	// Owner is the team to ask before removing the exception.
+	Owner string
+
+	// ExpiresInRelease is the release in which the exception should no longer be needed (e.g. 4.12)
+	ExpiresInRelease string
 }
 
 func testDuplicatedEventForUpgrade(events monitorapi.Intervals, kubeClientConfig *rest.Config, testSuite string) []*junitapi.JUnitTestCase {
@@ -244,11 +125,16 @@ func testDuplicatedEventForUpgrade(events monitorapi.Intervals, kubeClientConfig
 	allowedPatterns = append(allowedPatterns, allowedRepeatedEventPatterns...)
 	allowedPatterns = append(allowedPatterns, allowedUpgradeRepeatedEventPatterns...)
 
+	allowedExceptions := []knownProblem{}
+	allowedExceptions = append(allowedExceptions, defaultDuplicatedEventExceptions.allowed...)
+	allowedExceptions = append(allowedExceptions, defaultDuplicatedEventExceptions.allowedDuringUpgrade...)
+
 	evaluator := duplicateEventsEvaluator{
-		allowedRepeatedEventPatterns: allowedPatterns,
-		allowedRepeatedEventFns:      allowedRepeatedEventFns,
-		knownRepeatedEventsBugs:      knownEventsBugs,
-		testSuite:                    testSuite,
+		allowedRepeatedEventPatterns:   allowedPatterns,
+		allowedRepeatedEventFns:        allowedRepeatedEventFns,
+		allowedRepeatedEventExceptions: allowedExceptions,
+		knownRepeatedEventsBugs:        defaultDuplicatedEventExceptions.knownBugs,
+		testSuite:                      testSuite,
 	}
 
 	if err := evaluator.getClusterInfo(kubeClientConfig); err != nil {
@@ -258,15 +144,18 @@ func testDuplicatedEventForUpgrade(events monitorapi.Intervals, kubeClientConfig
 	tests := []*junitapi.JUnitTestCase{}
 	tests = append(tests, evaluator.testDuplicatedCoreNamespaceEvents(events, kubeClientConfig)...)
 	tests = append(tests, evaluator.testDuplicatedE2ENamespaceEvents(events, kubeClientConfig)...)
+	tests = append(tests, evaluator.testDuplicatedEventRates(events, kubeClientConfig)...)
+	tests = append(tests, evaluator.testDuplicatedEventExceptions(events)...)
 	return tests
 }
 
 func testDuplicatedEventForStableSystem(events monitorapi.Intervals, clientConfig *rest.Config, testSuite string) []*junitapi.JUnitTestCase {
 	evaluator := duplicateEventsEvaluator{
-		allowedRepeatedEventPatterns: allowedRepeatedEventPatterns,
-		allowedRepeatedEventFns:      allowedRepeatedEventFns,
-		knownRepeatedEventsBugs:      knownEventsBugs,
-		testSuite:                    testSuite,
+		allowedRepeatedEventPatterns:   allowedRepeatedEventPatterns,
+		allowedRepeatedEventFns:        allowedRepeatedEventFns,
+		allowedRepeatedEventExceptions: defaultDuplicatedEventExceptions.allowed,
+		knownRepeatedEventsBugs:        defaultDuplicatedEventExceptions.knownBugs,
+		testSuite:                      testSuite,
 	}
 
 	operatorClient, err := operatorv1client.NewForConfig(clientConfig)
@@ -286,6 +175,8 @@ func testDuplicatedEventForStableSystem(events monitorapi.Intervals, clientConfi
 	tests := []*junitapi.JUnitTestCase{}
 	tests = append(tests, evaluator.testDuplicatedCoreNamespaceEvents(events, clientConfig)...)
 	tests = append(tests, evaluator.testDuplicatedE2ENamespaceEvents(events, clientConfig)...)
+	tests = append(tests, evaluator.testDuplicatedEventRates(events, clientConfig)...)
+	tests = append(tests, evaluator.testDuplicatedEventExceptions(events)...)
 	return tests
 }
 
@@ -326,21 +217,9 @@ func (d duplicateEventsEvaluator) testDuplicatedEvents(testName string, flakeOnl
 	for _, event := range events {
 		eventDisplayMessage, times := getTimesAnEventHappened(fmt.Sprintf("%s - %s", event.Locator, event.Message))
 		if times > duplicateEventThreshold {
-			if allowedRepeatedEventsRegex.MatchString(eventDisplayMessage) {
-				continue
-			}
-			allowed := false
-			for _, allowRepeatedEventFn := range d.allowedRepeatedEventFns {
-				var err error
-				allowed, err = allowRepeatedEventFn(event, kubeClientConfig, times)
-				if err != nil {
-					failures = append(failures, fmt.Sprintf("error: [%v] when processing event %v", err, eventDisplayMessage))
-					allowed = false
-					continue
-				}
-				if allowed {
-					break
-				}
+			allowed, errs := d.isAllowed(allowedRepeatedEventsRegex, event, eventDisplayMessage, kubeClientConfig, times)
+			for _, err := range errs {
+				failures = append(failures, fmt.Sprintf("error: [%v] when processing event %v", err, eventDisplayMessage))
 			}
 			if allowed {
 				continue
@@ -352,28 +231,8 @@ func (d duplicateEventsEvaluator) testDuplicatedEvents(testName string, flakeOnl
 	var flakes []string
 	for display, count := range displayToCount {
 		msg := fmt.Sprintf("event happened %d times, something is wrong: %v", count, display)
-		flake := false
-		for _, kp := range d.knownRepeatedEventsBugs {
-			if kp.Regexp != nil && kp.Regexp.MatchString(display) {
-				// Check if this exception only applies to our specific platform
-				if kp.Platform != nil && *kp.Platform != d.platform {
-					continue
-				}
-
-				// Check if this exception only applies to a specific topology
-				if kp.Topology != nil && *kp.Topology != d.topology {
-					continue
-				}
-
-				// Check if this exception only applies to a specific test suite
-				if kp.TestSuite != nil && *kp.TestSuite != d.testSuite {
-					continue
-				}
-
-				msg += " - " + kp.BZ
-				flake = true
-			}
-		}
+		bugs, flake := d.knownBugsFor(display)
+		msg += bugs
 
 		if flake || flakeOnly {
 			flakes = append(flakes, msg)
@@ -411,6 +270,55 @@ func (d duplicateEventsEvaluator) testDuplicatedEvents(testName string, flakeOnl
 	return tests
 }
 
+// isAllowed returns true if the repeated event is allowed by a pattern, an exception, or an allowance func.  Errors from
+// allowance funcs are returned so they can be reported, and do not allow the event.
+func (d duplicateEventsEvaluator) isAllowed(allowedRepeatedEventsRegex *regexp.Regexp, event monitorapi.EventInterval, eventDisplayMessage string, kubeClientConfig *rest.Config, times int) (bool, []error) {
+	if allowedRepeatedEventsRegex.MatchString(eventDisplayMessage) {
+		return true, nil
+	}
+	if d.isAllowedByException(eventDisplayMessage) {
+		return true, nil
+	}
+	var errs []error
+	for _, allowRepeatedEventFn := range d.allowedRepeatedEventFns {
+		allowed, err := allowRepeatedEventFn(event, kubeClientConfig, times)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if allowed {
+			return true, errs
+		}
+	}
+	return false, errs
+}
+
+func (d duplicateEventsEvaluator) isAllowedByException(eventDisplayMessage string) bool {
+	for _, kp := range d.allowedRepeatedEventExceptions {
+		if kp.Regexp != nil && kp.Regexp.MatchString(eventDisplayMessage) && kp.appliesTo(d.platform, d.topology, d.testSuite) {
+			return true
+		}
+	}
+	return false
+}
+
+// knownBugsFor returns the bugs of every known problem that matches the event, and whether there were any.
+func (d duplicateEventsEvaluator) knownBugsFor(eventDisplayMessage string) (string, bool) {
+	bugs := ""
+	found := false
+	for _, kp := range d.knownRepeatedEventsBugs {
+		if kp.Regexp != nil && kp.Regexp.MatchString(eventDisplayMessage) {
+			if !kp.appliesTo(d.platform, d.topology, d.testSuite) {
+				continue
+			}
+
+			bugs += " - " + kp.BZ
+			found = true
+		}
+	}
+	return bugs, found
+}
+
 var eventCountExtractor = regexp.MustCompile(`(?s)(.*) \((\d+) times\).*`)
 
 func getTimesAnEventHappened(message string) (string, int) {
@@ -561,6 +469,12 @@ func (d *duplicateEventsEvaluator) getClusterInfo(c *rest.Config) (err error) {
 		d.topology = infra.Status.ControlPlaneTopology
 	}
 
+	clusterVersion, err := oc.ConfigV1().ClusterVersions().Get(context.Background(), "version", metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	d.release = clusterVersion.Status.Desired.Version
+
 	return nil
 }
 
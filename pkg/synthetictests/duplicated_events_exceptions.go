@@ -0,0 +1,315 @@
+package synthetictests
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	v1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+//go:embed duplicated_events_exceptions.yaml
+var duplicatedEventExceptionsYAML []byte
+
+// defaultDuplicatedEventExceptions are the exceptions checked in with origin.
+var defaultDuplicatedEventExceptions = mustParse(parseDuplicatedEventExceptions, duplicatedEventExceptionsYAML)
+
+type duplicatedEventExceptionKind string
+
+const (
+	// duplicatedEventAllowed exceptions are expected repeats, usually caused on purpose by a test.
+	duplicatedEventAllowed duplicatedEventExceptionKind = "Allowed"
+	// duplicatedEventKnownBug exceptions are repeats caused by a bug.  They flake instead of failing.
+	duplicatedEventKnownBug duplicatedEventExceptionKind = "KnownBug"
+)
+
+// duplicatedEventExceptionFile is the serialized form of duplicated_events_exceptions.yaml.
+type duplicatedEventExceptionFile struct {
+	versionedDataFile
+	RateThresholds serializedEventRateThresholds        `json:"rateThresholds,omitempty"`
+	Exceptions     []serializedDuplicatedEventException `json:"exceptions"`
+}
+
+// serializedEventRateThresholds are the thresholds of "[sig-arch] events should not repeat at a pathological rate".
+type serializedEventRateThresholds struct {
+	// Window defaults to defaultEventRateWindow.
+	Window metav1.Duration `json:"window,omitempty"`
+	// Default defaults to duplicateEventThreshold.
+	Default int `json:"default,omitempty"`
+	// Reasons override the default for specific event reasons.
+	Reasons map[string]int `json:"reasons,omitempty"`
+}
+
+type serializedDuplicatedEventException struct {
+	Pattern          string                        `json:"pattern"`
+	Kind             duplicatedEventExceptionKind  `json:"kind"`
+	BugURL           string                        `json:"bugURL,omitempty"`
+	Owner            string                        `json:"owner,omitempty"`
+	ExpiresInRelease string                        `json:"expiresInRelease,omitempty"`
+	Scope            duplicatedEventExceptionScope `json:"scope,omitempty"`
+}
+
+type duplicatedEventExceptionScope struct {
+	Platform    *v1.PlatformType `json:"platform,omitempty"`
+	Topology    *v1.TopologyMode `json:"topology,omitempty"`
+	TestSuite   *string          `json:"testSuite,omitempty"`
+	UpgradeOnly bool             `json:"upgradeOnly,omitempty"`
+}
+
+// duplicatedEventExceptions are the parsed exceptions, split by how they are applied.
+type duplicatedEventExceptions struct {
+	allowed              []knownProblem
+	allowedDuringUpgrade []knownProblem
+	knownBugs            []knownProblem
+
+	rateThresholds eventRateThresholds
+}
+
+func parseDuplicatedEventExceptions(data []byte) (duplicatedEventExceptions, error) {
+	ret := duplicatedEventExceptions{}
+
+	file := duplicatedEventExceptionFile{}
+	if err := parseDataFile("duplicated event exceptions", data, &file); err != nil {
+		return ret, err
+	}
+
+	ret.rateThresholds = eventRateThresholds{
+		window:           defaultEventRateWindow,
+		defaultThreshold: duplicateEventThreshold,
+		reasonThresholds: map[string]int{},
+	}
+	if file.RateThresholds.Window.Duration < 0 || file.RateThresholds.Default < 0 {
+		return ret, fmt.Errorf("rateThresholds: window and default must not be negative")
+	}
+	if file.RateThresholds.Window.Duration > 0 {
+		ret.rateThresholds.window = file.RateThresholds.Window.Duration
+	}
+	if file.RateThresholds.Default > 0 {
+		ret.rateThresholds.defaultThreshold = file.RateThresholds.Default
+	}
+	for reason, threshold := range file.RateThresholds.Reasons {
+		if threshold <= 0 {
+			return ret, fmt.Errorf("rateThresholds: threshold for %s must be positive, not %d", reason, threshold)
+		}
+		ret.rateThresholds.reasonThresholds[reason] = threshold
+	}
+
+	for i, exception := range file.Exceptions {
+		re, err := regexp.Compile(exception.Pattern)
+		if err != nil {
+			return ret, fmt.Errorf("exception %d: invalid pattern: %w", i, err)
+		}
+		if len(exception.ExpiresInRelease) > 0 {
+			if _, _, ok := parseMajorMinor(exception.ExpiresInRelease); !ok {
+				return ret, fmt.Errorf("exception %d: expiresInRelease must look like 4.11, not %q", i, exception.ExpiresInRelease)
+			}
+		}
+
+		kp := knownProblem{
+			Regexp:           re,
+			BZ:               exception.BugURL,
+			Owner:            exception.Owner,
+			ExpiresInRelease: exception.ExpiresInRelease,
+			Platform:         exception.Scope.Platform,
+			Topology:         exception.Scope.Topology,
+			TestSuite:        exception.Scope.TestSuite,
+		}
+		switch {
+		case exception.Kind == duplicatedEventAllowed && exception.Scope.UpgradeOnly:
+			ret.allowedDuringUpgrade = append(ret.allowedDuringUpgrade, kp)
+		case exception.Kind == duplicatedEventAllowed:
+			ret.allowed = append(ret.allowed, kp)
+		case exception.Kind == duplicatedEventKnownBug && exception.Scope.UpgradeOnly:
+			return ret, fmt.Errorf("exception %d: upgradeOnly is only supported for %s exceptions", i, duplicatedEventAllowed)
+		case exception.Kind == duplicatedEventKnownBug:
+			ret.knownBugs = append(ret.knownBugs, kp)
+		default:
+			return ret, fmt.Errorf("exception %d: kind must be %s or %s, not %q", i, duplicatedEventAllowed, duplicatedEventKnownBug, exception.Kind)
+		}
+	}
+
+	return ret, nil
+}
+
+var majorMinorRegex = regexp.MustCompile(`^(\d+)\.(\d+)`)
+
+// parseMajorMinor parses the major and minor version from things like 4.11 and 4.11.0-0.nightly-2022-07-05-083948.
+func parseMajorMinor(version string) (int, int, bool) {
+	matches := majorMinorRegex.FindStringSubmatch(version)
+	if len(matches) != 3 {
+		return 0, 0, false
+	}
+	major, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return 0, 0, false
+	}
+	minor, err := strconv.Atoi(matches[2])
+	if err != nil {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
+
+// appliesTo returns true if the problem is not limited to another platform, topology or test suite.
+func (kp knownProblem) appliesTo(platform v1.PlatformType, topology v1.TopologyMode, testSuite string) bool {
+	// Check if this exception only applies to our specific platform
+	if kp.Platform != nil && *kp.Platform != platform {
+		return false
+	}
+
+	// Check if this exception only applies to a specific topology
+	if kp.Topology != nil && *kp.Topology != topology {
+		return false
+	}
+
+	// Check if this exception only applies to a specific test suite
+	if kp.TestSuite != nil && *kp.TestSuite != testSuite {
+		return false
+	}
+
+	return true
+}
+
+// expiredIn returns true if the release is at or past the release the exception expires in.  Unknown releases never
+// expire anything.
+func (kp knownProblem) expiredIn(release string) bool {
+	if len(kp.ExpiresInRelease) == 0 {
+		return false
+	}
+	major, minor, ok := parseMajorMinor(release)
+	if !ok {
+		return false
+	}
+	expiresMajor, expiresMinor, ok := parseMajorMinor(kp.ExpiresInRelease)
+	if !ok {
+		return false
+	}
+	if major != expiresMajor {
+		return major > expiresMajor
+	}
+	return minor >= expiresMinor
+}
+
+func (kp knownProblem) String() string {
+	details := []string{}
+	if len(kp.Owner) > 0 {
+		details = append(details, "owner "+kp.Owner)
+	}
+	if len(kp.BZ) > 0 {
+		details = append(details, kp.BZ)
+	}
+	if len(details) == 0 {
+		return fmt.Sprintf("%q", kp.Regexp.String())
+	}
+	return fmt.Sprintf("%q (%s)", kp.Regexp.String(), strings.Join(details, ", "))
+}
+
+// testDuplicatedEventExceptions flakes when an exception that applies to this cluster has expired or did not match any
+// repeated event, so that exceptions are removed once they are no longer needed.  Exceptions limited to other
+// platforms, topologies or suites are not checked.  How many events every exception matched is also recorded by
+// WriteDuplicatedEventExceptionUsageForJobRun.
+func (d duplicateEventsEvaluator) testDuplicatedEventExceptions(events monitorapi.Intervals) []*junitapi.JUnitTestCase {
+	const testName = "[sig-arch] duplicated event exceptions should be current"
+
+	repeatedEvents := repeatedEventDisplayMessages(events)
+	var stale []string
+	exceptions := []knownProblem{}
+	exceptions = append(exceptions, d.allowedRepeatedEventExceptions...)
+	exceptions = append(exceptions, d.knownRepeatedEventsBugs...)
+	for _, kp := range exceptions {
+		if kp.Regexp == nil || !kp.appliesTo(d.platform, d.topology, d.testSuite) {
+			continue
+		}
+		if kp.expiredIn(d.release) {
+			stale = append(stale, fmt.Sprintf("exception expired in %s: %v", kp.ExpiresInRelease, kp))
+			continue
+		}
+		matched := false
+		for _, eventDisplayMessage := range repeatedEvents {
+			if kp.Regexp.MatchString(eventDisplayMessage) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			stale = append(stale, fmt.Sprintf("exception did not match any repeated event: %v", kp))
+		}
+	}
+
+	tests := []*junitapi.JUnitTestCase{}
+	if len(stale) > 0 {
+		tests = append(tests, &junitapi.JUnitTestCase{
+			Name: testName,
+			FailureOutput: &junitapi.FailureOutput{
+				Output: fmt.Sprintf("%d exceptions have expired or did not match in %s\n\n%v", len(stale), d.release, strings.Join(stale, "\n")),
+			},
+		})
+	}
+	// always pass so this only ever flakes
+	tests = append(tests, &junitapi.JUnitTestCase{Name: testName})
+	return tests
+}
+
+// repeatedEventDisplayMessages returns "<locator> - <message>" of every event repeated often enough to be checked
+// against the exceptions.
+func repeatedEventDisplayMessages(events monitorapi.Intervals) []string {
+	var ret []string
+	for _, event := range events {
+		eventDisplayMessage, times := getTimesAnEventHappened(fmt.Sprintf("%s - %s", event.Locator, event.Message))
+		if times > duplicateEventThreshold {
+			ret = append(ret, eventDisplayMessage)
+		}
+	}
+	return ret
+}
+
+// duplicatedEventExceptionUsage is how many repeated events of a run an exception matched.
+type duplicatedEventExceptionUsage struct {
+	serializedDuplicatedEventException
+	MatchedEvents int `json:"matchedEvents"`
+}
+
+// WriteDuplicatedEventExceptionUsageForJobRun records how many repeated events every checked in exception matched.
+// It includes the exceptions out of scope for the run, so the usage of every exception can be aggregated across jobs.
+func WriteDuplicatedEventExceptionUsageForJobRun(artifactDir string, _ monitorapi.ResourcesMap, events monitorapi.Intervals, timeSuffix string) error {
+	usage, err := duplicatedEventExceptionUsageFor(duplicatedEventExceptionsYAML, repeatedEventDisplayMessages(events))
+	if err != nil {
+		return err
+	}
+	content, err := json.MarshalIndent(usage, "", "    ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(artifactDir, fmt.Sprintf("duplicated-event-exception-usage%s.json", timeSuffix)), content, 0644)
+}
+
+func duplicatedEventExceptionUsageFor(data []byte, repeatedEvents []string) ([]duplicatedEventExceptionUsage, error) {
+	file := duplicatedEventExceptionFile{}
+	if err := parseDataFile("duplicated event exceptions", data, &file); err != nil {
+		return nil, err
+	}
+
+	ret := []duplicatedEventExceptionUsage{}
+	for i, exception := range file.Exceptions {
+		re, err := regexp.Compile(exception.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("exception %d: invalid pattern: %w", i, err)
+		}
+		usage := duplicatedEventExceptionUsage{serializedDuplicatedEventException: exception}
+		for _, eventDisplayMessage := range repeatedEvents {
+			if re.MatchString(eventDisplayMessage) {
+				usage.MatchedEvents++
+			}
+		}
+		ret = append(ret, usage)
+	}
+	return ret, nil
+}
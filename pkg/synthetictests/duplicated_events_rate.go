@@ -0,0 +1,216 @@
+package synthetictests
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// defaultEventRateWindow is how long the sliding window used to calculate event rates is.
+	defaultEventRateWindow = 10 * time.Minute
+)
+
+// eventRateThresholds are the maximum number of times an event may happen inside any window.
+type eventRateThresholds struct {
+	window           time.Duration
+	defaultThreshold int
+
+	// reasonThresholds override the default threshold for specific event reasons.
+	reasonThresholds map[string]int
+}
+
+// defaultEventRateThresholds are the rateThresholds of duplicated_events_exceptions.yaml.
+func defaultEventRateThresholds() eventRateThresholds {
+	return defaultDuplicatedEventExceptions.rateThresholds
+}
+
+func (t eventRateThresholds) thresholdFor(reason string) int {
+	if threshold, ok := t.reasonThresholds[reason]; ok {
+		return threshold
+	}
+	return t.defaultThreshold
+}
+
+// eventBurst is a time range during which an event happened more often than its threshold allows.
+type eventBurst struct {
+	reason string
+
+	// event and eventDisplayMessage are the last event seen during the burst.
+	event               monitorapi.EventInterval
+	eventDisplayMessage string
+
+	from time.Time
+	to   time.Time
+
+	// count is the largest number of times the event happened inside a single window of the burst.
+	count     int
+	threshold int
+	window    time.Duration
+}
+
+func (b eventBurst) String() string {
+	return fmt.Sprintf("event happened %d times between %s and %s (threshold %d per %v), something is wrong: %v",
+		b.count, b.from.UTC().Format(time.RFC3339), b.to.UTC().Format(time.RFC3339), b.threshold, b.window, b.eventDisplayMessage)
+}
+
+type eventOccurrence struct {
+	at                  time.Time
+	occurrences         int
+	event               monitorapi.EventInterval
+	eventDisplayMessage string
+}
+
+// eventOccurrencesFrom groups events by locator and reason and calculates how many times each event happened at each
+// timestamp.  Kube events carry a running count, so the occurrences are the increase from the last count.  The first
+// time an event is seen counts once, because we do not know when the earlier occurrences happened.
+func eventOccurrencesFrom(events monitorapi.Intervals) map[string][]eventOccurrence {
+	keyToOccurrences := map[string][]eventOccurrence{}
+	keyToLastCount := map[string]int{}
+	for _, event := range events {
+		reason := monitorapi.ReasonFrom(event.Message)
+		if len(reason) == 0 {
+			continue
+		}
+		key := event.Locator + " reason/" + reason
+
+		eventDisplayMessage, times := getTimesAnEventHappened(fmt.Sprintf("%s - %s", event.Locator, event.Message))
+		if times == 0 {
+			eventDisplayMessage = fmt.Sprintf("%s - %s", event.Locator, event.Message)
+		}
+
+		// the same reason can come from several kube events, each with their own count.
+		lastCount, seen := keyToLastCount[eventDisplayMessage]
+		occurrences := 1
+		switch {
+		case !seen || times == 0:
+		case times > lastCount:
+			occurrences = times - lastCount
+		case times == lastCount:
+			occurrences = 0
+		}
+		keyToLastCount[eventDisplayMessage] = times
+
+		keyToOccurrences[key] = append(keyToOccurrences[key], eventOccurrence{
+			at:                  event.From,
+			occurrences:         occurrences,
+			event:               event,
+			eventDisplayMessage: eventDisplayMessage,
+		})
+	}
+
+	for key := range keyToOccurrences {
+		occurrences := keyToOccurrences[key]
+		sort.SliceStable(occurrences, func(i, j int) bool {
+			return occurrences[i].at.Before(occurrences[j].at)
+		})
+	}
+	return keyToOccurrences
+}
+
+// findEventBursts slides a window over the occurrences of every event and returns the time ranges in which an event
+// happened more often than its threshold.  Overlapping windows over the threshold are merged into a single burst.
+func findEventBursts(events monitorapi.Intervals, thresholds eventRateThresholds) []eventBurst {
+	var bursts []eventBurst
+
+	keyToOccurrences := eventOccurrencesFrom(events)
+	keys := []string{}
+	for key := range keyToOccurrences {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		occurrences := keyToOccurrences[key]
+		reason := monitorapi.ReasonFrom(occurrences[0].event.Message)
+		threshold := thresholds.thresholdFor(reason)
+
+		var current *eventBurst
+		windowStart := 0
+		windowCount := 0
+		for _, occurrence := range occurrences {
+			windowCount += occurrence.occurrences
+			for !occurrences[windowStart].at.After(occurrence.at.Add(-thresholds.window)) {
+				windowCount -= occurrences[windowStart].occurrences
+				windowStart++
+			}
+			if windowCount <= threshold {
+				continue
+			}
+
+			from := occurrences[windowStart].at
+			if current != nil && !from.After(current.to) {
+				current.to = occurrence.at
+				current.event = occurrence.event
+				current.eventDisplayMessage = occurrence.eventDisplayMessage
+				if windowCount > current.count {
+					current.count = windowCount
+				}
+				continue
+			}
+			if current != nil {
+				bursts = append(bursts, *current)
+			}
+			current = &eventBurst{
+				reason:              reason,
+				event:               occurrence.event,
+				eventDisplayMessage: occurrence.eventDisplayMessage,
+				from:                from,
+				to:                  occurrence.at,
+				count:               windowCount,
+				threshold:           threshold,
+				window:              thresholds.window,
+			}
+		}
+		if current != nil {
+			bursts = append(bursts, *current)
+		}
+	}
+
+	return bursts
+}
+
+// testDuplicatedEventRates fails on events that repeat faster than their threshold, no matter how long the run was.
+// Unlike testDuplicatedEvents, which compares the total count to a fixed threshold, this points at the time ranges in
+// which the events happened.  It only flakes for now.
+func (d duplicateEventsEvaluator) testDuplicatedEventRates(events monitorapi.Intervals, kubeClientConfig *rest.Config) []*junitapi.JUnitTestCase {
+	const testName = "[sig-arch] events should not repeat at a pathological rate"
+
+	allowedRepeatedEventsRegex := combinedRegexp(d.allowedRepeatedEventPatterns...)
+	thresholds := d.eventRateThresholds
+	if thresholds.window == 0 {
+		thresholds = defaultEventRateThresholds()
+	}
+
+	var messages []string
+	for _, burst := range findEventBursts(events, thresholds) {
+		// the allowance funcs are written against the total count, so give them the largest count we saw.
+		allowed, errs := d.isAllowed(allowedRepeatedEventsRegex, burst.event, burst.eventDisplayMessage, kubeClientConfig, burst.count)
+		for _, err := range errs {
+			messages = append(messages, fmt.Sprintf("error: [%v] when processing event %v", err, burst.eventDisplayMessage))
+		}
+		if allowed {
+			continue
+		}
+		bugs, _ := d.knownBugsFor(burst.eventDisplayMessage)
+		messages = append(messages, burst.String()+bugs)
+	}
+
+	tests := []*junitapi.JUnitTestCase{}
+	if len(messages) > 0 {
+		tests = append(tests, &junitapi.JUnitTestCase{
+			Name: testName,
+			FailureOutput: &junitapi.FailureOutput{
+				Output: fmt.Sprintf("%d events happened too frequently\n\n%v", len(messages), strings.Join(messages, "\n")),
+			},
+		})
+	}
+	// only flake for now
+	tests = append(tests, &junitapi.JUnitTestCase{Name: testName})
+	return tests
+}
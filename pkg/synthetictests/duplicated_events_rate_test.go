@@ -0,0 +1,98 @@
+package synthetictests
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func TestFindEventBursts(t *testing.T) {
+	start := time.Date(2022, time.July, 5, 17, 0, 0, 0, time.UTC)
+	repeatedEvent := func(locator, message string, times int, at time.Time) monitorapi.EventInterval {
+		return monitorapi.EventInterval{
+			Condition: monitorapi.Condition{Level: monitorapi.Warning, Locator: locator, Message: fmt.Sprintf("%s (%d times)", message, times)},
+			From:      at,
+			To:        at,
+		}
+	}
+	thresholds := eventRateThresholds{
+		window:           10 * time.Minute,
+		defaultThreshold: 20,
+		reasonThresholds: map[string]int{"ProbeError": 60},
+	}
+
+	events := monitorapi.Intervals{}
+	// slow and steady: 5 every 10 minutes for 2 hours is 60 total, but never more than 10 in a window.
+	for i := 1; i <= 12; i++ {
+		events = append(events, repeatedEvent("ns/openshift-foo pod/slow", "reason/BackOff Back-off restarting failed container", i*5, start.Add(time.Duration(i)*10*time.Minute)))
+	}
+	// a burst of 30 in 3 minutes, an hour into the run.
+	for i := 1; i <= 30; i++ {
+		events = append(events, repeatedEvent("ns/openshift-foo pod/burst", "reason/BackOff Back-off restarting failed container", i, start.Add(time.Hour+time.Duration(i)*6*time.Second)))
+	}
+	// 30 probe errors in 3 minutes is fine for probes.
+	for i := 1; i <= 30; i++ {
+		events = append(events, repeatedEvent("ns/openshift-foo pod/probe", "reason/ProbeError Readiness probe error", i, start.Add(time.Duration(i)*6*time.Second)))
+	}
+
+	bursts := findEventBursts(events, thresholds)
+	if len(bursts) != 1 {
+		t.Fatalf("expected a single burst: %s", spew.Sdump(bursts))
+	}
+	burst := bursts[0]
+	if burst.event.Locator != "ns/openshift-foo pod/burst" || burst.count != 30 {
+		t.Errorf("unexpected burst: %s", burst.String())
+	}
+	if !burst.from.Equal(start.Add(time.Hour+6*time.Second)) || !burst.to.Equal(start.Add(time.Hour+3*time.Minute)) {
+		t.Errorf("unexpected burst range: %s", burst.String())
+	}
+	if !strings.Contains(burst.String(), "between 2022-07-05T18:00:06Z and 2022-07-05T18:03:00Z (threshold 20 per 10m0s)") {
+		t.Errorf("unexpected burst message: %s", burst.String())
+	}
+}
+
+func TestFindEventBurstsFirstObservation(t *testing.T) {
+	start := time.Date(2022, time.July, 5, 17, 0, 0, 0, time.UTC)
+	events := monitorapi.Intervals{
+		{
+			// this happened 50 times before we started watching, so we do not know when.
+			Condition: monitorapi.Condition{Locator: "ns/openshift-foo pod/old", Message: "reason/BackOff Back-off restarting failed container (50 times)"},
+			From:      start,
+			To:        start,
+		},
+		{
+			Condition: monitorapi.Condition{Locator: "ns/openshift-foo pod/old", Message: "reason/BackOff Back-off restarting failed container (51 times)"},
+			From:      start.Add(time.Minute),
+			To:        start.Add(time.Minute),
+		},
+	}
+
+	if bursts := findEventBursts(events, defaultEventRateThresholds()); len(bursts) != 0 {
+		t.Fatalf("expected no bursts: %s", spew.Sdump(bursts))
+	}
+}
+
+func TestParseEventRateThresholds(t *testing.T) {
+	exceptions, err := parseDuplicatedEventExceptions([]byte("version: 1\nrateThresholds:\n  window: 5m\n  reasons:\n    BackOff: 30\nexceptions: []"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	thresholds := exceptions.rateThresholds
+	if thresholds.window != 5*time.Minute {
+		t.Errorf("expected a 5m window, got %v", thresholds.window)
+	}
+	if got := thresholds.thresholdFor("BackOff"); got != 30 {
+		t.Errorf("expected 30 for BackOff, got %d", got)
+	}
+	if got := thresholds.thresholdFor("Pulling"); got != duplicateEventThreshold {
+		t.Errorf("expected the default threshold for other reasons, got %d", got)
+	}
+
+	if got := defaultEventRateThresholds().thresholdFor("ProbeError"); got != 60 {
+		t.Errorf("expected the checked in threshold of 60 for ProbeError, got %d", got)
+	}
+}
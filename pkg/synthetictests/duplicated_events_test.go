@@ -2,6 +2,7 @@ package synthetictests
 
 import (
 	_ "embed"
+	"reflect"
 	"regexp"
 	"strings"
 	"testing"
@@ -95,7 +96,12 @@ func TestEventCountExtractor(t *testing.T) {
 }
 
 func TestEventRegexExcluder(t *testing.T) {
-	allowedRepeatedEventsRegex := combinedRegexp(allowedRepeatedEventPatterns...)
+	allowedPatterns := []*regexp.Regexp{}
+	allowedPatterns = append(allowedPatterns, allowedRepeatedEventPatterns...)
+	for _, kp := range defaultDuplicatedEventExceptions.allowed {
+		allowedPatterns = append(allowedPatterns, kp.Regexp)
+	}
+	allowedRepeatedEventsRegex := combinedRegexp(allowedPatterns...)
 
 	tests := []struct {
 		name    string
@@ -143,7 +149,12 @@ func TestEventRegexExcluder(t *testing.T) {
 }
 
 func TestUpgradeEventRegexExcluder(t *testing.T) {
-	allowedRepeatedEventsRegex := combinedRegexp(allowedUpgradeRepeatedEventPatterns...)
+	allowedPatterns := []*regexp.Regexp{}
+	allowedPatterns = append(allowedPatterns, allowedUpgradeRepeatedEventPatterns...)
+	for _, kp := range defaultDuplicatedEventExceptions.allowedDuringUpgrade {
+		allowedPatterns = append(allowedPatterns, kp.Regexp)
+	}
+	allowedRepeatedEventsRegex := combinedRegexp(allowedPatterns...)
 
 	tests := []struct {
 		name    string
@@ -283,3 +294,146 @@ func TestKnownBugEvents(t *testing.T) {
 		})
 	}
 }
+
+const testDuplicatedEventExceptionsYAML = `
+version: 1
+exceptions:
+- pattern: 'ns/e2e-.* reason/SomeEvent1.*'
+  kind: Allowed
+  owner: sig-apps
+- pattern: 'ns/.* reason/SomeEvent2.*'
+  kind: KnownBug
+  bugURL: https://bugzilla.redhat.com/show_bug.cgi?id=1234567
+  owner: sig-node
+  expiresInRelease: "4.11"
+- pattern: 'ns/.* reason/SomeEvent3.*'
+  kind: KnownBug
+  bugURL: https://bugzilla.redhat.com/show_bug.cgi?id=7654321
+  owner: sig-network
+- pattern: 'ns/.* reason/SomeEvent4.*'
+  kind: KnownBug
+  bugURL: https://bugzilla.redhat.com/show_bug.cgi?id=7654321
+  scope:
+    platform: GCP
+`
+
+func TestDuplicatedEventExceptions(t *testing.T) {
+	exceptions, err := parseDuplicatedEventExceptions([]byte(testDuplicatedEventExceptionsYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	evaluator := duplicateEventsEvaluator{
+		allowedRepeatedEventPatterns:   allowedRepeatedEventPatterns,
+		allowedRepeatedEventExceptions: exceptions.allowed,
+		knownRepeatedEventsBugs:        exceptions.knownBugs,
+		platform:                       v1.AWSPlatformType,
+		release:                        "4.12.0-0.nightly-2022-07-05-083948",
+	}
+	events := monitorapi.Intervals{
+		{
+			Condition: monitorapi.Condition{Locator: "ns/e2e-foo", Message: "reason/SomeEvent1 foo (21 times)"},
+			From:      time.Unix(1, 0),
+			To:        time.Unix(1, 0),
+		},
+		{
+			Condition: monitorapi.Condition{Locator: "ns/openshift-foo", Message: "reason/SomeEvent2 foo (21 times)"},
+			From:      time.Unix(1, 0),
+			To:        time.Unix(1, 0),
+		},
+	}
+
+	junits := evaluator.testDuplicatedEvents("events should not repeat", false, events, nil)
+	if junits[0].FailureOutput == nil || !strings.Contains(junits[0].FailureOutput.Output, "1 events with known BZs") || strings.Contains(junits[0].FailureOutput.Output, "too frequently") {
+		t.Errorf("expected only the known bug to flake: %s", spew.Sdump(junits))
+	}
+
+	junits = evaluator.testDuplicatedEventExceptions(events)
+	if len(junits) != 2 || junits[0].FailureOutput == nil || junits[1].FailureOutput != nil {
+		t.Fatalf("expected a flake: %s", spew.Sdump(junits))
+	}
+	output := junits[0].FailureOutput.Output
+	if !strings.Contains(output, "2 exceptions have expired or did not match in 4.12.0-0.nightly-2022-07-05-083948") {
+		t.Errorf("expected two stale exceptions: %s", output)
+	}
+	if !strings.Contains(output, "expired in 4.11") || !strings.Contains(output, "SomeEvent2") {
+		t.Errorf("expected SomeEvent2 to be expired: %s", output)
+	}
+	if !strings.Contains(output, "did not match any repeated event") || !strings.Contains(output, "SomeEvent3") {
+		t.Errorf("expected SomeEvent3 to be unmatched: %s", output)
+	}
+	if strings.Contains(output, "SomeEvent1") || strings.Contains(output, "SomeEvent4") {
+		t.Errorf("expected matched and out of scope exceptions not to be reported: %s", output)
+	}
+
+	// without the expiration only the unmatched exception flakes
+	evaluator.release = "4.10.0"
+	junits = evaluator.testDuplicatedEventExceptions(events)
+	if len(junits) != 2 || junits[0].FailureOutput == nil || junits[1].FailureOutput != nil {
+		t.Fatalf("expected a flake: %s", spew.Sdump(junits))
+	}
+	if output := junits[0].FailureOutput.Output; !strings.Contains(output, "1 exceptions have expired or did not match") || strings.Contains(output, "SomeEvent2") {
+		t.Errorf("expected only SomeEvent3 to be reported: %s", output)
+	}
+	usage, err := duplicatedEventExceptionUsageFor([]byte(testDuplicatedEventExceptionsYAML), repeatedEventDisplayMessages(events))
+	if err != nil {
+		t.Fatal(err)
+	}
+	matchedEvents := map[string]int{}
+	for _, curr := range usage {
+		matchedEvents[curr.Pattern] = curr.MatchedEvents
+	}
+	wantMatchedEvents := map[string]int{
+		"ns/e2e-.* reason/SomeEvent1.*": 1,
+		"ns/.* reason/SomeEvent2.*":     1,
+		"ns/.* reason/SomeEvent3.*":     0,
+		"ns/.* reason/SomeEvent4.*":     0,
+	}
+	if !reflect.DeepEqual(matchedEvents, wantMatchedEvents) {
+		t.Errorf("expected %v, got %v", wantMatchedEvents, matchedEvents)
+	}
+}
+
+func TestParseDuplicatedEventExceptionsErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{
+			name:  "unknown version",
+			input: "version: 2\nexceptions: []",
+		},
+		{
+			name:  "bad pattern",
+			input: "version: 1\nexceptions:\n- pattern: '('\n  kind: Allowed",
+		},
+		{
+			name:  "bad kind",
+			input: "version: 1\nexceptions:\n- pattern: 'foo'\n  kind: Sometimes",
+		},
+		{
+			name:  "bad release",
+			input: "version: 1\nexceptions:\n- pattern: 'foo'\n  kind: Allowed\n  expiresInRelease: soon",
+		},
+		{
+			name:  "unknown field",
+			input: "version: 1\nexceptions:\n- pattern: 'foo'\n  kind: Allowed\n  bz: https://bugzilla.redhat.com/show_bug.cgi?id=1234567",
+		},
+		{
+			name:  "bad rate window",
+			input: "version: 1\nrateThresholds:\n  window: often\nexceptions: []",
+		},
+		{
+			name:  "zero rate threshold",
+			input: "version: 1\nrateThresholds:\n  reasons:\n    BackOff: 0\nexceptions: []",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := parseDuplicatedEventExceptions([]byte(test.input)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -46,6 +46,14 @@ func StableSystemEventInvariants(events monitorapi.Intervals, duration time.Dura
 	tests = append(tests, testBackoffStartingFailedContainerForE2ENamespaces(events)...)
 	tests = append(tests, testAPIQuotaEvents(events)...)
 	tests = append(tests, testErrorUpdatingEndpointSlices(events)...)
+	tests = append(tests, testHotResources(recordedResource, duration)...)
+	tests = append(tests, testContainerRestartBudget(events, recordedResource, defaultContainerRestartBudgets)...)
+	tests = append(tests, testLeaderElectionChurn(events, defaultLeaderElectionAllowances)...)
+	tests = append(tests, testCertificateRotation(events)...)
+	tests = append(tests, testAuditLogRequests(events)...)
+	tests = append(tests, testControlPlaneNodePressure(events)...)
+	tests = append(tests, testPodsEvictedDuringNodePressure(events)...)
+	tests = append(tests, testPodStartupSLOs(events, defaultPodStartupSLOs)...)
 
 	return tests
 }
@@ -80,6 +88,13 @@ func SystemUpgradeEventInvariants(events monitorapi.Intervals, duration time.Dur
 	tests = append(tests, testBackoffStartingFailedContainerForE2ENamespaces(events)...)
 	tests = append(tests, testAPIQuotaEvents(events)...)
 	tests = append(tests, testErrorUpdatingEndpointSlices(events)...)
+	tests = append(tests, testHotResources(recordedResource, duration)...)
+	tests = append(tests, testContainerRestartBudget(events, recordedResource, defaultContainerRestartBudgets)...)
+	tests = append(tests, testLeaderElectionChurn(events, defaultLeaderElectionAllowances)...)
+	tests = append(tests, testCertificateRotation(events)...)
+	tests = append(tests, testAuditLogRequests(events)...)
+	tests = append(tests, testPodsEvictedDuringNodePressure(events)...)
+	tests = append(tests, testPodDisruptionBudgetViolations(events, recordedResource)...)
 
 	tests = append(tests, testAllAPIBackendsForDisruption(events, duration, kubeClientConfig)...)
 	tests = append(tests, testAllIngressBackendsForDisruption(events, duration, kubeClientConfig)...)
@@ -0,0 +1,296 @@
+package historicaldata_cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/openshift/origin/pkg/monitor"
+	"github.com/openshift/origin/pkg/synthetictests/allowedalerts"
+	"github.com/openshift/origin/pkg/synthetictests/allowedbackenddisruption"
+	"github.com/openshift/origin/pkg/synthetictests/historicaldata"
+	"github.com/openshift/origin/pkg/synthetictests/platformidentification"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+const (
+	alertsFilePrefix            = "alerts"
+	backendDisruptionFilePrefix = "backend-disruption"
+)
+
+type BuildOptions struct {
+	ArtifactDirs []string
+	OutputDir    string
+
+	IOStreams genericclioptions.IOStreams
+}
+
+func NewBuildOptions(ioStreams genericclioptions.IOStreams) *BuildOptions {
+	return &BuildOptions{
+		IOStreams: ioStreams,
+	}
+}
+
+func NewHistoricalDataCommand(ioStreams genericclioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "historical-data",
+		Short: "Work with the historical data used to set the allowances of tests",
+	}
+	cmd.AddCommand(NewBuildCommand(ioStreams))
+	return cmd
+}
+
+func NewBuildCommand(ioStreams genericclioptions.IOStreams) *cobra.Command {
+	o := NewBuildOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:   "build --output-dir=DIR ARTIFACT_DIR...",
+		Short: "Build historical data from the artifacts of past runs",
+		Long: `
+		Build the P95 and P99 of alert durations and backend disruption per job type from the artifacts of past runs.
+
+		Every artifact directory is searched for the job-type, alerts and backend-disruption files written at the end
+		of a run.  Runs without a job type are skipped.  The results are written as alerts.json and disruptions.json,
+		so the output directory can be passed to --historical-data-dir.
+
+		openshift-tests historical-data build --output-dir=historical-data artifacts/run-1 artifacts/run-2
+		`,
+
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.ArtifactDirs = args
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			return o.Run()
+		},
+	}
+
+	o.Bind(cmd.Flags())
+
+	return cmd
+}
+
+func (o *BuildOptions) Bind(flagset *pflag.FlagSet) {
+	flagset.StringVar(&o.OutputDir, "output-dir", o.OutputDir, "The directory to write the historical data to.")
+}
+
+func (o *BuildOptions) Validate() error {
+	if len(o.ArtifactDirs) == 0 {
+		return fmt.Errorf("at least one artifact directory is required")
+	}
+	if len(o.OutputDir) == 0 {
+		return fmt.Errorf("missing --output-dir")
+	}
+	return nil
+}
+
+func (o *BuildOptions) Run() error {
+	jobRuns, err := findJobRuns(o.ArtifactDirs, o.IOStreams.ErrOut)
+	if err != nil {
+		return err
+	}
+	if len(jobRuns) == 0 {
+		return fmt.Errorf("no runs with a job type were found in %v", strings.Join(o.ArtifactDirs, ", "))
+	}
+
+	if err := os.MkdirAll(o.OutputDir, 0755); err != nil {
+		return err
+	}
+	dataTypeToSeconds := map[string]func(jobRun) map[string]float64{
+		allowedalerts.HistoricalDataType:            func(run jobRun) map[string]float64 { return run.alertSeconds },
+		allowedbackenddisruption.HistoricalDataType: func(run jobRun) map[string]float64 { return run.disruptionSeconds },
+	}
+	for _, dataType := range []string{allowedalerts.HistoricalDataType, allowedbackenddisruption.HistoricalDataType} {
+		data := percentilesFor(jobRuns, dataTypeToSeconds[dataType])
+		jsonContent, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			return err
+		}
+		filename := filepath.Join(o.OutputDir, dataType+".json")
+		if err := ioutil.WriteFile(filename, jsonContent, 0644); err != nil {
+			return err
+		}
+		fmt.Fprintf(o.IOStreams.Out, "Wrote %d %s entries from %d runs to %v\n", len(data), dataType, len(jobRuns), filename)
+	}
+	return nil
+}
+
+// jobRun is the data of a single run found in the artifacts.  The data is nil when the run did not write it.
+type jobRun struct {
+	jobType platformidentification.JobType
+
+	// alertSeconds is how long each alert was at or above warning, summed over namespaces.
+	alertSeconds map[string]float64
+	// disruptionSeconds is how long each backend was disrupted, keyed like <backend>-<new|reused>-connections.
+	disruptionSeconds map[string]float64
+}
+
+// findJobRuns finds the runs in the artifact directories.  The files of a run share a directory and a time suffix,
+// like job-type_20220705-170000.json and alerts_20220705-170000.json.
+func findJobRuns(artifactDirs []string, errOut io.Writer) ([]jobRun, error) {
+	jobRuns := []jobRun{}
+	skipped := 0
+	for _, artifactDir := range artifactDirs {
+		err := filepath.WalkDir(artifactDir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || filepath.Ext(d.Name()) != ".json" {
+				return nil
+			}
+			if strings.HasPrefix(d.Name(), alertsFilePrefix+"_") {
+				suffix := strings.TrimPrefix(d.Name(), alertsFilePrefix)
+				if _, err := os.Stat(filepath.Join(filepath.Dir(path), platformidentification.JobTypeFilePrefix+suffix)); err != nil {
+					skipped++
+				}
+				return nil
+			}
+			if !strings.HasPrefix(d.Name(), platformidentification.JobTypeFilePrefix+"_") {
+				return nil
+			}
+
+			suffix := strings.TrimPrefix(d.Name(), platformidentification.JobTypeFilePrefix)
+			jobType, err := platformidentification.ReadJobType(path)
+			if err != nil {
+				return err
+			}
+			run := jobRun{jobType: *jobType}
+			if run.alertSeconds, err = readAlertSeconds(filepath.Join(filepath.Dir(path), alertsFilePrefix+suffix)); err != nil {
+				return err
+			}
+			if run.disruptionSeconds, err = readDisruptionSeconds(filepath.Join(filepath.Dir(path), backendDisruptionFilePrefix+suffix)); err != nil {
+				return err
+			}
+			jobRuns = append(jobRuns, run)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if skipped > 0 {
+		fmt.Fprintf(errOut, "Skipped %d runs without a job type\n", skipped)
+	}
+	return jobRuns, nil
+}
+
+// readAlertSeconds returns nil when the run did not write the file.
+func readAlertSeconds(filename string) (map[string]float64, error) {
+	content, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	alertList := &allowedalerts.AlertList{}
+	if err := json.Unmarshal(content, alertList); err != nil {
+		return nil, fmt.Errorf("unable to read alerts from %v: %w", filename, err)
+	}
+	ret := map[string]float64{}
+	for _, alert := range alertList.Alerts {
+		ret[alert.Name] += alert.Duration.Seconds()
+	}
+	return ret, nil
+}
+
+// readDisruptionSeconds returns nil when the run did not write the file.
+func readDisruptionSeconds(filename string) (map[string]float64, error) {
+	content, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	disruptionList := &monitor.BackendDisruptionList{}
+	if err := json.Unmarshal(content, disruptionList); err != nil {
+		return nil, fmt.Errorf("unable to read backend disruption from %v: %w", filename, err)
+	}
+	ret := map[string]float64{}
+	for name, disruption := range disruptionList.BackendDisruptions {
+		ret[name] = disruption.DisruptedDuration.Seconds()
+	}
+	return ret, nil
+}
+
+// historicalDataJSON is the format of the embedded query_results.json, using Name for the name.
+type historicalDataJSON struct {
+	historicaldata.DataKey `json:",inline"`
+	P50                    string
+	P75                    string
+	P90                    string
+	P95                    string
+	P99                    string
+	Max                    string
+	JobRuns                string
+}
+
+// percentilesFor calculates the P95 and P99 of every name for every job type.  Runs only write the backends that
+// were disrupted, so a run of the job type that has data but not for a name counts as zero seconds for that name.
+func percentilesFor(jobRuns []jobRun, secondsOf func(jobRun) map[string]float64) []historicalDataJSON {
+	jobTypeToRuns := map[platformidentification.JobType][]map[string]float64{}
+	for _, run := range jobRuns {
+		if seconds := secondsOf(run); seconds != nil {
+			jobTypeToRuns[run.jobType] = append(jobTypeToRuns[run.jobType], seconds)
+		}
+	}
+
+	ret := []historicalDataJSON{}
+	for jobType, runs := range jobTypeToRuns {
+		names := map[string]bool{}
+		for _, run := range runs {
+			for name := range run {
+				names[name] = true
+			}
+		}
+		for name := range names {
+			samples := []float64{}
+			for _, run := range runs {
+				samples = append(samples, run[name])
+			}
+			sort.Float64s(samples)
+			percentile := func(percentile float64) string {
+				return strconv.FormatFloat(percentileCont(samples, percentile), 'f', -1, 64)
+			}
+			ret = append(ret, historicalDataJSON{
+				DataKey: historicaldata.DataKey{Name: name, JobType: jobType},
+				P50:     percentile(0.50),
+				P75:     percentile(0.75),
+				P90:     percentile(0.90),
+				P95:     percentile(0.95),
+				P99:     percentile(0.99),
+				Max:     percentile(1),
+				JobRuns: strconv.Itoa(len(samples)),
+			})
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return fmt.Sprintf("%v", ret[i].DataKey) < fmt.Sprintf("%v", ret[j].DataKey)
+	})
+	return ret
+}
+
+// percentileCont interpolates between the closest samples like PERCENTILE_CONT in BigQuery, which produced the
+// embedded data.  The samples must be sorted.
+func percentileCont(samples []float64, percentile float64) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	rank := percentile * float64(len(samples)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return samples[lower] + (samples[upper]-samples[lower])*(rank-float64(lower))
+}
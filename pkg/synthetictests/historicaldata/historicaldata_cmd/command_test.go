@@ -0,0 +1,104 @@
+package historicaldata_cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/openshift/origin/pkg/synthetictests/historicaldata"
+	"github.com/openshift/origin/pkg/synthetictests/platformidentification"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+func Test_percentileCont(t *testing.T) {
+	samples := []float64{}
+	for i := 1; i <= 101; i++ {
+		samples = append(samples, float64(i))
+	}
+	if got := percentileCont(samples, 0.95); got != 96 {
+		t.Errorf("expected 96, got %v", got)
+	}
+	if got := percentileCont([]float64{0, 10}, 0.95); got != 9.5 {
+		t.Errorf("expected 9.5, got %v", got)
+	}
+	if got := percentileCont(nil, 0.95); got != 0 {
+		t.Errorf("expected 0, got %v", got)
+	}
+}
+
+func TestBuildOptions_Run(t *testing.T) {
+	artifactDir, err := ioutil.TempDir("", "artifacts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(artifactDir)
+
+	writeFile := func(path, content string) {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	jobType := `{"Release": "4.12", "FromRelease": "4.11", "Platform": "metal", "Architecture": "amd64", "Network": "ovn", "Topology": "ha"}`
+	writeFile(filepath.Join(artifactDir, "run-1", "job-type_20220705-170000.json"), jobType)
+	writeFile(filepath.Join(artifactDir, "run-1", "alerts_20220705-170000.json"),
+		`{"Alerts": [{"Name": "etcdNoLeader", "Namespace": "openshift-etcd", "Level": "Warning", "Duration": "10s"}]}`)
+	writeFile(filepath.Join(artifactDir, "run-1", "backend-disruption_20220705-170000.json"),
+		`{"BackendDisruptions": {"kube-api-new-connections": {"Name": "kube-api-new-connections", "DisruptedDuration": "4s"}}}`)
+	writeFile(filepath.Join(artifactDir, "run-2", "job-type_20220706-170000.json"), jobType)
+	writeFile(filepath.Join(artifactDir, "run-2", "alerts_20220706-170000.json"),
+		`{"Alerts": [{"Name": "etcdNoLeader", "Namespace": "openshift-etcd", "Level": "Warning", "Duration": "0s"}]}`)
+	writeFile(filepath.Join(artifactDir, "run-2", "backend-disruption_20220706-170000.json"), `{"BackendDisruptions": {}}`)
+	// no job type, so this run is skipped
+	writeFile(filepath.Join(artifactDir, "run-3", "alerts_20220707-170000.json"),
+		`{"Alerts": [{"Name": "etcdNoLeader", "Namespace": "openshift-etcd", "Level": "Warning", "Duration": "1h"}]}`)
+
+	outputDir := filepath.Join(artifactDir, "historical-data")
+	errOut := &bytes.Buffer{}
+	o := NewBuildOptions(genericclioptions.IOStreams{Out: &bytes.Buffer{}, ErrOut: errOut})
+	o.ArtifactDirs = []string{artifactDir}
+	o.OutputDir = outputDir
+	if err := o.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if errOut.String() != "Skipped 1 runs without a job type\n" {
+		t.Errorf("expected the run without a job type to be skipped, got %q", errOut.String())
+	}
+
+	metal := platformidentification.JobType{Release: "4.12", FromRelease: "4.11", Platform: "metal", Architecture: "amd64", Network: "ovn", Topology: "ha"}
+	for _, tt := range []struct {
+		dataType string
+		name     string
+		wantP95  float64
+		wantMax  float64
+	}{
+		{dataType: "alerts", name: "etcdNoLeader", wantP95: 9.5, wantMax: 10},
+		{dataType: "disruptions", name: "kube-api-new-connections", wantP95: 3.8, wantMax: 4},
+	} {
+		content, err := ioutil.ReadFile(filepath.Join(outputDir, tt.dataType+".json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		matcher, err := historicaldata.NewMatcher(content, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, details, err := matcher.BestMatch(tt.name, metal)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(details) > 0 || data.P95 != tt.wantP95 {
+			t.Errorf("expected %v p95 for %v, got %v %v", tt.wantP95, tt.name, data.P95, details)
+		}
+		if data.JobRuns != 2 {
+			t.Errorf("expected 2 job runs for %v, got %v", tt.name, data.JobRuns)
+		}
+		if max, ok := data.Percentile(historicaldata.PercentileMax); !ok || max != tt.wantMax {
+			t.Errorf("expected %v max for %v, got %v", tt.wantMax, tt.name, max)
+		}
+	}
+}
@@ -2,190 +2,174 @@ package historicaldata
 
 import (
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 
 	"github.com/openshift/origin/pkg/synthetictests/platformidentification"
 )
 
-// nextBestGuessers is the order in which to attempt to lookup other alternative matches that are close to this job type.
-// TODO building a cross multiply would likely be beneficial
-var nextBestGuessers = []NextBestKey{
-	MicroReleaseUpgrade,
-	MinorReleaseUpgrade,
-	PreviousReleaseUpgrade,
-	combine(PreviousReleaseUpgrade, MicroReleaseUpgrade),
-	combine(PreviousReleaseUpgrade, MinorReleaseUpgrade),
-
-	OnArchitecture("amd64"),
-	OnArchitecture("ppc64le"),
-	OnArchitecture("s390x"),
-	OnArchitecture("arm64"),
-
-	combine(OnArchitecture("amd64"), MicroReleaseUpgrade),
-	combine(OnArchitecture("ppc64le"), MicroReleaseUpgrade),
-	combine(OnArchitecture("s390x"), MicroReleaseUpgrade),
-	combine(OnArchitecture("arm64"), MicroReleaseUpgrade),
-
-	combine(OnArchitecture("amd64"), MinorReleaseUpgrade),
-	combine(OnArchitecture("ppc64le"), MinorReleaseUpgrade),
-	combine(OnArchitecture("s390x"), MinorReleaseUpgrade),
-	combine(OnArchitecture("arm64"), MinorReleaseUpgrade),
-
-	combine(OnArchitecture("amd64"), PreviousReleaseUpgrade),
-	combine(OnArchitecture("ppc64le"), PreviousReleaseUpgrade),
-	combine(OnArchitecture("s390x"), PreviousReleaseUpgrade),
-	combine(OnArchitecture("arm64"), PreviousReleaseUpgrade),
-
-	combine(OnArchitecture("amd64"), PreviousReleaseUpgrade, MicroReleaseUpgrade),
-	combine(OnArchitecture("ppc64le"), PreviousReleaseUpgrade, MicroReleaseUpgrade),
-	combine(OnArchitecture("s390x"), PreviousReleaseUpgrade, MicroReleaseUpgrade),
-	combine(OnArchitecture("arm64"), PreviousReleaseUpgrade, MicroReleaseUpgrade),
-
-	combine(OnArchitecture("amd64"), PreviousReleaseUpgrade, MinorReleaseUpgrade),
-	combine(OnArchitecture("ppc64le"), PreviousReleaseUpgrade, MinorReleaseUpgrade),
-	combine(OnArchitecture("s390x"), PreviousReleaseUpgrade, MinorReleaseUpgrade),
-	combine(OnArchitecture("arm64"), PreviousReleaseUpgrade, MinorReleaseUpgrade),
-
-	combine(ForTopology("single"), OnSDN),
-	combine(ForTopology("single"), OnSDN, PreviousReleaseUpgrade),
-	combine(ForTopology("single"), OnSDN, PreviousReleaseUpgrade, MicroReleaseUpgrade),
+// JobTypeDistanceFunc returns how far the candidate job type is from the wanted job type, along with the differences
+// that make up the distance.  ok is false when data for the candidate must never be used for the wanted job type.
+// The closest candidate with data is used when there is no data for the wanted job type.
+type JobTypeDistanceFunc func(wanted, candidate platformidentification.JobType) (distance float64, differences []string, ok bool)
+
+// JobTypeWeights are the cost of each difference between job types.  A weight of math.Inf(1) means that job types may
+// not differ on that dimension at all.
+type JobTypeWeights struct {
+	// PreviousRelease is the cost of every minor release the candidate is older than the wanted release.  Newer
+	// releases are never used, since a regression on the wanted release would then hide behind the fix it is missing.
+	PreviousRelease float64
+	// UpgradeType is the cost of using a micro upgrade for a minor upgrade, or the other way around.  Data with an
+	// upgrade is never used for a job without an upgrade, or the other way around.
+	UpgradeType float64
+
+	Architecture float64
+	Platform     float64
+	Network      float64
+	Topology     float64
+
+	// The optional dimensions only cost their weight when the candidate has a value for them.  Data without them
+	// applies to every value.
+	IPFamily      float64
+	Proxy         float64
+	FIPS          float64
+	InstallerType float64
 }
 
-// NextBestKey returns the next best key in the query_results.json generated from BigQuery and a bool indicating whether this guesser has an opinion.
-// If the bool is false, the key should not be used.
-// Returning true doesn't mean the key exists, it just means that the key is worth trying.
-type NextBestKey func(in platformidentification.JobType) (platformidentification.JobType, bool)
+// DefaultJobTypeWeights prefer the same cluster on an older release over a different upgrade type, over a different
+// architecture, over a different network.  Platforms and topologies behave too differently to compare.  The optional
+// dimensions are cheaper than the network, except for the IP family, which changes the network as much.
+var DefaultJobTypeWeights = JobTypeWeights{
+	UpgradeType:     1,
+	PreviousRelease: 2,
+	Architecture:    4,
+	Network:         8,
+	Platform:        math.Inf(1),
+	Topology:        math.Inf(1),
+
+	FIPS:          2,
+	Proxy:         4,
+	InstallerType: 4,
+	IPFamily:      8,
+}
 
-// MinorReleaseUpgrade if we don't have data for the current fromRelease and it's a micro upgrade, perhaps we have data
-// for a minor upgrade.  A 4.11 to 4.11 upgrade will attempt a 4.10 to 4.11 upgrade.
-func MinorReleaseUpgrade(in platformidentification.JobType) (platformidentification.JobType, bool) {
-	if len(in.FromRelease) == 0 {
-		return platformidentification.JobType{}, false
-	}
+// DefaultJobTypeDistance uses the DefaultJobTypeWeights.
+var DefaultJobTypeDistance = DefaultJobTypeWeights.Distance
 
-	fromReleaseMinor := getMinor(in.FromRelease)
-	toReleaseMajor := getMajor(in.Release)
-	toReleaseMinor := getMinor(in.Release)
-	// if we're already a minor upgrade, this doesn't apply
-	if fromReleaseMinor == (toReleaseMinor - 1) {
-		return platformidentification.JobType{}, false
+// Distance sums the weights of every difference between the job types.
+func (w JobTypeWeights) Distance(wanted, candidate platformidentification.JobType) (float64, []string, bool) {
+	distance := 0.0
+	var differences []string
+	add := func(dimension string, weight float64, from, to string) bool {
+		if math.IsInf(weight, 1) {
+			return false
+		}
+		distance += weight
+		differences = append(differences, fmt.Sprintf("%s %s->%s", dimension, from, to))
+		return true
 	}
 
-	ret := platformidentification.CloneJobType(in)
-	ret.FromRelease = fmt.Sprintf("%d.%d", toReleaseMajor, toReleaseMinor-1)
-	return ret, true
-}
-
-// MicroReleaseUpgrade if we don't have data for the current fromRelease and it's a minor upgrade, perhaps we have data
-// for a micro upgrade.  A 4.10 to 4.11 upgrade will attempt a 4.11 to 4.11 upgrade.
-func MicroReleaseUpgrade(in platformidentification.JobType) (platformidentification.JobType, bool) {
-	if len(in.FromRelease) == 0 {
-		return platformidentification.JobType{}, false
+	if wanted.Release != candidate.Release {
+		releasesBack, ok := minorReleasesBetween(candidate.Release, wanted.Release)
+		if !ok || releasesBack < 0 {
+			return 0, nil, false
+		}
+		if releasesBack > 0 && !add("release", w.PreviousRelease*float64(releasesBack), wanted.Release, candidate.Release) {
+			return 0, nil, false
+		}
 	}
-
-	fromReleaseMinor := getMinor(in.FromRelease)
-	toReleaseMajor := getMajor(in.Release)
-	toReleaseMinor := getMinor(in.Release)
-	// if we're already a micro upgrade, this doesn't apply
-	if fromReleaseMinor == toReleaseMinor {
-		return platformidentification.JobType{}, false
+	wantedUpgrade, wantedOK := upgradeType(wanted)
+	candidateUpgrade, candidateOK := upgradeType(candidate)
+	switch {
+	case !wantedOK || !candidateOK:
+		if wanted.FromRelease != candidate.FromRelease {
+			return 0, nil, false
+		}
+	case wantedUpgrade == candidateUpgrade:
+	case len(wanted.FromRelease) == 0 || len(candidate.FromRelease) == 0:
+		return 0, nil, false
+	case !add("upgrade", w.UpgradeType, wantedUpgrade, candidateUpgrade):
+		return 0, nil, false
 	}
 
-	ret := platformidentification.CloneJobType(in)
-	ret.FromRelease = fmt.Sprintf("%d.%d", toReleaseMajor, toReleaseMinor)
-	return ret, true
-}
-
-// PreviousReleaseUpgrade if we don't have data for the current toRelease, perhaps we have data for the congruent test
-// on the prior release.   A 4.11 to 4.11 upgrade will attempt a 4.10 to 4.10 upgrade.  A 4.11 no upgrade, will attempt a 4.10 no upgrade.
-func PreviousReleaseUpgrade(in platformidentification.JobType) (platformidentification.JobType, bool) {
-	toReleaseMajor := getMajor(in.Release)
-	toReleaseMinor := getMinor(in.Release)
-
-	ret := platformidentification.CloneJobType(in)
-	ret.Release = fmt.Sprintf("%d.%d", toReleaseMajor, toReleaseMinor-1)
-	if len(in.FromRelease) > 0 {
-		fromReleaseMinor := getMinor(in.FromRelease)
-		ret.FromRelease = fmt.Sprintf("%d.%d", toReleaseMajor, fromReleaseMinor-1)
+	for _, dimension := range []struct {
+		name           string
+		weight         float64
+		wanted, actual string
+	}{
+		{name: "architecture", weight: w.Architecture, wanted: wanted.Architecture, actual: candidate.Architecture},
+		{name: "platform", weight: w.Platform, wanted: wanted.Platform, actual: candidate.Platform},
+		{name: "network", weight: w.Network, wanted: wanted.Network, actual: candidate.Network},
+		{name: "topology", weight: w.Topology, wanted: wanted.Topology, actual: candidate.Topology},
+	} {
+		if dimension.wanted == dimension.actual {
+			continue
+		}
+		if !add(dimension.name, dimension.weight, dimension.wanted, dimension.actual) {
+			return 0, nil, false
+		}
 	}
-	return ret, true
-}
 
-func getMajor(in string) int {
-	major, err := strconv.ParseInt(strings.Split(in, ".")[0], 10, 32)
-	if err != nil {
-		panic(err)
+	for _, dimension := range []struct {
+		name           string
+		weight         float64
+		wanted, actual string
+	}{
+		{name: "ip family", weight: w.IPFamily, wanted: wanted.IPFamily, actual: candidate.IPFamily},
+		{name: "proxy", weight: w.Proxy, wanted: wanted.Proxy, actual: candidate.Proxy},
+		{name: "fips", weight: w.FIPS, wanted: wanted.FIPS, actual: candidate.FIPS},
+		{name: "installer type", weight: w.InstallerType, wanted: wanted.InstallerType, actual: candidate.InstallerType},
+	} {
+		if len(dimension.actual) == 0 || dimension.wanted == dimension.actual {
+			continue
+		}
+		if !add(dimension.name, dimension.weight, dimension.wanted, dimension.actual) {
+			return 0, nil, false
+		}
 	}
-	return int(major)
+	return distance, differences, true
 }
 
-func getMinor(in string) int {
-	minor, err := strconv.ParseInt(strings.Split(in, ".")[1], 10, 32)
-	if err != nil {
-		panic(err)
+// upgradeType returns micro, minor or none.  It is not ok when the releases cannot be parsed.
+func upgradeType(jobType platformidentification.JobType) (string, bool) {
+	if len(jobType.FromRelease) == 0 {
+		return "none", true
 	}
-	return int(minor)
-}
-
-// OnOVN maybe we have data on OVN
-func OnOVN(in platformidentification.JobType) (platformidentification.JobType, bool) {
-	if in.Network == "ovn" {
-		return platformidentification.JobType{}, false
+	releasesBack, ok := minorReleasesBetween(jobType.FromRelease, jobType.Release)
+	switch {
+	case !ok:
+		return "", false
+	case releasesBack == 0:
+		return "micro", true
+	default:
+		return "minor", true
 	}
-
-	ret := platformidentification.CloneJobType(in)
-	ret.Network = "ovn"
-	return ret, true
 }
 
-// OnSDN maybe we have data on SDN
-func OnSDN(in platformidentification.JobType) (platformidentification.JobType, bool) {
-	if in.Network == "sdn" {
-		return platformidentification.JobType{}, false
+// minorReleasesBetween returns how many minor releases from is older than to, like 1 for 4.10 and 4.11.  Releases of
+// different major versions cannot be compared.
+func minorReleasesBetween(from, to string) (int, bool) {
+	fromMajor, fromMinor, fromOK := parseRelease(from)
+	toMajor, toMinor, toOK := parseRelease(to)
+	if !fromOK || !toOK || fromMajor != toMajor {
+		return 0, false
 	}
-
-	ret := platformidentification.CloneJobType(in)
-	ret.Network = "sdn"
-	return ret, true
+	return toMinor - fromMinor, true
 }
 
-// OnArchitecture maybe we match a different architecture
-func OnArchitecture(architecture string) func(in platformidentification.JobType) (platformidentification.JobType, bool) {
-	return func(in platformidentification.JobType) (platformidentification.JobType, bool) {
-		if in.Architecture == architecture {
-			return platformidentification.JobType{}, false
-		}
-
-		ret := platformidentification.CloneJobType(in)
-		ret.Architecture = architecture
-		return ret, true
+func parseRelease(release string) (int, int, bool) {
+	parts := strings.Split(release, ".")
+	if len(parts) < 2 {
+		return 0, 0, false
 	}
-}
-
-// ForTopology we match on exact topology
-func ForTopology(topology string) func(in platformidentification.JobType) (platformidentification.JobType, bool) {
-	return func(in platformidentification.JobType) (platformidentification.JobType, bool) {
-		if in.Topology != topology {
-			return platformidentification.JobType{}, false
-		}
-		return in, true
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, false
 	}
-}
-
-// combine will start with the input and call each guess in order.  It uses the output of the previous NextBestKeyFn
-// as the input to the next.  This allows combinations like "previous release upgrade micro" without writing custom
-// functions for each.
-func combine(nextBestKeys ...NextBestKey) NextBestKey {
-	return func(in platformidentification.JobType) (platformidentification.JobType, bool) {
-		curr := in
-		for _, nextBestKey := range nextBestKeys {
-			var ok bool
-			curr, ok = nextBestKey(curr)
-			if !ok {
-				return curr, false
-			}
-		}
-		return curr, true
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, false
 	}
+	return major, minor, true
 }
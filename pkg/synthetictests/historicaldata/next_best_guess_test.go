@@ -0,0 +1,200 @@
+package historicaldata
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/openshift/origin/pkg/synthetictests/platformidentification"
+)
+
+func TestJobTypeWeights_Distance(t *testing.T) {
+	wanted := platformidentification.JobType{Release: "4.12", FromRelease: "4.11", Platform: "aws", Architecture: "amd64", Network: "ovn", Topology: "ha"}
+	with := func(mutate func(*platformidentification.JobType)) platformidentification.JobType {
+		ret := platformidentification.CloneJobType(wanted)
+		mutate(&ret)
+		return ret
+	}
+
+	tests := []struct {
+		name            string
+		candidate       platformidentification.JobType
+		wantDistance    float64
+		wantDifferences string
+		wantNotOK       bool
+	}{
+		{
+			name:      "same",
+			candidate: wanted,
+		},
+		{
+			name:            "micro upgrade",
+			candidate:       with(func(j *platformidentification.JobType) { j.FromRelease = "4.12" }),
+			wantDistance:    1,
+			wantDifferences: "upgrade minor->micro",
+		},
+		{
+			name:            "previous release",
+			candidate:       with(func(j *platformidentification.JobType) { j.Release, j.FromRelease = "4.10", "4.9" }),
+			wantDistance:    4,
+			wantDifferences: "release 4.12->4.10",
+		},
+		{
+			name:            "architecture and network",
+			candidate:       with(func(j *platformidentification.JobType) { j.Architecture, j.Network = "arm64", "sdn" }),
+			wantDistance:    12,
+			wantDifferences: "architecture amd64->arm64, network ovn->sdn",
+		},
+		{
+			name:      "newer release",
+			candidate: with(func(j *platformidentification.JobType) { j.Release = "4.13" }),
+			wantNotOK: true,
+		},
+		{
+			name:      "no upgrade",
+			candidate: with(func(j *platformidentification.JobType) { j.FromRelease = "" }),
+			wantNotOK: true,
+		},
+		{
+			name:      "platform",
+			candidate: with(func(j *platformidentification.JobType) { j.Platform = "gcp" }),
+			wantNotOK: true,
+		},
+		{
+			name:      "topology",
+			candidate: with(func(j *platformidentification.JobType) { j.Topology = "single" }),
+			wantNotOK: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			distance, differences, ok := DefaultJobTypeWeights.Distance(wanted, tt.candidate)
+			if ok == tt.wantNotOK {
+				t.Fatalf("expected ok %v, got %v", !tt.wantNotOK, ok)
+			}
+			if distance != tt.wantDistance {
+				t.Errorf("expected distance %v, got %v", tt.wantDistance, distance)
+			}
+			if got := strings.Join(differences, ", "); got != tt.wantDifferences {
+				t.Errorf("expected differences %q, got %q", tt.wantDifferences, got)
+			}
+		})
+	}
+}
+
+func TestBestMatch_closestJobType(t *testing.T) {
+	historicalJSON := []byte(`[
+  {"Name": "etcdNoLeader", "Release": "4.12", "FromRelease": "4.12", "Platform": "aws", "Architecture": "arm64", "Network": "ovn", "Topology": "ha", "P95": "1.0", "P99": "1.0"},
+  {"Name": "etcdNoLeader", "Release": "4.11", "FromRelease": "4.10", "Platform": "aws", "Architecture": "amd64", "Network": "ovn", "Topology": "ha", "P95": "2.0", "P99": "2.0"},
+  {"Name": "etcdNoLeader", "Release": "4.12", "FromRelease": "4.12", "Platform": "gcp", "Architecture": "amd64", "Network": "ovn", "Topology": "ha", "P95": "3.0", "P99": "3.0"}
+]`)
+	wanted := platformidentification.JobType{Release: "4.12", FromRelease: "4.11", Platform: "aws", Architecture: "amd64", Network: "ovn", Topology: "ha"}
+
+	matcher, err := NewMatcher(historicalJSON, 3.141)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, details, err := matcher.BestMatch("etcdNoLeader", wanted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the previous release costs 2, the other architecture with a micro upgrade costs 5
+	if data.P99 != 2 || !strings.Contains(details, "(distance 2: release 4.12->4.11)") {
+		t.Errorf("expected the previous release, got %v %v", data.P99, details)
+	}
+
+	// prefer any architecture over an older release
+	architectureFirst := DefaultJobTypeWeights
+	architectureFirst.Architecture = 0.5
+	architectureFirst.UpgradeType = 0.5
+	matcher, err = NewMatcherWithDistance(historicalJSON, 3.141, architectureFirst.Distance)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, details, err = matcher.BestMatch("etcdNoLeader", wanted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.P99 != 1 || !strings.Contains(details, "(distance 1: upgrade minor->micro, architecture amd64->arm64)") {
+		t.Errorf("expected the other architecture, got %v %v", data.P99, details)
+	}
+
+	// allow other platforms at any cost
+	anyPlatform := DefaultJobTypeWeights
+	anyPlatform.Platform = 0
+	anyPlatform.PreviousRelease = math.Inf(1)
+	matcher, err = NewMatcherWithDistance(historicalJSON, 3.141, anyPlatform.Distance)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, details, err = matcher.BestMatch("etcdNoLeader", wanted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.P99 != 3 || !strings.Contains(details, "platform aws->gcp") {
+		t.Errorf("expected the other platform, got %v %v", data.P99, details)
+	}
+}
+
+func TestBestMatch_optionalDimensions(t *testing.T) {
+	historicalJSON := []byte(`[
+  {"Name": "etcdNoLeader", "Release": "4.12", "FromRelease": "", "Platform": "aws", "Architecture": "amd64", "Network": "ovn", "Topology": "ha", "P95": "1.0", "P99": "1.0"},
+  {"Name": "etcdNoLeader", "Release": "4.12", "FromRelease": "", "Platform": "aws", "Architecture": "amd64", "Network": "ovn", "Topology": "ha", "FIPS": "true", "P95": "2.0", "P99": "2.0"},
+  {"Name": "etcdNoLeader", "Release": "4.12", "FromRelease": "", "Platform": "aws", "Architecture": "amd64", "Network": "ovn", "Topology": "ha", "IPFamily": "ipv6", "P95": "3.0", "P99": "3.0"}
+]`)
+	matcher, err := NewMatcher(historicalJSON, 3.141)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		jobType     platformidentification.JobType
+		wantP99     float64
+		wantDetails string
+	}{
+		{
+			name:    "without optional dimensions",
+			jobType: platformidentification.JobType{Release: "4.12", Platform: "aws", Architecture: "amd64", Network: "ovn", Topology: "ha"},
+			wantP99: 1,
+		},
+		{
+			name:    "data without the optional dimensions is an exact match",
+			jobType: platformidentification.JobType{Release: "4.12", Platform: "aws", Architecture: "amd64", Network: "ovn", Topology: "ha", IPFamily: "ipv4", Proxy: "false", FIPS: "false", InstallerType: "ipi"},
+			wantP99: 1,
+		},
+		{
+			name:    "the most specific data wins",
+			jobType: platformidentification.JobType{Release: "4.12", Platform: "aws", Architecture: "amd64", Network: "ovn", Topology: "ha", IPFamily: "ipv4", FIPS: "true"},
+			wantP99: 2,
+		},
+		{
+			name:    "exact match on an optional dimension",
+			jobType: platformidentification.JobType{Release: "4.12", Platform: "aws", Architecture: "amd64", Network: "ovn", Topology: "ha", IPFamily: "ipv6"},
+			wantP99: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, details, err := matcher.BestMatch("etcdNoLeader", tt.jobType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if data.P99 != tt.wantP99 {
+				t.Errorf("expected p99 %v, got %v", tt.wantP99, data.P99)
+			}
+			if details != tt.wantDetails {
+				t.Errorf("expected details %q, got %q", tt.wantDetails, details)
+			}
+		})
+	}
+
+	// data for another value of an optional dimension is only a fallback
+	distance, differences, ok := DefaultJobTypeDistance(
+		platformidentification.JobType{Release: "4.12", Platform: "aws", Architecture: "amd64", Network: "ovn", Topology: "ha", IPFamily: "ipv4"},
+		platformidentification.JobType{Release: "4.12", Platform: "aws", Architecture: "amd64", Network: "ovn", Topology: "ha", IPFamily: "ipv6"},
+	)
+	if !ok || distance != DefaultJobTypeWeights.IPFamily || strings.Join(differences, ", ") != "ip family ipv4->ipv6" {
+		t.Errorf("expected the other ip family to cost %v, got %v %v %v", DefaultJobTypeWeights.IPFamily, distance, differences, ok)
+	}
+}
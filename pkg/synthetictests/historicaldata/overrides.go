@@ -0,0 +1,134 @@
+package historicaldata
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// HistoricalDataDirEnvVar is the directory to read historical data from when --historical-data-dir is not set.
+	// It is also how the directory is passed to the processes running the individual tests.
+	HistoricalDataDirEnvVar = "TEST_HISTORICAL_DATA_DIR"
+)
+
+var (
+	historicalDataDirLock sync.Mutex
+	historicalDataDir     string
+)
+
+// HistoricalDataOverride is historical data supplied by the user instead of embedded in the binary.
+type HistoricalDataOverride struct {
+	// Source is where the data was read from, like the path of the file.
+	Source string
+	// JSON is the historical data in the format of the embedded query_results.json, using Name for the name.
+	JSON []byte
+}
+
+// SetHistoricalDataDir sets the directory to read historical data from, overriding HistoricalDataDirEnvVar.  The data
+// is read when the historical data of each type is first needed, so this must be called before running any tests.
+func SetHistoricalDataDir(dir string) {
+	historicalDataDirLock.Lock()
+	defer historicalDataDirLock.Unlock()
+	historicalDataDir = dir
+}
+
+// HistoricalDataDir returns the directory to read historical data from, or empty if there is none.
+func HistoricalDataDir() string {
+	historicalDataDirLock.Lock()
+	defer historicalDataDirLock.Unlock()
+	if len(historicalDataDir) > 0 {
+		return historicalDataDir
+	}
+	return os.Getenv(HistoricalDataDirEnvVar)
+}
+
+// LoadOverrides reads the historical data of the data type, like alerts or disruptions, from the historical data
+// directory.  The data of a type is read from <dir>/<dataType>.json, followed by every <dir>/<dataType>/*.json in
+// lexical order, so later files take precedence over earlier ones.  The files use the format of the embedded
+// query_results.json, where nameField, like AlertName, is the name of the data.
+func LoadOverrides(dataType, nameField string) ([]HistoricalDataOverride, error) {
+	dir := HistoricalDataDir()
+	if len(dir) == 0 {
+		return nil, nil
+	}
+	if info, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("unable to read historical data: %w", err)
+	} else if !info.IsDir() {
+		return nil, fmt.Errorf("unable to read historical data: %v is not a directory", dir)
+	}
+
+	files := []string{}
+	if _, err := os.Stat(filepath.Join(dir, dataType+".json")); err == nil {
+		files = append(files, filepath.Join(dir, dataType+".json"))
+	}
+	dataTypeFiles, err := filepath.Glob(filepath.Join(dir, dataType, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(dataTypeFiles)
+	files = append(files, dataTypeFiles...)
+
+	ret := []HistoricalDataOverride{}
+	for _, file := range files {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read historical data: %w", err)
+		}
+		genericJSON, err := renameField(content, nameField, "Name")
+		if err != nil {
+			return nil, fmt.Errorf("unable to read historical data from %v: %w", file, err)
+		}
+		ret = append(ret, HistoricalDataOverride{Source: file, JSON: genericJSON})
+	}
+	return ret, nil
+}
+
+// ValidateOverrides checks that the historical data of every data type can be read, so that a bad file is reported
+// before running any tests instead of when the data is first needed.  It returns a description of what was found.
+func ValidateOverrides(dataTypeToNameField map[string]string) ([]string, error) {
+	dataTypes := []string{}
+	for dataType := range dataTypeToNameField {
+		dataTypes = append(dataTypes, dataType)
+	}
+	sort.Strings(dataTypes)
+
+	var messages []string
+	for _, dataType := range dataTypes {
+		overrides, err := LoadOverrides(dataType, dataTypeToNameField[dataType])
+		if err != nil {
+			return nil, err
+		}
+		for _, override := range overrides {
+			data, err := decodeHistoricalData(override.JSON)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read historical data from %v: %w", override.Source, err)
+			}
+			messages = append(messages, fmt.Sprintf("%d %s entries from %v take precedence over the embedded data", len(data), dataType, override.Source))
+		}
+	}
+	return messages, nil
+}
+
+// renameField renames a field of every object in a JSON list.  Indentation and order are not preserved.
+func renameField(listJSON []byte, from, to string) ([]byte, error) {
+	if from == to || !strings.Contains(string(listJSON), from) {
+		return listJSON, nil
+	}
+	list := []map[string]interface{}{}
+	if err := json.Unmarshal(listJSON, &list); err != nil {
+		return nil, err
+	}
+	for _, curr := range list {
+		if value, ok := curr[from]; ok {
+			curr[to] = value
+			delete(curr, from)
+		}
+	}
+	return json.Marshal(list)
+}
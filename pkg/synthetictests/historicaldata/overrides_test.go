@@ -0,0 +1,117 @@
+package historicaldata
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openshift/origin/pkg/synthetictests/platformidentification"
+)
+
+const embeddedAlerts = `[
+  {
+    "AlertName": "etcdNoLeader",
+    "Release": "4.12",
+    "FromRelease": "",
+    "Platform": "aws",
+    "Architecture": "amd64",
+    "Network": "ovn",
+    "Topology": "ha",
+    "P95": "1.0",
+    "P99": "2.0"
+  }
+]`
+
+func TestNewMatcherWithOverrides(t *testing.T) {
+	dir, err := ioutil.TempDir("", "historical-data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeFile := func(path, content string) {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(filepath.Join(dir, "alerts.json"),
+		`[{"AlertName": "etcdNoLeader", "Release": "4.12", "Platform": "aws", "Architecture": "amd64", "Network": "ovn", "Topology": "ha", "P95": "10.0", "P99": "20.0"}]`)
+	writeFile(filepath.Join(dir, "alerts", "vsphere.json"),
+		`[{"AlertName": "etcdNoLeader", "Release": "4.12", "Platform": "vsphere", "Architecture": "amd64", "Network": "ovn", "Topology": "ha", "P95": "30.0", "P99": "40.0"}]`)
+	writeFile(filepath.Join(dir, "disruptions.json"), `not json`)
+
+	SetHistoricalDataDir(dir)
+	defer SetHistoricalDataDir("")
+
+	overrides, err := LoadOverrides("alerts", "AlertName")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(overrides) != 2 {
+		t.Fatalf("expected two files, got %v", overrides)
+	}
+	embedded, err := renameField([]byte(embeddedAlerts), "AlertName", "Name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	matcher, err := NewMatcherWithOverrides(embedded, 3.141, overrides...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		platform    string
+		wantP99     float64
+		wantDetails string
+	}{
+		{
+			name:        "replaces embedded data",
+			platform:    "aws",
+			wantP99:     20,
+			wantDetails: "which takes precedence over the embedded data",
+		},
+		{
+			name:        "adds data",
+			platform:    "vsphere",
+			wantP99:     40,
+			wantDetails: "(from " + filepath.Join(dir, "alerts", "vsphere.json") + ")",
+		},
+		{
+			name:        "default",
+			platform:    "gcp",
+			wantP99:     3.141,
+			wantDetails: "no exact or fuzzy match",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobType := platformidentification.JobType{
+				Release:      "4.12",
+				Platform:     tt.platform,
+				Architecture: "amd64",
+				Network:      "ovn",
+				Topology:     "ha",
+			}
+			data, details, err := matcher.BestMatch("etcdNoLeader", jobType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if data.P99 != tt.wantP99 {
+				t.Errorf("expected p99 %v, got %v", tt.wantP99, data.P99)
+			}
+			if !strings.Contains(details, tt.wantDetails) {
+				t.Errorf("expected details to contain %q, got %q", tt.wantDetails, details)
+			}
+		})
+	}
+
+	if _, err := ValidateOverrides(map[string]string{"disruptions": "BackendName"}); err == nil {
+		t.Errorf("expected an error for invalid disruption data")
+	}
+}
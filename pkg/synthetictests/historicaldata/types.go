@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/openshift/origin/pkg/synthetictests/platformidentification"
@@ -19,18 +21,64 @@ type BestMatcher interface {
 	BestMatchDuration(name string, jopType platformidentification.JobType) (StatisticalDuration, string, error)
 
 	BestMatchP99(name string, jobType platformidentification.JobType) (*time.Duration, string, error)
+
+	// BestMatchWithMinimumJobRuns is BestMatch, but skips fuzzy matches based on fewer than minimumJobRuns job runs.
+	// An exact match is always used, as is data that does not know how many job runs it is based on.
+	BestMatchWithMinimumJobRuns(name string, jobType platformidentification.JobType, minimumJobRuns int64) (StatisticalData, string, error)
+	// BestMatchDurationWithMinimumJobRuns is BestMatchDuration, but skips fuzzy matches based on fewer than
+	// minimumJobRuns job runs.
+	BestMatchDurationWithMinimumJobRuns(name string, jobType platformidentification.JobType, minimumJobRuns int64) (StatisticalDuration, string, error)
 }
 
+const (
+	// PercentileMax is the percentile of the largest value.
+	PercentileMax = 100
+)
+
 type StatisticalDuration struct {
 	DataKey `json:",inline"`
 	P95     time.Duration
 	P99     time.Duration
+
+	// Percentiles holds the percentiles other than P95 and P99 that are known, like 50 or PercentileMax.
+	Percentiles map[int]time.Duration `json:",omitempty"`
+	// JobRuns is how many job runs the data is based on, or zero when that is not known.
+	JobRuns int64 `json:",omitempty"`
+}
+
+// Percentile returns the percentile, like 50 or PercentileMax, and whether it is known.
+func (d StatisticalDuration) Percentile(percentile int) (time.Duration, bool) {
+	switch percentile {
+	case 95:
+		return d.P95, true
+	case 99:
+		return d.P99, true
+	}
+	ret, ok := d.Percentiles[percentile]
+	return ret, ok
 }
 
 type StatisticalData struct {
 	DataKey `json:",inline"`
 	P95     float64
 	P99     float64
+
+	// Percentiles holds the percentiles other than P95 and P99 that are known, like 50 or PercentileMax.
+	Percentiles map[int]float64 `json:",omitempty"`
+	// JobRuns is how many job runs the data is based on, or zero when that is not known.
+	JobRuns int64 `json:",omitempty"`
+}
+
+// Percentile returns the percentile, like 50 or PercentileMax, and whether it is known.
+func (d StatisticalData) Percentile(percentile int) (float64, bool) {
+	switch percentile {
+	case 95:
+		return d.P95, true
+	case 99:
+		return d.P99, true
+	}
+	ret, ok := d.Percentiles[percentile]
+	return ret, ok
 }
 
 type DataKey struct {
@@ -43,25 +91,100 @@ type DataKey struct {
 type bestMatcher struct {
 	historicalData map[DataKey]StatisticalData
 	defaultReturn  float64
+
+	// keyToOverride holds where the historical data for a key came from when it was not embedded.
+	keyToOverride map[DataKey]overrideSource
+
+	// nameToKeys holds every key of a name, sorted, to search for the closest job type.
+	nameToKeys map[string][]DataKey
+	distance   JobTypeDistanceFunc
+}
+
+type overrideSource struct {
+	source string
+	// replacedEmbedded is true when the embedded data had the same key.
+	replacedEmbedded bool
 }
 
 func NewMatcher(historicalJSON []byte, defaultReturn float64) (BestMatcher, error) {
+	return NewMatcherWithOverrides(historicalJSON, defaultReturn)
+}
+
+// NewMatcherWithOverrides merges the overrides, in order, into the historical data.  Data in an override takes
+// precedence over the embedded data and earlier overrides with the same key.
+func NewMatcherWithOverrides(historicalJSON []byte, defaultReturn float64, overrides ...HistoricalDataOverride) (BestMatcher, error) {
+	return NewMatcherWithDistance(historicalJSON, defaultReturn, DefaultJobTypeDistance, overrides...)
+}
+
+// NewMatcherWithDistance uses the distance to find the closest job type with data when there is no data for the wanted
+// job type.
+func NewMatcherWithDistance(historicalJSON []byte, defaultReturn float64, distance JobTypeDistanceFunc, overrides ...HistoricalDataOverride) (BestMatcher, error) {
+	embedded, err := decodeHistoricalData(historicalJSON)
+	if err != nil {
+		return nil, err
+	}
+
 	historicalData := map[DataKey]StatisticalData{}
+	for _, curr := range embedded {
+		historicalData[curr.DataKey] = curr
+	}
+	keyToOverride := map[DataKey]overrideSource{}
+	for _, override := range overrides {
+		data, err := decodeHistoricalData(override.JSON)
+		if err != nil {
+			return nil, fmt.Errorf("failed reading historical data from %v: %w", override.Source, err)
+		}
+		for _, curr := range data {
+			_, replacedEmbedded := historicalData[curr.DataKey]
+			if previous, ok := keyToOverride[curr.DataKey]; ok {
+				replacedEmbedded = previous.replacedEmbedded
+			}
+			historicalData[curr.DataKey] = curr
+			keyToOverride[curr.DataKey] = overrideSource{source: override.Source, replacedEmbedded: replacedEmbedded}
+		}
+	}
+
+	nameToKeys := map[string][]DataKey{}
+	for key := range historicalData {
+		nameToKeys[key.Name] = append(nameToKeys[key.Name], key)
+	}
+	for _, keys := range nameToKeys {
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprintf("%#v", keys[i]) < fmt.Sprintf("%#v", keys[j])
+		})
+	}
 
+	return &bestMatcher{
+		historicalData: historicalData,
+		defaultReturn:  defaultReturn,
+		keyToOverride:  keyToOverride,
+		nameToKeys:     nameToKeys,
+		distance:       distance,
+	}, nil
+}
+
+// decodeHistoricalData reads the format of query_results.json.  Every value is a string, because that is how BigQuery
+// exports numbers.  P95 and P99 are required, while the other percentiles and JobRuns are optional so that older files
+// can still be read.
+func decodeHistoricalData(historicalJSON []byte) ([]StatisticalData, error) {
 	inFile := bytes.NewBuffer(historicalJSON)
 	jsonDecoder := json.NewDecoder(inFile)
-
 	type DecodingPercentile struct {
 		DataKey `json:",inline"`
+		P50     string
+		P75     string
+		P90     string
 		P95     string
 		P99     string
+		Max     string
+		JobRuns string
 	}
 	decodingPercentilesList := []DecodingPercentile{}
-
 	if err := jsonDecoder.Decode(&decodingPercentilesList); err != nil {
 		return nil, err
 	}
 
+	ret := []StatisticalData{}
 	for _, currDecoded := range decodingPercentilesList {
 		p95, err := strconv.ParseFloat(currDecoded.P95, 64)
 		if err != nil {
@@ -76,38 +199,107 @@ func NewMatcher(historicalJSON []byte, defaultReturn float64) (BestMatcher, erro
 			P95:     p95,
 			P99:     p99,
 		}
-		historicalData[curr.DataKey] = curr
+
+		optionalPercentiles := map[int]string{
+			50:            currDecoded.P50,
+			75:            currDecoded.P75,
+			90:            currDecoded.P90,
+			PercentileMax: currDecoded.Max,
+		}
+		for percentile, value := range optionalPercentiles {
+			if len(value) == 0 {
+				continue
+			}
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, err
+			}
+			if curr.Percentiles == nil {
+				curr.Percentiles = map[int]float64{}
+			}
+			curr.Percentiles[percentile] = parsed
+		}
+		if len(currDecoded.JobRuns) > 0 {
+			if curr.JobRuns, err = strconv.ParseInt(currDecoded.JobRuns, 10, 64); err != nil {
+				return nil, err
+			}
+		}
+		ret = append(ret, curr)
 	}
+	return ret, nil
+}
 
-	return &bestMatcher{
-		historicalData: historicalData,
-		defaultReturn:  defaultReturn,
-	}, nil
+// sourceDetails describes where the data for the key came from when it was not embedded.
+func (b *bestMatcher) sourceDetails(key DataKey) string {
+	override, ok := b.keyToOverride[key]
+	switch {
+	case !ok:
+		return ""
+	case override.replacedEmbedded:
+		return fmt.Sprintf("(from %v, which takes precedence over the embedded data)", override.source)
+	default:
+		return fmt.Sprintf("(from %v)", override.source)
+	}
 }
 
 func (b *bestMatcher) BestMatch(name string, jobType platformidentification.JobType) (StatisticalData, string, error) {
+	return b.BestMatchWithMinimumJobRuns(name, jobType, 0)
+}
+
+func (b *bestMatcher) BestMatchWithMinimumJobRuns(name string, jobType platformidentification.JobType, minimumJobRuns int64) (StatisticalData, string, error) {
 	exactMatchKey := DataKey{
 		Name:    name,
 		JobType: jobType,
 	}
 
 	if percentiles, ok := b.historicalData[exactMatchKey]; ok {
-		return percentiles, "", nil
+		return percentiles, b.sourceDetails(exactMatchKey), nil
 	}
 
-	// tested in TestGetClosestP95Value in allowedbackendisruption.  Should get a local test at some point.
-	for _, nextBestGuesser := range nextBestGuessers {
-		nextBestJobType, ok := nextBestGuesser(jobType)
+	// every other job type with data is a candidate, the closest one wins.  Among equally close candidates the one
+	// with the most optional dimensions wins, otherwise ties go to the first key in sort order, which prefers amd64
+	// and releases without an upgrade.
+	type candidate struct {
+		key         DataKey
+		distance    float64
+		differences []string
+	}
+	candidates := []candidate{}
+	for _, key := range b.nameToKeys[name] {
+		distance, differences, ok := b.distance(jobType, key.JobType)
 		if !ok {
 			continue
 		}
-		nextBestMatchKey := DataKey{
-			Name:    name,
-			JobType: nextBestJobType,
+		candidates = append(candidates, candidate{key: key, distance: distance, differences: differences})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return optionalDimensionsOf(candidates[i].key.JobType) > optionalDimensionsOf(candidates[j].key.JobType)
+	})
+
+	var skipped []string
+	for _, nextBest := range candidates {
+		percentiles := b.historicalData[nextBest.key]
+		if percentiles.JobRuns > 0 && percentiles.JobRuns < minimumJobRuns {
+			skipped = append(skipped, fmt.Sprintf("(skipped %#v with only %d job runs)", nextBest.key, percentiles.JobRuns))
+			continue
+		}
+		// data without some of the optional dimensions applies to every value of them, so it is as good as an
+		// exact match.
+		if len(nextBest.differences) == 0 && len(skipped) == 0 {
+			return percentiles, b.sourceDetails(nextBest.key), nil
 		}
-		if percentiles, ok := b.historicalData[nextBestMatchKey]; ok {
-			return percentiles, fmt.Sprintf("(no exact match for %#v, fell back to %#v)", exactMatchKey, nextBestMatchKey), nil
+		details := fmt.Sprintf("(no exact match for %#v, fell back to %#v) (distance %v: %s)",
+			exactMatchKey, nextBest.key, nextBest.distance, strings.Join(nextBest.differences, ", "))
+		if sourceDetails := b.sourceDetails(nextBest.key); len(sourceDetails) > 0 {
+			details += " " + sourceDetails
 		}
+		if len(skipped) > 0 {
+			details += " " + strings.Join(skipped, " ")
+		}
+		return percentiles, details, nil
 	}
 
 	defaultReturn := StatisticalData{
@@ -115,9 +307,22 @@ func (b *bestMatcher) BestMatch(name string, jobType platformidentification.JobT
 		P95:     b.defaultReturn,
 		P99:     b.defaultReturn,
 	}
-	return defaultReturn,
-		fmt.Sprintf("(no exact or fuzzy match for jobType=%#v)", jobType),
-		nil
+	details := fmt.Sprintf("(no exact or fuzzy match for jobType=%#v)", jobType)
+	if len(skipped) > 0 {
+		details += " " + strings.Join(skipped, " ")
+	}
+	return defaultReturn, details, nil
+}
+
+// optionalDimensionsOf counts the optional dimensions the job type has.
+func optionalDimensionsOf(jobType platformidentification.JobType) int {
+	count := 0
+	for _, value := range []string{jobType.IPFamily, jobType.Proxy, jobType.FIPS, jobType.InstallerType} {
+		if len(value) > 0 {
+			count++
+		}
+	}
+	return count
 }
 
 func (b *bestMatcher) BestMatchDuration(name string, jobType platformidentification.JobType) (StatisticalDuration, string, error) {
@@ -125,17 +330,30 @@ func (b *bestMatcher) BestMatchDuration(name string, jobType platformidentificat
 	return toStatisticalDuration(rawData), details, err
 }
 
+func (b *bestMatcher) BestMatchDurationWithMinimumJobRuns(name string, jobType platformidentification.JobType, minimumJobRuns int64) (StatisticalDuration, string, error) {
+	rawData, details, err := b.BestMatchWithMinimumJobRuns(name, jobType, minimumJobRuns)
+	return toStatisticalDuration(rawData), details, err
+}
+
 func (b *bestMatcher) BestMatchP99(name string, jobType platformidentification.JobType) (*time.Duration, string, error) {
 	rawData, details, err := b.BestMatchDuration(name, jobType)
 	return &rawData.P99, details, err
 }
 
 func toStatisticalDuration(in StatisticalData) StatisticalDuration {
-	return StatisticalDuration{
+	ret := StatisticalDuration{
 		DataKey: in.DataKey,
 		P95:     DurationOrDie(in.P95),
 		P99:     DurationOrDie(in.P99),
+		JobRuns: in.JobRuns,
+	}
+	for percentile, seconds := range in.Percentiles {
+		if ret.Percentiles == nil {
+			ret.Percentiles = map[int]time.Duration{}
+		}
+		ret.Percentiles[percentile] = DurationOrDie(seconds)
 	}
+	return ret
 }
 
 func DurationOrDie(seconds float64) time.Duration {
@@ -0,0 +1,123 @@
+package historicaldata
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/openshift/origin/pkg/synthetictests/platformidentification"
+)
+
+func TestBestMatchWithMinimumJobRuns(t *testing.T) {
+	historicalJSON := []byte(`[
+  {"Name": "etcdNoLeader", "Release": "4.12", "FromRelease": "4.12", "Platform": "aws", "Architecture": "amd64", "Network": "ovn", "Topology": "ha",
+   "P50": "1.0", "P75": "2.0", "P90": "3.0", "P95": "4.0", "P99": "5.0", "Max": "6.0", "JobRuns": "3"},
+  {"Name": "etcdNoLeader", "Release": "4.12", "FromRelease": "4.11", "Platform": "aws", "Architecture": "amd64", "Network": "ovn", "Topology": "ha",
+   "P95": "7.0", "P99": "8.0"},
+  {"Name": "etcdNoLeader", "Release": "4.12", "FromRelease": "4.12", "Platform": "gcp", "Architecture": "amd64", "Network": "ovn", "Topology": "ha",
+   "P95": "9.0", "P99": "10.0", "JobRuns": "3000"}
+]`)
+	matcher, err := NewMatcher(historicalJSON, 3.141)
+	if err != nil {
+		t.Fatal(err)
+	}
+	microUpgrade := platformidentification.JobType{Release: "4.12", FromRelease: "4.12", Platform: "aws", Architecture: "amd64", Network: "ovn", Topology: "ha"}
+	minorUpgrade := platformidentification.JobType{Release: "4.12", FromRelease: "4.11", Platform: "aws", Architecture: "amd64", Network: "ovn", Topology: "ha"}
+	minorUpgradeOnGCP := platformidentification.JobType{Release: "4.12", FromRelease: "4.11", Platform: "gcp", Architecture: "amd64", Network: "ovn", Topology: "ha"}
+
+	tests := []struct {
+		name           string
+		jobType        platformidentification.JobType
+		minimumJobRuns int64
+		wantP99        float64
+		wantJobRuns    int64
+		wantDetails    string
+	}{
+		{
+			name:           "exact match with few job runs",
+			jobType:        microUpgrade,
+			minimumJobRuns: 10,
+			wantP99:        5,
+			wantJobRuns:    3,
+		},
+		{
+			name:           "exact match without job runs",
+			jobType:        minorUpgrade,
+			minimumJobRuns: 10,
+			wantP99:        8,
+		},
+		{
+			name:           "fallback with enough job runs",
+			jobType:        minorUpgradeOnGCP,
+			minimumJobRuns: 10,
+			wantP99:        10,
+			wantJobRuns:    3000,
+			wantDetails:    "fell back to",
+		},
+		{
+			name:           "fallback with too few job runs",
+			jobType:        platformidentification.JobType{Release: "4.12", FromRelease: "4.12", Platform: "aws", Architecture: "arm64", Network: "ovn", Topology: "ha"},
+			minimumJobRuns: 10,
+			// amd64 micro upgrade is skipped, amd64 minor upgrade has no job runs to compare
+			wantP99:     8,
+			wantDetails: "with only 3 job runs",
+		},
+		{
+			name:        "fallback without a minimum",
+			jobType:     platformidentification.JobType{Release: "4.12", FromRelease: "4.12", Platform: "aws", Architecture: "arm64", Network: "ovn", Topology: "ha"},
+			wantP99:     5,
+			wantJobRuns: 3,
+			wantDetails: "fell back to",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, details, err := matcher.BestMatchWithMinimumJobRuns("etcdNoLeader", tt.jobType, tt.minimumJobRuns)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if data.P99 != tt.wantP99 || data.JobRuns != tt.wantJobRuns {
+				t.Errorf("expected p99 %v from %d job runs, got %v from %d", tt.wantP99, tt.wantJobRuns, data.P99, data.JobRuns)
+			}
+			if !strings.Contains(details, tt.wantDetails) {
+				t.Errorf("expected details to contain %q, got %q", tt.wantDetails, details)
+			}
+		})
+	}
+}
+
+func TestStatisticalDuration_Percentile(t *testing.T) {
+	historicalJSON := []byte(`[
+  {"Name": "etcdNoLeader", "Release": "4.12", "Platform": "aws", "P50": "1.5", "P95": "4.0", "P99": "5.0", "Max": "60.0"},
+  {"Name": "etcdNoLeader", "Release": "4.11", "Platform": "aws", "P95": "4.0", "P99": "5.0"}
+]`)
+	matcher, err := NewMatcher(historicalJSON, 3.141)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, _, err := matcher.BestMatchDuration("etcdNoLeader", platformidentification.JobType{Release: "4.12", Platform: "aws"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p50, ok := data.Percentile(50); !ok || p50.Seconds() != 1.5 {
+		t.Errorf("expected p50 of 1.5s, got %v", p50)
+	}
+	if max, ok := data.Percentile(PercentileMax); !ok || max.Seconds() != 60 {
+		t.Errorf("expected max of 60s, got %v", max)
+	}
+	if _, ok := data.Percentile(90); ok {
+		t.Errorf("expected p90 to be unknown")
+	}
+
+	// data with only P95 and P99, like the embedded data
+	data, _, err = matcher.BestMatchDuration("etcdNoLeader", platformidentification.JobType{Release: "4.11", Platform: "aws"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := data.Percentile(50); ok || data.Percentiles != nil || data.JobRuns != 0 {
+		t.Errorf("expected only p95 and p99, got %#v", data)
+	}
+	if p99, ok := data.Percentile(99); !ok || p99.Seconds() != 5 {
+		t.Errorf("expected p99 of 5s, got %v", p99)
+	}
+}
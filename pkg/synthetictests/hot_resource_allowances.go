@@ -0,0 +1,81 @@
+package synthetictests
+
+import (
+	_ "embed"
+	"fmt"
+	"regexp"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+//go:embed hot_resource_allowances.yaml
+var hotResourceAllowancesYAML []byte
+
+// defaultHotResourceLimits are the limits checked in with origin.
+var defaultHotResourceLimits = mustParse(parseHotResourceLimits, hotResourceAllowancesYAML)
+
+// hotResourceAllowanceFile is the serialized form of hot_resource_allowances.yaml.
+type hotResourceAllowanceFile struct {
+	versionedDataFile
+	DefaultMaxUpdatesPerHour     int                              `json:"defaultMaxUpdatesPerHour"`
+	DefaultMaxRecreationsPerHour int                              `json:"defaultMaxRecreationsPerHour"`
+	Allowances                   []serializedHotResourceAllowance `json:"allowances,omitempty"`
+}
+
+type serializedHotResourceAllowance struct {
+	ResourceType          string `json:"resourceType"`
+	NamespacePattern      string `json:"namespacePattern,omitempty"`
+	NamePattern           string `json:"namePattern,omitempty"`
+	MaxUpdatesPerHour     int    `json:"maxUpdatesPerHour"`
+	MaxRecreationsPerHour int    `json:"maxRecreationsPerHour"`
+	Owner                 string `json:"owner,omitempty"`
+}
+
+func parseHotResourceLimits(data []byte) (hotResourceEvaluator, error) {
+	ret := hotResourceEvaluator{}
+
+	file := hotResourceAllowanceFile{}
+	if err := parseDataFile("hot resource allowances", data, &file); err != nil {
+		return ret, err
+	}
+	if file.DefaultMaxUpdatesPerHour <= 0 || file.DefaultMaxRecreationsPerHour <= 0 {
+		return ret, fmt.Errorf("defaultMaxUpdatesPerHour and defaultMaxRecreationsPerHour must be positive")
+	}
+	ret.defaultMaxUpdatesPerHour = file.DefaultMaxUpdatesPerHour
+	ret.defaultMaxRecreationsPerHour = file.DefaultMaxRecreationsPerHour
+
+	for i, allowance := range file.Allowances {
+		if len(allowance.ResourceType) == 0 {
+			return ret, fmt.Errorf("allowance %d: resourceType is required", i)
+		}
+		if !sets.NewString(hotResourceTypes...).Has(allowance.ResourceType) {
+			return ret, fmt.Errorf("allowance %d: resourceType must be one of %v", i, hotResourceTypes)
+		}
+		if allowance.MaxUpdatesPerHour <= 0 || allowance.MaxRecreationsPerHour <= 0 {
+			return ret, fmt.Errorf("allowance %d: maxUpdatesPerHour and maxRecreationsPerHour must be positive", i)
+		}
+
+		curr := hotResourceAllowance{
+			resourceType:          allowance.ResourceType,
+			maxUpdatesPerHour:     allowance.MaxUpdatesPerHour,
+			maxRecreationsPerHour: allowance.MaxRecreationsPerHour,
+		}
+		if len(allowance.NamespacePattern) > 0 {
+			re, err := regexp.Compile(allowance.NamespacePattern)
+			if err != nil {
+				return ret, fmt.Errorf("allowance %d: invalid namespacePattern: %w", i, err)
+			}
+			curr.namespace = re
+		}
+		if len(allowance.NamePattern) > 0 {
+			re, err := regexp.Compile(allowance.NamePattern)
+			if err != nil {
+				return ret, fmt.Errorf("allowance %d: invalid namePattern: %w", i, err)
+			}
+			curr.name = re
+		}
+		ret.allowances = append(ret.allowances, curr)
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,169 @@
+package synthetictests
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+const (
+	// leaderElectionAnnotation is set on configmaps used for leader election.  They are renewed every few seconds.
+	leaderElectionAnnotation = "control-plane.alpha.kubernetes.io/leader"
+)
+
+// hotResourceTypes are the recorded resource types that are checked.  Other recorded types, like events, are updated
+// as a matter of course and are covered by their own tests.
+var hotResourceTypes = []string{"configmaps", "pods"}
+
+// hotResourceAllowance raises the limits for resources that are expected to change often.
+type hotResourceAllowance struct {
+	// resourceType is the recorded resource type, like configmaps.
+	resourceType string
+	namespace    *regexp.Regexp
+	name         *regexp.Regexp
+
+	maxUpdatesPerHour     int
+	maxRecreationsPerHour int
+}
+
+func (a hotResourceAllowance) matches(resourceType, namespace, name string) bool {
+	if a.resourceType != resourceType {
+		return false
+	}
+	if a.namespace != nil && !a.namespace.MatchString(namespace) {
+		return false
+	}
+	if a.name != nil && !a.name.MatchString(name) {
+		return false
+	}
+	return true
+}
+
+// hotResourceEvaluator finds resources in openshift-* namespaces that were updated or recreated more often than
+// expected for the duration of the run, like two operators fighting over the content of a configmap.
+type hotResourceEvaluator struct {
+	defaultMaxUpdatesPerHour     int
+	defaultMaxRecreationsPerHour int
+
+	// allowances are checked in order, the first matching allowance wins.
+	allowances []hotResourceAllowance
+}
+
+// hotResource is every recorded instance of a resource with the same namespace and name.
+type hotResource struct {
+	resourceType string
+	namespace    string
+	name         string
+
+	updates     int
+	recreations int
+}
+
+func (r hotResource) String() string {
+	return fmt.Sprintf("ns/%s %s/%s", r.namespace, strings.TrimSuffix(r.resourceType, "s"), r.name)
+}
+
+// hotResourcesFrom sums the observed updates for each namespace and name.  The recorded resources are keyed by UID, so
+// the recreations are the number of UIDs observed for the same name, plus any recreations counted by the monitor.
+func hotResourcesFrom(resourceType string, instances monitorapi.InstanceMap) []hotResource {
+	keyToResource := map[string]*hotResource{}
+	for instanceKey, obj := range instances {
+		if !strings.HasPrefix(instanceKey.Namespace, "openshift-") {
+			continue
+		}
+		metadata, err := meta.Accessor(obj)
+		if err != nil {
+			continue
+		}
+		if _, ok := metadata.GetAnnotations()[leaderElectionAnnotation]; ok {
+			continue
+		}
+
+		key := instanceKey.Namespace + "/" + instanceKey.Name
+		resource, ok := keyToResource[key]
+		if !ok {
+			resource = &hotResource{resourceType: resourceType, namespace: instanceKey.Namespace, name: instanceKey.Name}
+			keyToResource[key] = resource
+		} else {
+			resource.recreations++
+		}
+
+		if updates, err := strconv.Atoi(metadata.GetAnnotations()[monitorapi.ObservedUpdateCountAnnotation]); err == nil {
+			resource.updates += updates
+		}
+		if recreations, err := strconv.Atoi(metadata.GetAnnotations()[monitorapi.ObservedRecreationCountAnnotation]); err == nil {
+			resource.recreations += recreations
+		}
+	}
+
+	ret := []hotResource{}
+	for _, resource := range keyToResource {
+		ret = append(ret, *resource)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].String() < ret[j].String()
+	})
+	return ret
+}
+
+func (e hotResourceEvaluator) limitsFor(resourceType, namespace, name string) (int, int) {
+	for _, allowance := range e.allowances {
+		if allowance.matches(resourceType, namespace, name) {
+			return allowance.maxUpdatesPerHour, allowance.maxRecreationsPerHour
+		}
+	}
+	return e.defaultMaxUpdatesPerHour, e.defaultMaxRecreationsPerHour
+}
+
+// findHotResources returns a message for every resource of the hotResourceTypes over its limits.  Runs shorter than an hour are held to
+// the hourly limits, so that a short run does not make a handful of updates look pathological.
+func (e hotResourceEvaluator) findHotResources(recordedResources monitorapi.ResourcesMap, duration time.Duration) []string {
+	hours := duration.Hours()
+	if hours < 1 {
+		hours = 1
+	}
+
+	var messages []string
+	for _, resourceType := range hotResourceTypes {
+		for _, resource := range hotResourcesFrom(resourceType, recordedResources[resourceType]) {
+			maxUpdatesPerHour, maxRecreationsPerHour := e.limitsFor(resourceType, resource.namespace, resource.name)
+			if allowed := int(float64(maxUpdatesPerHour) * hours); resource.updates > allowed {
+				messages = append(messages, fmt.Sprintf("%v was updated %d times in %v, more than the %d allowed at %d/h",
+					resource, resource.updates, duration.Round(time.Second), allowed, maxUpdatesPerHour))
+			}
+			if allowed := int(float64(maxRecreationsPerHour) * hours); resource.recreations > allowed {
+				messages = append(messages, fmt.Sprintf("%v was recreated %d times in %v, more than the %d allowed at %d/h",
+					resource, resource.recreations, duration.Round(time.Second), allowed, maxRecreationsPerHour))
+			}
+		}
+	}
+	return messages
+}
+
+func testHotResources(recordedResources *monitorapi.ResourcesMap, duration time.Duration) []*junitapi.JUnitTestCase {
+	const testName = "[sig-arch] resources in openshift namespaces should not be updated or recreated pathologically"
+	if recordedResources == nil {
+		return nil
+	}
+
+	messages := defaultHotResourceLimits.findHotResources(*recordedResources, duration)
+	tests := []*junitapi.JUnitTestCase{}
+	if len(messages) > 0 {
+		tests = append(tests, &junitapi.JUnitTestCase{
+			Name: testName,
+			FailureOutput: &junitapi.FailureOutput{
+				Output: fmt.Sprintf("%d resources changed too often\n\n%v", len(messages), strings.Join(messages, "\n")),
+			},
+		})
+	}
+	// only flake for now
+	tests = append(tests, &junitapi.JUnitTestCase{Name: testName})
+	return tests
+}
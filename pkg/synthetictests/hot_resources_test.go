@@ -0,0 +1,96 @@
+package synthetictests
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const testHotResourceAllowancesYAML = `
+version: 1
+defaultMaxUpdatesPerHour: 60
+defaultMaxRecreationsPerHour: 10
+allowances:
+- resourceType: configmaps
+  namespacePattern: '^openshift-bar$'
+  maxUpdatesPerHour: 1000
+  maxRecreationsPerHour: 10
+`
+
+func recordedConfigMap(namespace, name, uid string, updates int, annotations map[string]string) (monitorapi.InstanceKey, *corev1.ConfigMap) {
+	allAnnotations := map[string]string{
+		monitorapi.ObservedUpdateCountAnnotation:     strconv.Itoa(updates),
+		monitorapi.ObservedRecreationCountAnnotation: "0",
+	}
+	for k, v := range annotations {
+		allAnnotations[k] = v
+	}
+	return monitorapi.InstanceKey{Namespace: namespace, Name: name, UID: uid}, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, UID: types.UID(uid), Annotations: allAnnotations},
+	}
+}
+
+func TestFindHotResources(t *testing.T) {
+	configMaps := monitorapi.InstanceMap{}
+	add := func(key monitorapi.InstanceKey, configMap *corev1.ConfigMap) {
+		configMaps[key] = configMap
+	}
+	// fought over by two operators
+	add(recordedConfigMap("openshift-foo", "hot", "1", 500, nil))
+	// deleted and created over and over
+	for _, uid := range []string{"2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12", "13", "14", "15", "16", "17", "18", "19", "20", "21", "22", "23", "24"} {
+		add(recordedConfigMap("openshift-foo", "recreated", uid, 1, nil))
+	}
+	// boring
+	add(recordedConfigMap("openshift-foo", "cold", "25", 3, nil))
+	// renewed every few seconds for leader election
+	add(recordedConfigMap("openshift-foo", "lock", "26", 5000, map[string]string{leaderElectionAnnotation: "{}"}))
+	// not an openshift namespace
+	add(recordedConfigMap("e2e-foo", "hot", "27", 5000, nil))
+	// expected to change often
+	add(recordedConfigMap("openshift-bar", "allowed", "28", 500, nil))
+
+	evaluator, err := parseHotResourceLimits([]byte(testHotResourceAllowancesYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// events repeat as a matter of course and are not checked
+	events := monitorapi.InstanceMap{}
+	eventKey, event := recordedConfigMap("openshift-foo", "repeated", "29", 500, nil)
+	events[eventKey] = event
+
+	messages := evaluator.findHotResources(monitorapi.ResourcesMap{"configmaps": configMaps, "events": events}, 2*time.Hour)
+	if len(messages) != 2 {
+		t.Fatalf("unexpected messages: %v", strings.Join(messages, "\n"))
+	}
+	if messages[0] != "ns/openshift-foo configmap/hot was updated 500 times in 2h0m0s, more than the 120 allowed at 60/h" {
+		t.Errorf("unexpected message: %v", messages[0])
+	}
+	if messages[1] != "ns/openshift-foo configmap/recreated was recreated 22 times in 2h0m0s, more than the 20 allowed at 10/h" {
+		t.Errorf("unexpected message: %v", messages[1])
+	}
+}
+
+func TestParseHotResourceLimits(t *testing.T) {
+	invalid := map[string]string{
+		"missing defaults":      "version: 1\n",
+		"missing resource type": "version: 1\ndefaultMaxUpdatesPerHour: 60\ndefaultMaxRecreationsPerHour: 10\nallowances:\n- maxUpdatesPerHour: 1\n  maxRecreationsPerHour: 1\n",
+		"zero limit":            "version: 1\ndefaultMaxUpdatesPerHour: 60\ndefaultMaxRecreationsPerHour: 10\nallowances:\n- resourceType: pods\n  maxUpdatesPerHour: 1\n",
+		"invalid pattern":       "version: 1\ndefaultMaxUpdatesPerHour: 60\ndefaultMaxRecreationsPerHour: 10\nallowances:\n- resourceType: pods\n  namePattern: '('\n  maxUpdatesPerHour: 1\n  maxRecreationsPerHour: 1\n",
+		"unknown field":         "version: 1\ndefaultMaxUpdatesPerHour: 60\ndefaultMaxRecreationsPerHour: 10\nmaxUpdates: 1\n",
+		"unknown version":       "version: 2\n",
+		"unchecked type":        "version: 1\ndefaultMaxUpdatesPerHour: 60\ndefaultMaxRecreationsPerHour: 10\nallowances:\n- resourceType: events\n  maxUpdatesPerHour: 1\n  maxRecreationsPerHour: 1\n",
+	}
+	for name, data := range invalid {
+		if _, err := parseHotResourceLimits([]byte(data)); err == nil {
+			t.Errorf("%v: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package synthetictests
+
+import (
+	_ "embed"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/intervalcreation"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+//go:embed leader_election_allowances.yaml
+var leaderElectionAllowancesYAML []byte
+
+// defaultLeaderElectionAllowances are the allowances checked in with origin.
+var defaultLeaderElectionAllowances = mustParse(parseLeaderElectionAllowances, leaderElectionAllowancesYAML)
+
+const (
+	// leaderChangeRolloutGrace is how long after a rollout a leader change is still attributed to it.  Leases are
+	// usually held for up to two minutes, so the new leader can take over well after the operator stops progressing.
+	leaderChangeRolloutGrace = 5 * time.Minute
+)
+
+// leaseToClusterOperator maps leases whose namespace does not match their clusteroperator.
+var leaseToClusterOperator = map[string]string{
+	"kube-system/kube-controller-manager":                              "kube-controller-manager",
+	"kube-system/kube-scheduler":                                       "kube-scheduler",
+	"openshift-kube-controller-manager/cluster-policy-controller-lock": "kube-controller-manager",
+}
+
+// leaderElectionAllowanceFile is the serialized form of leader_election_allowances.yaml.
+type leaderElectionAllowanceFile struct {
+	versionedDataFile
+	DefaultAllowedLeaderChanges int                                          `json:"defaultAllowedLeaderChanges"`
+	Leases                      map[string]serializedLeaderElectionAllowance `json:"leases,omitempty"`
+}
+
+type serializedLeaderElectionAllowance struct {
+	AllowedLeaderChanges int    `json:"allowedLeaderChanges"`
+	Owner                string `json:"owner,omitempty"`
+}
+
+// leaderElectionAllowances are how many times each lease may change hands outside of a rollout.
+type leaderElectionAllowances struct {
+	defaultAllowedLeaderChanges int
+	// leaseToAllowedLeaderChanges is keyed by namespace/name.
+	leaseToAllowedLeaderChanges map[string]int
+}
+
+func parseLeaderElectionAllowances(data []byte) (leaderElectionAllowances, error) {
+	ret := leaderElectionAllowances{leaseToAllowedLeaderChanges: map[string]int{}}
+
+	file := leaderElectionAllowanceFile{}
+	if err := parseDataFile("leader election allowances", data, &file); err != nil {
+		return ret, err
+	}
+	if file.DefaultAllowedLeaderChanges < 0 {
+		return ret, fmt.Errorf("defaultAllowedLeaderChanges must not be negative")
+	}
+	ret.defaultAllowedLeaderChanges = file.DefaultAllowedLeaderChanges
+
+	for lease, allowance := range file.Leases {
+		if parts := strings.Split(lease, "/"); len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+			return ret, fmt.Errorf("lease %q must be <namespace>/<name>", lease)
+		}
+		if allowance.AllowedLeaderChanges < 0 {
+			return ret, fmt.Errorf("lease %s: allowedLeaderChanges must not be negative", lease)
+		}
+		ret.leaseToAllowedLeaderChanges[lease] = allowance.AllowedLeaderChanges
+	}
+
+	return ret, nil
+}
+
+// allowedLeaderChanges returns the allowance of a lease keyed by namespace/name.
+func (a leaderElectionAllowances) allowedLeaderChanges(lease string) int {
+	if allowed, ok := a.leaseToAllowedLeaderChanges[lease]; ok {
+		return allowed
+	}
+	return a.defaultAllowedLeaderChanges
+}
+
+// clusterOperatorForLease returns the clusteroperator that rolls out the component holding the lease.  Operators
+// hold their leases in openshift-<operator>-operator and operands in openshift-<operator>.
+func clusterOperatorForLease(namespace, name string) string {
+	if operator, ok := leaseToClusterOperator[namespace+"/"+name]; ok {
+		return operator
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(namespace, "openshift-"), "-operator")
+}
+
+// nodeFromLeaseHolder returns the node of a holder identity like <node>_<uuid>, which is what most components use.
+func nodeFromLeaseHolder(holder string) string {
+	i := strings.LastIndex(holder, "_")
+	if i == -1 {
+		return ""
+	}
+	return holder[:i]
+}
+
+func isDuringAny(at time.Time, intervals monitorapi.Intervals, grace time.Duration) bool {
+	for _, interval := range intervals {
+		if !at.Before(interval.From) && !at.After(interval.To.Add(grace)) {
+			return true
+		}
+	}
+	return false
+}
+
+// testLeaderElectionChurn flakes when a lease changes hands more often than allowed while its component was not
+// rolling out.  The rollout of a component is when its clusteroperator was progressing, or when the node of the
+// previous holder was being updated, since draining and rebooting a node moves every leader on it.
+func testLeaderElectionChurn(events monitorapi.Intervals, allowances leaderElectionAllowances) []*junitapi.JUnitTestCase {
+	const testName = "[sig-arch] leader elections should not churn outside of component rollouts"
+
+	beginning, end := events.Span()
+	operatorsProgressing := intervalcreation.IntervalsFromEvents_OperatorProgressing(events, nil, beginning, end)
+	nodeUpdates := intervalcreation.IntervalsFromEvents_MachineConfigNodeUpdates(events, nil, beginning, end)
+
+	leaseToChanges := map[string][]string{}
+	for _, event := range events {
+		if monitorapi.ReasonFrom(event.Message) != monitorapi.LeaseReasonHolderChanged {
+			continue
+		}
+		namespace, name, ok := monitorapi.LeaseFromLocator(event.Locator)
+		if !ok {
+			continue
+		}
+
+		operator := clusterOperatorForLease(namespace, name)
+		if isDuringAny(event.From, operatorsProgressing.Filter(func(interval monitorapi.EventInterval) bool {
+			return interval.Locator == monitorapi.OperatorLocator(operator)
+		}), leaderChangeRolloutGrace) {
+			continue
+		}
+		annotations := monitorapi.AnnotationsFromMessage(event.Message)
+		// the holder released the lease and took it back
+		if annotations["previousHolder"] == annotations["holder"] {
+			continue
+		}
+		if node := nodeFromLeaseHolder(annotations["previousHolder"]); len(node) > 0 {
+			if isDuringAny(event.From, nodeUpdates.Filter(func(interval monitorapi.EventInterval) bool {
+				return interval.Locator == monitorapi.NodeLocator(node)
+			}), leaderChangeRolloutGrace) {
+				continue
+			}
+		}
+
+		key := namespace + "/" + name
+		leaseToChanges[key] = append(leaseToChanges[key], fmt.Sprintf("%s from %s to %s",
+			event.From.UTC().Format(time.RFC3339), annotations["previousHolder"], annotations["holder"]))
+	}
+
+	leases := []string{}
+	for lease := range leaseToChanges {
+		leases = append(leases, lease)
+	}
+	sort.Strings(leases)
+
+	var failures []string
+	for _, lease := range leases {
+		allowed := allowances.allowedLeaderChanges(lease)
+		changes := leaseToChanges[lease]
+		if len(changes) <= allowed {
+			continue
+		}
+		failures = append(failures, fmt.Sprintf("lease %s changed leader %d times outside of a rollout, more than the %d allowed:\n\t%s",
+			lease, len(changes), allowed, strings.Join(changes, "\n\t")))
+	}
+
+	if len(failures) == 0 {
+		return []*junitapi.JUnitTestCase{{Name: testName}}
+	}
+	// only flake for now
+	return []*junitapi.JUnitTestCase{
+		{
+			Name: testName,
+			FailureOutput: &junitapi.FailureOutput{
+				Output: fmt.Sprintf("%d leases changed leader too often\n\n%v", len(failures), strings.Join(failures, "\n")),
+			},
+		},
+		{Name: testName},
+	}
+}
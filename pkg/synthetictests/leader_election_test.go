@@ -0,0 +1,165 @@
+package synthetictests
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func leaseChange(namespace, name, previousHolder, holder string, at time.Time) monitorapi.EventInterval {
+	return monitorapi.EventInterval{
+		Condition: monitorapi.Condition{
+			Level:   monitorapi.Warning,
+			Locator: monitorapi.LeaseLocator(namespace, name),
+			Message: "reason/LeaseHolderChanged holder/" + holder + " previousHolder/" + previousHolder + " transitions/1",
+		},
+		From: at,
+		To:   at,
+	}
+}
+
+const testLeaderElectionAllowancesYAML = `
+version: 1
+defaultAllowedLeaderChanges: 2
+leases:
+  openshift-etcd-operator/openshift-cluster-etcd-operator-lock:
+    allowedLeaderChanges: 10
+`
+
+func Test_testLeaderElectionChurn(t *testing.T) {
+	allowances, err := parseLeaderElectionAllowances([]byte(testLeaderElectionAllowancesYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2022, 7, 5, 17, 0, 0, 0, time.UTC)
+	changes := func(namespace, name string, from time.Time, count int) monitorapi.Intervals {
+		var ret monitorapi.Intervals
+		for i := 0; i < count; i++ {
+			ret = append(ret, leaseChange(namespace, name, "master-0_a", "master-1_b", from.Add(time.Duration(i)*time.Minute)))
+		}
+		return ret
+	}
+
+	tests := []struct {
+		name      string
+		events    monitorapi.Intervals
+		wantFlake bool
+	}{
+		{
+			name:   "within allowance",
+			events: changes("kube-system", "kube-scheduler", start, 2),
+		},
+		{
+			name:      "over allowance",
+			events:    changes("kube-system", "kube-scheduler", start, 3),
+			wantFlake: true,
+		},
+		{
+			name: "during operator rollout",
+			events: append(monitorapi.Intervals{
+				{
+					Condition: monitorapi.Condition{Level: monitorapi.Info, Locator: "clusteroperator/kube-scheduler", Message: "condition/Progressing status/True reason/NodeInstaller changed: "},
+					From:      start,
+					To:        start,
+				},
+				{
+					Condition: monitorapi.Condition{Level: monitorapi.Info, Locator: "clusteroperator/kube-scheduler", Message: "condition/Progressing status/False changed: "},
+					From:      start.Add(2 * time.Minute),
+					To:        start.Add(2 * time.Minute),
+				},
+			}, changes("kube-system", "kube-scheduler", start.Add(time.Minute), 5)...),
+		},
+		{
+			name: "during node update of the previous holder",
+			events: append(monitorapi.Intervals{
+				{
+					Condition: monitorapi.Condition{Level: monitorapi.Info, Locator: "node/master-0", Message: "reason/MachineConfigState state/Working config/rendered-master-2 roles/master"},
+					From:      start,
+					To:        start,
+				},
+				{
+					Condition: monitorapi.Condition{Level: monitorapi.Info, Locator: "node/master-0", Message: "reason/MachineConfigState state/Done config/rendered-master-2 roles/master"},
+					From:      start.Add(10 * time.Minute),
+					To:        start.Add(10 * time.Minute),
+				},
+			}, changes("openshift-etcd-operator", "openshift-cluster-etcd-operator-lock", start.Add(time.Minute), 5)...),
+		},
+		{
+			name:   "lease allowance",
+			events: changes("openshift-etcd-operator", "openshift-cluster-etcd-operator-lock", start, 5),
+		},
+		{
+			name: "reacquired by the same holder",
+			events: monitorapi.Intervals{
+				leaseChange("kube-system", "kube-scheduler", "master-0_a", "master-0_a", start),
+				leaseChange("kube-system", "kube-scheduler", "master-0_a", "master-0_a", start.Add(time.Minute)),
+				leaseChange("kube-system", "kube-scheduler", "master-0_a", "master-0_a", start.Add(2*time.Minute)),
+			},
+		},
+		{
+			name: "rollout of another operator",
+			events: append(monitorapi.Intervals{
+				{
+					Condition: monitorapi.Condition{Level: monitorapi.Info, Locator: "clusteroperator/network", Message: "condition/Progressing status/True reason/Deploying changed: "},
+					From:      start,
+					To:        start,
+				},
+				{
+					Condition: monitorapi.Condition{Level: monitorapi.Info, Locator: "clusteroperator/network", Message: "condition/Progressing status/False changed: "},
+					From:      start.Add(10 * time.Minute),
+					To:        start.Add(10 * time.Minute),
+				},
+			}, changes("kube-system", "kube-controller-manager", start.Add(time.Minute), 5)...),
+			wantFlake: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			junits := testLeaderElectionChurn(tt.events, allowances)
+			var failures int
+			for _, junit := range junits {
+				if junit.FailureOutput != nil {
+					failures++
+				}
+			}
+			if flaked := failures > 0; flaked != tt.wantFlake {
+				t.Errorf("expected flake %v, got %v", tt.wantFlake, junits)
+			}
+			if passes := len(junits) - failures; passes != 1 {
+				t.Errorf("expected a passing result, got %d", passes)
+			}
+		})
+	}
+}
+
+func Test_clusterOperatorForLease(t *testing.T) {
+	tests := map[string]string{
+		"kube-system/kube-controller-manager":                                    "kube-controller-manager",
+		"openshift-etcd-operator/openshift-cluster-etcd-lock":                    "etcd",
+		"openshift-authentication-operator/cluster-authentication-operator-lock": "authentication",
+		"openshift-kube-scheduler/kube-scheduler":                                "kube-scheduler",
+	}
+	for lease, expected := range tests {
+		parts := strings.SplitN(lease, "/", 2)
+		if actual := clusterOperatorForLease(parts[0], parts[1]); actual != expected {
+			t.Errorf("%s: expected %q, got %q", lease, expected, actual)
+		}
+	}
+}
+
+func Test_parseLeaderElectionAllowances(t *testing.T) {
+	invalid := map[string]string{
+		"negative default":   "version: 1\ndefaultAllowedLeaderChanges: -1\n",
+		"negative allowance": "version: 1\ndefaultAllowedLeaderChanges: 2\nleases:\n  kube-system/kube-scheduler:\n    allowedLeaderChanges: -1\n",
+		"missing namespace":  "version: 1\ndefaultAllowedLeaderChanges: 2\nleases:\n  kube-scheduler:\n    allowedLeaderChanges: 1\n",
+		"unknown field":      "version: 1\ndefaultAllowedLeaderChanges: 2\nallowed: 1\n",
+		"unknown version":    "version: 2\n",
+	}
+	for name, data := range invalid {
+		if _, err := parseLeaderElectionAllowances([]byte(data)); err == nil {
+			t.Errorf("%v: expected an error", name)
+		}
+	}
+}
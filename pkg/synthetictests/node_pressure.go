@@ -0,0 +1,129 @@
+package synthetictests
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/intervalcreation"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+// nodePressureIntervals calculates the resource pressure intervals of every node over the span of the events.
+func nodePressureIntervals(events monitorapi.Intervals) monitorapi.Intervals {
+	if len(events) == 0 {
+		return nil
+	}
+	beginning, end := events.Span()
+	return intervalcreation.IntervalsFromEvents_NodePressure(events, nil, beginning, end)
+}
+
+func isControlPlaneRoles(roles string) bool {
+	for _, role := range strings.Split(roles, ",") {
+		if role == "master" || role == "control-plane" {
+			return true
+		}
+	}
+	return false
+}
+
+// testControlPlaneNodePressure fails if any control plane node reported memory, disk or PID pressure.  The kubelet
+// evicts pods under pressure, and on a control plane node that includes the operators and their operands.
+func testControlPlaneNodePressure(events monitorapi.Intervals) []*junitapi.JUnitTestCase {
+	const testName = "[sig-node] control plane nodes should not report resource pressure"
+
+	var failures []string
+	for _, interval := range nodePressureIntervals(events) {
+		if !isControlPlaneRoles(monitorapi.GetNodeRoles(interval)) {
+			continue
+		}
+		failures = append(failures, interval.String())
+	}
+
+	if len(failures) == 0 {
+		return []*junitapi.JUnitTestCase{{Name: testName}}
+	}
+	return []*junitapi.JUnitTestCase{
+		{
+			Name: testName,
+			FailureOutput: &junitapi.FailureOutput{
+				Output: fmt.Sprintf("%d resource pressure conditions on control plane nodes\n\n%v", len(failures), strings.Join(failures, "\n")),
+			},
+		},
+	}
+}
+
+// podsEvictedDuringNodePressure returns the pods that were evicted from a node while it reported resource pressure.
+// Kube events for evictions do not name the node, so the node of each pod is taken from the intervals the monitor
+// recorded for it.
+func podsEvictedDuringNodePressure(events monitorapi.Intervals) []string {
+	nodeToPressure := map[string]monitorapi.Intervals{}
+	for _, interval := range nodePressureIntervals(events) {
+		node, _ := monitorapi.NodeFromLocator(interval.Locator)
+		nodeToPressure[node] = append(nodeToPressure[node], interval)
+	}
+	if len(nodeToPressure) == 0 {
+		return nil
+	}
+
+	podToNode := map[string]string{}
+	for _, event := range events {
+		parts := monitorapi.LocatorParts(event.Locator)
+		if pod, node := parts["pod"], parts["node"]; len(pod) > 0 && len(node) > 0 {
+			podToNode[monitorapi.NamespaceFrom(parts)+"/"+pod] = node
+		}
+	}
+
+	podToMessage := map[string]string{}
+	for _, event := range events {
+		if monitorapi.ReasonFrom(event.Message) != "Evicted" {
+			continue
+		}
+		parts := monitorapi.LocatorParts(event.Locator)
+		if len(parts["pod"]) == 0 {
+			continue
+		}
+		pod := monitorapi.NamespaceFrom(parts) + "/" + parts["pod"]
+		if _, ok := podToMessage[pod]; ok {
+			continue
+		}
+		node := podToNode[pod]
+		for _, pressure := range nodeToPressure[node] {
+			// the pod status is updated after the kubelet has reclaimed the resources, so allow a little slack
+			if event.From.Before(pressure.From) || event.From.After(pressure.To.Add(time.Minute)) {
+				continue
+			}
+			podToMessage[pod] = fmt.Sprintf("pod %s was evicted from node/%s at %s during %s: %s",
+				pod, node, event.From.UTC().Format(time.RFC3339), monitorapi.AnnotationsFromMessage(pressure.Message)["condition"], event.Message)
+			break
+		}
+	}
+
+	messages := []string{}
+	for _, message := range podToMessage {
+		messages = append(messages, message)
+	}
+	sort.Strings(messages)
+	return messages
+}
+
+// testPodsEvictedDuringNodePressure flags the pods evicted because a node was under resource pressure.  Tests are
+// free to evict pods through the API, so this only flakes.
+func testPodsEvictedDuringNodePressure(events monitorapi.Intervals) []*junitapi.JUnitTestCase {
+	const testName = "[sig-node] pods should not be evicted by node resource pressure"
+
+	messages := podsEvictedDuringNodePressure(events)
+	tests := []*junitapi.JUnitTestCase{}
+	if len(messages) > 0 {
+		tests = append(tests, &junitapi.JUnitTestCase{
+			Name: testName,
+			FailureOutput: &junitapi.FailureOutput{
+				Output: fmt.Sprintf("%d pods were evicted during node resource pressure\n\n%v", len(messages), strings.Join(messages, "\n")),
+			},
+		})
+	}
+	tests = append(tests, &junitapi.JUnitTestCase{Name: testName})
+	return tests
+}
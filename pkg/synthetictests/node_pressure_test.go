@@ -0,0 +1,87 @@
+package synthetictests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+func Test_testControlPlaneNodePressure(t *testing.T) {
+	start := time.Date(2022, 7, 5, 17, 0, 0, 0, time.UTC)
+	event := func(locator, message string, at time.Time) monitorapi.EventInterval {
+		return monitorapi.EventInterval{
+			Condition: monitorapi.Condition{Level: monitorapi.Warning, Locator: locator, Message: message},
+			From:      at,
+			To:        at,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		events   monitorapi.Intervals
+		wantFail bool
+	}{
+		{
+			name: "worker pressure",
+			events: monitorapi.Intervals{
+				event("node/worker-0", "condition/DiskPressure status/True reason/KubeletHasDiskPressure roles/worker changed", start),
+			},
+		},
+		{
+			name: "control plane pressure",
+			events: monitorapi.Intervals{
+				event("node/master-0", "condition/MemoryPressure status/True reason/KubeletHasInsufficientMemory roles/master changed", start),
+				event("node/master-0", "condition/MemoryPressure status/False reason/KubeletHasSufficientMemory roles/master changed", start.Add(time.Minute)),
+			},
+			wantFail: true,
+		},
+		{
+			name: "control plane without pressure",
+			events: monitorapi.Intervals{
+				event("node/master-0", "condition/Ready status/False reason/KubeletNotReady roles/master changed", start),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			junits := testControlPlaneNodePressure(tt.events)
+			if len(junits) != 1 {
+				t.Fatalf("expected a single result, got %d", len(junits))
+			}
+			if failed := junits[0].FailureOutput != nil; failed != tt.wantFail {
+				t.Errorf("expected failure %v, got %v", tt.wantFail, junits[0].FailureOutput)
+			}
+		})
+	}
+}
+
+func Test_podsEvictedDuringNodePressure(t *testing.T) {
+	start := time.Date(2022, 7, 5, 17, 0, 0, 0, time.UTC)
+	event := func(locator, message string, at time.Time) monitorapi.EventInterval {
+		return monitorapi.EventInterval{
+			Condition: monitorapi.Condition{Level: monitorapi.Warning, Locator: locator, Message: message},
+			From:      at,
+			To:        at,
+		}
+	}
+	events := monitorapi.Intervals{
+		event("ns/e2e-1 pod/hog node/worker-0 uid/1", "reason/Created ", start),
+		event("ns/e2e-1 pod/evicted-by-api node/worker-1 uid/2", "reason/Created ", start),
+		event("node/worker-0", "condition/MemoryPressure status/True reason/KubeletHasInsufficientMemory roles/worker changed", start.Add(time.Minute)),
+		// the kube event does not name the node
+		event("ns/e2e-1 pod/hog", "reason/Evicted The node was low on resource: memory.", start.Add(2*time.Minute)),
+		event("ns/e2e-1 pod/hog node/worker-0 uid/1", "reason/Evicted The node was low on resource: memory.", start.Add(2*time.Minute)),
+		event("ns/e2e-1 pod/evicted-by-api node/worker-1 uid/2", "reason/Evicted ", start.Add(2*time.Minute)),
+		event("node/worker-0", "condition/MemoryPressure status/False reason/KubeletHasSufficientMemory roles/worker changed", start.Add(3*time.Minute)),
+	}
+
+	actual := podsEvictedDuringNodePressure(events)
+	if len(actual) != 1 {
+		t.Fatalf("expected a single evicted pod, got %v", actual)
+	}
+	expected := "pod e2e-1/hog was evicted from node/worker-0 at 2022-07-05T17:02:00Z during MemoryPressure: reason/Evicted The node was low on resource: memory."
+	if actual[0] != expected {
+		t.Errorf("expected %q, got %q", expected, actual[0])
+	}
+}
@@ -0,0 +1,185 @@
+package synthetictests
+
+import (
+	_ "embed"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+//go:embed operator_condition_reasons.yaml
+var operatorConditionReasonsYAML []byte
+
+// defaultOperatorConditionReasons are the classifications checked in with origin.
+var defaultOperatorConditionReasons = mustParse(parseOperatorConditionReasons, operatorConditionReasonsYAML)
+
+type operatorConditionClassification string
+
+const (
+	// operatorConditionExpectedDuringUpgrade transitions are caused by the rollout itself.
+	operatorConditionExpectedDuringUpgrade operatorConditionClassification = "ExpectedDuringUpgrade"
+	// operatorConditionKnownBug transitions are caused by a bug.  They flake instead of failing.
+	operatorConditionKnownBug operatorConditionClassification = "KnownBug"
+	// operatorConditionFail transitions are outages, even during upgrades.  They only flake for now.
+	operatorConditionFail operatorConditionClassification = "Fail"
+
+	// allOperatorsKey is the key of the entries that apply to every operator.
+	allOperatorsKey = "*"
+)
+
+// operatorConditionReasonFile is the serialized form of operator_condition_reasons.yaml.
+type operatorConditionReasonFile struct {
+	versionedDataFile
+	Operators map[string][]serializedOperatorConditionReason `json:"operators"`
+}
+
+type serializedOperatorConditionReason struct {
+	Condition      configv1.ClusterStatusConditionType `json:"condition,omitempty"`
+	Reason         string                              `json:"reason"`
+	MessagePattern string                              `json:"messagePattern,omitempty"`
+	Classification operatorConditionClassification     `json:"classification"`
+	BugURL         string                              `json:"bugURL,omitempty"`
+	Owner          string                              `json:"owner,omitempty"`
+}
+
+type operatorConditionReason struct {
+	condition      configv1.ClusterStatusConditionType
+	reason         string
+	messageRegexp  *regexp.Regexp
+	classification operatorConditionClassification
+	bugURL         string
+	owner          string
+}
+
+// operatorConditionReasons are the parsed classifications by operator name.
+type operatorConditionReasons map[string][]operatorConditionReason
+
+func parseOperatorConditionReasons(data []byte) (operatorConditionReasons, error) {
+	ret := operatorConditionReasons{}
+
+	file := operatorConditionReasonFile{}
+	if err := parseDataFile("operator condition reasons", data, &file); err != nil {
+		return ret, err
+	}
+
+	for operatorName, reasons := range file.Operators {
+		for i, reason := range reasons {
+			if len(reason.Reason) == 0 {
+				return ret, fmt.Errorf("%s entry %d: reason is required", operatorName, i)
+			}
+			switch reason.Condition {
+			case "", configv1.OperatorAvailable, configv1.OperatorDegraded:
+			default:
+				return ret, fmt.Errorf("%s entry %d: condition must be %s or %s, not %q", operatorName, i, configv1.OperatorAvailable, configv1.OperatorDegraded, reason.Condition)
+			}
+			switch reason.Classification {
+			case operatorConditionExpectedDuringUpgrade, operatorConditionKnownBug, operatorConditionFail:
+			default:
+				return ret, fmt.Errorf("%s entry %d: classification must be %s, %s or %s, not %q", operatorName, i,
+					operatorConditionExpectedDuringUpgrade, operatorConditionKnownBug, operatorConditionFail, reason.Classification)
+			}
+			if reason.Classification == operatorConditionKnownBug && len(reason.BugURL) == 0 {
+				return ret, fmt.Errorf("%s entry %d: bugURL is required for %s", operatorName, i, operatorConditionKnownBug)
+			}
+
+			curr := operatorConditionReason{
+				condition:      reason.Condition,
+				reason:         reason.Reason,
+				classification: reason.Classification,
+				bugURL:         reason.BugURL,
+				owner:          reason.Owner,
+			}
+			if len(reason.MessagePattern) > 0 {
+				re, err := regexp.Compile(reason.MessagePattern)
+				if err != nil {
+					return ret, fmt.Errorf("%s entry %d: invalid messagePattern: %w", operatorName, i, err)
+				}
+				curr.messageRegexp = re
+			}
+			ret[operatorName] = append(ret[operatorName], curr)
+		}
+	}
+
+	return ret, nil
+}
+
+// classify returns the first entry of the operator, then of every operator, matching the condition.  Nil means the
+// transition is not classified.
+func (r operatorConditionReasons) classify(operatorName string, condition *configv1.ClusterOperatorStatusCondition) *operatorConditionReason {
+	if condition == nil {
+		return nil
+	}
+	for _, key := range []string{operatorName, allOperatorsKey} {
+		for i := range r[key] {
+			curr := &r[key][i]
+			if len(curr.condition) > 0 && curr.condition != condition.Type {
+				continue
+			}
+			if curr.reason != condition.Reason {
+				continue
+			}
+			if curr.messageRegexp != nil && !curr.messageRegexp.MatchString(condition.Message) {
+				continue
+			}
+			return curr
+		}
+	}
+	return nil
+}
+
+func (r *operatorConditionReason) String() string {
+	if r == nil {
+		return "unclassified"
+	}
+	details := []string{string(r.classification)}
+	if len(r.owner) > 0 {
+		details = append(details, "owner "+r.owner)
+	}
+	if len(r.bugURL) > 0 {
+		details = append(details, r.bugURL)
+	}
+	return strings.Join(details, ", ")
+}
+
+// operatorReasonTime is the time an operator spent out of its good state for a single reason.
+type operatorReasonTime struct {
+	reason         string
+	classification string
+	duration       time.Duration
+	transitions    int
+}
+
+// summarizeOperatorReasonTimes lists the time in each reason, longest first.
+func summarizeOperatorReasonTimes(reasonTimes map[string]*operatorReasonTime) string {
+	if len(reasonTimes) == 0 {
+		return ""
+	}
+	sorted := []*operatorReasonTime{}
+	for _, reasonTime := range reasonTimes {
+		sorted = append(sorted, reasonTime)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].duration != sorted[j].duration {
+			return sorted[i].duration > sorted[j].duration
+		}
+		if sorted[i].reason != sorted[j].reason {
+			return sorted[i].reason < sorted[j].reason
+		}
+		return sorted[i].classification < sorted[j].classification
+	})
+
+	lines := []string{"time spent in each reason:"}
+	for _, reasonTime := range sorted {
+		transitions := "transitions"
+		if reasonTime.transitions == 1 {
+			transitions = "transition"
+		}
+		lines = append(lines, fmt.Sprintf("  %s (%s): %s over %d %s",
+			reasonTime.reason, reasonTime.classification, reasonTime.duration.Round(time.Second), reasonTime.transitions, transitions))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -19,13 +19,17 @@ import (
 )
 
 func testStableSystemOperatorStateTransitions(events monitorapi.Intervals) []*junitapi.JUnitTestCase {
-	return testOperatorStateTransitions(events, []configv1.ClusterStatusConditionType{configv1.OperatorAvailable, configv1.OperatorDegraded})
+	return testOperatorStateTransitions(events, []configv1.ClusterStatusConditionType{configv1.OperatorAvailable, configv1.OperatorDegraded}, defaultOperatorConditionReasons, false)
 }
 
 func testUpgradeOperatorStateTransitions(events monitorapi.Intervals) []*junitapi.JUnitTestCase {
-	return testOperatorStateTransitions(events, []configv1.ClusterStatusConditionType{configv1.OperatorAvailable, configv1.OperatorDegraded})
+	return testOperatorStateTransitions(events, []configv1.ClusterStatusConditionType{configv1.OperatorAvailable, configv1.OperatorDegraded}, defaultOperatorConditionReasons, true)
 }
-func testOperatorStateTransitions(events monitorapi.Intervals, conditionTypes []configv1.ClusterStatusConditionType) []*junitapi.JUnitTestCase {
+
+// testOperatorStateTransitions classifies every time an operator left its good state by the reason of the condition.
+// ExpectedDuringUpgrade transitions are ignored during upgrades, and all of the others flake.  Transitions classified
+// Fail are listed separately, but only flake until the classifications have been confirmed.
+func testOperatorStateTransitions(events monitorapi.Intervals, conditionTypes []configv1.ClusterStatusConditionType, reasons operatorConditionReasons, upgrade bool) []*junitapi.JUnitTestCase {
 	ret := []*junitapi.JUnitTestCase{}
 
 	var start, stop time.Time
@@ -58,19 +62,29 @@ func testOperatorStateTransitions(events monitorapi.Intervals, conditionTypes []
 				continue
 			}
 
-			failures := testOperatorState(condition, operatorEvents, e2eEventIntervals)
-			if len(failures) > 0 {
+			transitions := testOperatorState(condition, operatorName, operatorEvents, e2eEventIntervals, reasons, upgrade)
+			summary := summarizeOperatorReasonTimes(transitions.reasonTimes)
+			if len(transitions.failures) > 0 {
+				ret = append(ret, &junitapi.JUnitTestCase{
+					Name:      testName,
+					Duration:  duration,
+					SystemOut: summary + "\n\n" + strings.Join(transitions.failures, "\n"),
+					FailureOutput: &junitapi.FailureOutput{
+						Output: fmt.Sprintf("%d clusteroperator state transitions for reasons that must not happen during e2e test run \n\n%v\n\n%v", len(transitions.failures), strings.Join(transitions.failures, "\n"), summary),
+					},
+				})
+			} else if len(transitions.flakes) > 0 {
 				ret = append(ret, &junitapi.JUnitTestCase{
 					Name:      testName,
 					Duration:  duration,
-					SystemOut: strings.Join(failures, "\n"),
+					SystemOut: summary + "\n\n" + strings.Join(transitions.flakes, "\n"),
 					FailureOutput: &junitapi.FailureOutput{
-						Output: fmt.Sprintf("%d unexpected clusteroperator state transitions during e2e test run \n\n%v", len(failures), strings.Join(failures, "\n")),
+						Output: fmt.Sprintf("%d unexpected clusteroperator state transitions during e2e test run \n\n%v\n\n%v", len(transitions.flakes), strings.Join(transitions.flakes, "\n"), summary),
 					},
 				})
 			}
-			// always add a success so we flake and not fail
-			ret = append(ret, &junitapi.JUnitTestCase{Name: testName})
+			// add a success so we flake and not fail
+			ret = append(ret, &junitapi.JUnitTestCase{Name: testName, SystemOut: summary})
 		}
 	}
 
@@ -286,8 +300,19 @@ func getEventsByOperator(events monitorapi.Intervals) map[string]monitorapi.Inte
 	return eventsByClusterOperator
 }
 
-func testOperatorState(interestingCondition configv1.ClusterStatusConditionType, eventIntervals monitorapi.Intervals, e2eEventIntervals monitorapi.Intervals) []string {
-	failures := []string{}
+// operatorStateTransitions are the times an operator left its good state, split by how they are reported.
+type operatorStateTransitions struct {
+	// failures are classified Fail.
+	failures []string
+	// flakes are known bugs, unclassified, or expected only during upgrades on a stable system.
+	flakes []string
+	// reasonTimes aggregates every transition by the reason of the condition and its classification, including the
+	// ignored ones.
+	reasonTimes map[string]*operatorReasonTime
+}
+
+func testOperatorState(interestingCondition configv1.ClusterStatusConditionType, operatorName string, eventIntervals monitorapi.Intervals, e2eEventIntervals monitorapi.Intervals, reasons operatorConditionReasons, upgrade bool) operatorStateTransitions {
+	ret := operatorStateTransitions{reasonTimes: map[string]*operatorReasonTime{}}
 
 	for _, eventInterval := range eventIntervals {
 		// ignore non-interval eventInterval intervals
@@ -298,8 +323,35 @@ func testOperatorState(interestingCondition configv1.ClusterStatusConditionType,
 			continue
 		}
 
+		// the calculated interval only keeps the message, so read the reason from the change that started it
+		condition := operatorConditionChangeAt(eventIntervals, interestingCondition, eventInterval.From)
+		classification := reasons.classify(operatorName, condition)
+		reason := "Unknown"
+		if condition != nil && len(condition.Reason) > 0 {
+			reason = condition.Reason
+		}
+		// the message pattern can classify the same reason differently
+		reasonKey := reason + " " + classification.String()
+		if _, ok := ret.reasonTimes[reasonKey]; !ok {
+			ret.reasonTimes[reasonKey] = &operatorReasonTime{reason: reason, classification: classification.String()}
+		}
+		ret.reasonTimes[reasonKey].duration += eventInterval.To.Sub(eventInterval.From)
+		ret.reasonTimes[reasonKey].transitions++
+
+		var output *[]string
+		switch {
+		case classification == nil:
+			output = &ret.flakes
+		case classification.classification == operatorConditionFail:
+			output = &ret.failures
+		case classification.classification == operatorConditionExpectedDuringUpgrade && upgrade:
+			continue
+		default:
+			output = &ret.flakes
+		}
+
 		// if there was any switch, it was wrong/unexpected at some point
-		failures = append(failures, fmt.Sprintf("%v", eventInterval))
+		*output = append(*output, fmt.Sprintf("%v (%v)", eventInterval, classification.String()))
 
 		overlappingE2EIntervals := monitor.FindOverlap(e2eEventIntervals, eventInterval.From, eventInterval.From)
 		concurrentE2E := []string{}
@@ -315,8 +367,24 @@ func testOperatorState(interestingCondition configv1.ClusterStatusConditionType,
 		}
 
 		if len(concurrentE2E) > 0 {
-			failures = append(failures, fmt.Sprintf("%d tests failed during this blip (%v to %v): %v", len(concurrentE2E), eventInterval.From, eventInterval.From, strings.Join(concurrentE2E, "\n")))
+			*output = append(*output, fmt.Sprintf("%d tests failed during this blip (%v to %v): %v", len(concurrentE2E), eventInterval.From, eventInterval.From, strings.Join(concurrentE2E, "\n")))
+		}
+	}
+	return ret
+}
+
+// operatorConditionChangeAt returns the condition an operator changed to at the given time, or nil when the change was
+// not observed, like when the operator was already out of its good state before the monitor started.
+func operatorConditionChangeAt(eventIntervals monitorapi.Intervals, conditionType configv1.ClusterStatusConditionType, at time.Time) *configv1.ClusterOperatorStatusCondition {
+	for _, eventInterval := range eventIntervals {
+		if eventInterval.From != eventInterval.To || !eventInterval.From.Equal(at) {
+			continue
+		}
+		condition := monitorapi.GetOperatorConditionStatus(eventInterval.Message)
+		if condition == nil || condition.Type != conditionType {
+			continue
 		}
+		return condition
 	}
-	return failures
+	return nil
 }
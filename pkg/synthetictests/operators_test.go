@@ -0,0 +1,179 @@
+package synthetictests
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/origin/pkg/monitor/intervalcreation"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+const testOperatorConditionReasonsYAML = `
+version: 1
+operators:
+  "*":
+  - condition: Available
+    reason: NoReplicasAvailable
+    classification: Fail
+  etcd:
+  - condition: Degraded
+    reason: EtcdMembers_UnhealthyMembers
+    classification: ExpectedDuringUpgrade
+  dns:
+  - reason: DNSDegraded
+    messagePattern: 'is degraded'
+    classification: KnownBug
+    bugURL: https://bugzilla.redhat.com/show_bug.cgi?id=1234567
+`
+
+func operatorConditionChange(operatorName, message string, at time.Time) monitorapi.EventInterval {
+	return monitorapi.EventInterval{
+		Condition: monitorapi.Condition{
+			Level:   monitorapi.Warning,
+			Locator: monitorapi.OperatorLocator(operatorName),
+			Message: message,
+		},
+		From: at,
+		To:   at,
+	}
+}
+
+func Test_testOperatorStateTransitions(t *testing.T) {
+	reasons, err := parseOperatorConditionReasons([]byte(testOperatorConditionReasonsYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2022, 7, 5, 17, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	// the calculated intervals are created from the changes before the synthetic tests run
+	withCalculatedIntervals := func(changes ...monitorapi.EventInterval) monitorapi.Intervals {
+		events := monitorapi.Intervals(changes)
+		events = append(events, intervalcreation.IntervalsFromEvents_OperatorAvailable(changes, nil, start, end)...)
+		events = append(events, intervalcreation.IntervalsFromEvents_OperatorDegraded(changes, nil, start, end)...)
+		return events
+	}
+
+	tests := []struct {
+		name        string
+		events      monitorapi.Intervals
+		upgrade     bool
+		testName    string
+		want        string
+		wantSummary string
+	}{
+		{
+			name: "outage only flakes until the classifications are confirmed",
+			events: withCalculatedIntervals(
+				operatorConditionChange("image-registry", "condition/Available status/False reason/NoReplicasAvailable changed: The deployment does not have available replicas", start),
+				operatorConditionChange("image-registry", "condition/Available status/True reason/Ready changed: The registry is ready", start.Add(2*time.Minute)),
+			),
+			upgrade:     true,
+			testName:    "[bz-Image Registry] clusteroperator/image-registry should not change condition/Available",
+			want:        "flake",
+			wantSummary: "NoReplicasAvailable (Fail): 2m0s over 1 transition",
+		},
+		{
+			name: "expected during upgrade passes",
+			events: withCalculatedIntervals(
+				operatorConditionChange("etcd", "condition/Degraded status/True reason/EtcdMembers_UnhealthyMembers changed: EtcdMembersDegraded: 2 of 3 members are available", start),
+				operatorConditionChange("etcd", "condition/Degraded status/False reason/AsExpected changed: NodeControllerDegraded: All master nodes are ready", start.Add(time.Minute)),
+				operatorConditionChange("etcd", "condition/Degraded status/True reason/EtcdMembers_UnhealthyMembers changed: EtcdMembersDegraded: 2 of 3 members are available", start.Add(10*time.Minute)),
+				operatorConditionChange("etcd", "condition/Degraded status/False reason/AsExpected changed: NodeControllerDegraded: All master nodes are ready", start.Add(12*time.Minute)),
+			),
+			upgrade:     true,
+			testName:    "[bz-Etcd] clusteroperator/etcd should not change condition/Degraded",
+			want:        "pass",
+			wantSummary: "EtcdMembers_UnhealthyMembers (ExpectedDuringUpgrade): 3m0s over 2 transitions",
+		},
+		{
+			name: "expected during upgrade flakes on a stable system",
+			events: withCalculatedIntervals(
+				operatorConditionChange("etcd", "condition/Degraded status/True reason/EtcdMembers_UnhealthyMembers changed: EtcdMembersDegraded: 2 of 3 members are available", start),
+				operatorConditionChange("etcd", "condition/Degraded status/False reason/AsExpected changed: NodeControllerDegraded: All master nodes are ready", start.Add(time.Minute)),
+			),
+			testName:    "[bz-Etcd] clusteroperator/etcd should not change condition/Degraded",
+			want:        "flake",
+			wantSummary: "EtcdMembers_UnhealthyMembers (ExpectedDuringUpgrade): 1m0s over 1 transition",
+		},
+		{
+			name: "message must match",
+			events: withCalculatedIntervals(
+				operatorConditionChange("dns", "condition/Degraded status/True reason/DNSDegraded changed: DNS default is degraded", start),
+				operatorConditionChange("dns", "condition/Degraded status/False reason/AsExpected changed: ", start.Add(time.Minute)),
+				operatorConditionChange("dns", "condition/Degraded status/True reason/DNSDegraded changed: DNS default is unavailable", start.Add(5*time.Minute)),
+				operatorConditionChange("dns", "condition/Degraded status/False reason/AsExpected changed: ", start.Add(6*time.Minute)),
+			),
+			upgrade:     true,
+			testName:    "[bz-DNS] clusteroperator/dns should not change condition/Degraded",
+			want:        "flake",
+			wantSummary: "DNSDegraded (KnownBug, https://bugzilla.redhat.com/show_bug.cgi?id=1234567): 1m0s over 1 transition\n  DNSDegraded (unclassified)",
+		},
+		{
+			name: "unobserved change is unclassified",
+			events: withCalculatedIntervals(
+				operatorConditionChange("image-registry", "condition/Available status/True reason/Ready changed: The registry is ready", start.Add(time.Minute)),
+			),
+			upgrade:     true,
+			testName:    "[bz-Image Registry] clusteroperator/image-registry should not change condition/Available",
+			want:        "flake",
+			wantSummary: "Unknown (unclassified): 1m0s over 1 transition",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			junits := testOperatorStateTransitions(tt.events, []configv1.ClusterStatusConditionType{configv1.OperatorAvailable, configv1.OperatorDegraded}, reasons, tt.upgrade)
+			var failed, passed []*junitapi.JUnitTestCase
+			for _, junit := range junits {
+				if junit.Name != tt.testName {
+					continue
+				}
+				if junit.FailureOutput != nil {
+					failed = append(failed, junit)
+				} else {
+					passed = append(passed, junit)
+				}
+			}
+
+			got := ""
+			switch {
+			case len(failed) > 0 && len(passed) > 0:
+				got = "flake"
+			case len(failed) > 0:
+				got = "fail"
+			case len(passed) > 0:
+				got = "pass"
+			default:
+				t.Fatalf("missing %v", tt.testName)
+			}
+			if got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+			for _, junit := range append(failed, passed...) {
+				if !strings.Contains(junit.SystemOut, tt.wantSummary) {
+					t.Errorf("expected the output to contain %q, got %v", tt.wantSummary, junit.SystemOut)
+				}
+			}
+		})
+	}
+}
+
+func Test_parseOperatorConditionReasons(t *testing.T) {
+	invalid := map[string]string{
+		"missing reason":          "version: 1\noperators:\n  dns:\n  - classification: Fail\n",
+		"unknown classification":  "version: 1\noperators:\n  dns:\n  - reason: DNSDegraded\n    classification: Ignore\n",
+		"unknown condition":       "version: 1\noperators:\n  dns:\n  - condition: Progressing\n    reason: DNSDegraded\n    classification: Fail\n",
+		"invalid pattern":         "version: 1\noperators:\n  dns:\n  - reason: DNSDegraded\n    messagePattern: '('\n    classification: Fail\n",
+		"unknown field":           "version: 1\noperators:\n  dns:\n  - reason: DNSDegraded\n    classification: Fail\n    bug: 1\n",
+		"known bug without a bug": "version: 1\noperators:\n  dns:\n  - reason: DNSDegraded\n    classification: KnownBug\n",
+		"unknown version":         "version: 2\noperators: {}\n",
+	}
+	for name, data := range invalid {
+		if _, err := parseOperatorConditionReasons([]byte(data)); err == nil {
+			t.Errorf("%v: expected an error", name)
+		}
+	}
+}
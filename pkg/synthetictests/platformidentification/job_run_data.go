@@ -0,0 +1,41 @@
+package platformidentification
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+)
+
+const (
+	// JobTypeFilePrefix starts the name of the file holding the job type of a run, like job-type_<timeSuffix>.json.
+	JobTypeFilePrefix = "job-type"
+)
+
+// WriteJobTypeForJobRun writes the job type next to the other data of the run, so that the data can be attributed to
+// the job type without access to the cluster.
+func WriteJobTypeForJobRun(artifactDir string, jobType *JobType, timeSuffix string) error {
+	jsonContent, err := json.MarshalIndent(jobType, "", "    ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(JobTypeFilename(artifactDir, timeSuffix), jsonContent, 0644)
+}
+
+// JobTypeFilename is the file WriteJobTypeForJobRun writes the job type of the run with the time suffix to.
+func JobTypeFilename(artifactDir, timeSuffix string) string {
+	return filepath.Join(artifactDir, fmt.Sprintf("%s%s.json", JobTypeFilePrefix, timeSuffix))
+}
+
+// ReadJobType reads a job type written by WriteJobTypeForJobRun.
+func ReadJobType(filename string) (*JobType, error) {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	jobType := &JobType{}
+	if err := json.Unmarshal(content, jobType); err != nil {
+		return nil, fmt.Errorf("unable to read job type from %v: %w", filename, err)
+	}
+	return jobType, nil
+}
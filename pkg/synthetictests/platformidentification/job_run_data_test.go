@@ -0,0 +1,30 @@
+package platformidentification
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWriteJobTypeForJobRun(t *testing.T) {
+	artifactDir := t.TempDir()
+	if _, err := ReadJobType(JobTypeFilename(artifactDir, "_20220705-170000")); err == nil {
+		t.Errorf("expected an error without a job type")
+	}
+
+	previous := &JobType{Release: "4.11", Platform: "aws", Architecture: "amd64", Network: "ovn", Topology: "ha"}
+	current := &JobType{Release: "4.12", FromRelease: "4.11", Platform: "aws", Architecture: "amd64", Network: "ovn", Topology: "ha"}
+	if err := WriteJobTypeForJobRun(artifactDir, current, "_20220705-170000"); err != nil {
+		t.Fatal(err)
+	}
+	// a later run writing to the same directory does not change the job type of this run
+	if err := WriteJobTypeForJobRun(artifactDir, previous, "_20220706-170000"); err != nil {
+		t.Fatal(err)
+	}
+	actual, err := ReadJobType(JobTypeFilename(artifactDir, "_20220705-170000"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, current) {
+		t.Errorf("expected the job type of the run %v, got %v", current, actual)
+	}
+}
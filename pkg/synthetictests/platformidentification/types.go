@@ -3,6 +3,9 @@ package platformidentification
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net"
+	"strconv"
 	"strings"
 
 	configv1 "github.com/openshift/api/config/v1"
@@ -20,6 +23,18 @@ type JobType struct {
 	Architecture string
 	Network      string
 	Topology     string
+
+	// The dimensions below are optional.  They are empty when they could not be determined, and historical data
+	// without them applies to every value.
+
+	// IPFamily is ipv4, ipv6 or dualstack.
+	IPFamily string `json:",omitempty"`
+	// Proxy is true when the cluster reaches the internet through a proxy.
+	Proxy string `json:",omitempty"`
+	// FIPS is true when the cluster was installed in FIPS mode.
+	FIPS string `json:",omitempty"`
+	// InstallerType is ipi, upi, agent or hypershift.
+	InstallerType string `json:",omitempty"`
 }
 
 const (
@@ -27,17 +42,62 @@ const (
 	ArchitectureAMD64   = "amd64"
 	ArchitecturePPC64le = "ppc64le"
 	ArchitectureARM64   = "arm64"
+
+	IPFamilyV4        = "ipv4"
+	IPFamilyV6        = "ipv6"
+	IPFamilyDualStack = "dualstack"
+
+	InstallerTypeIPI        = "ipi"
+	InstallerTypeUPI        = "upi"
+	InstallerTypeAgent      = "agent"
+	InstallerTypeHypershift = "hypershift"
 )
 
 func CloneJobType(in JobType) JobType {
 	return JobType{
-		Release:      in.Release,
-		FromRelease:  in.FromRelease,
-		Platform:     in.Platform,
-		Architecture: in.Architecture,
-		Network:      in.Network,
-		Topology:     in.Topology,
+		Release:       in.Release,
+		FromRelease:   in.FromRelease,
+		Platform:      in.Platform,
+		Architecture:  in.Architecture,
+		Network:       in.Network,
+		Topology:      in.Topology,
+		IPFamily:      in.IPFamily,
+		Proxy:         in.Proxy,
+		FIPS:          in.FIPS,
+		InstallerType: in.InstallerType,
+	}
+}
+
+// WithoutOptionalDimensions returns the job type with only the dimensions that all historical data has.
+func (t JobType) WithoutOptionalDimensions() JobType {
+	return JobType{
+		Release:      t.Release,
+		FromRelease:  t.FromRelease,
+		Platform:     t.Platform,
+		Architecture: t.Architecture,
+		Network:      t.Network,
+		Topology:     t.Topology,
+	}
+}
+
+// GoString leaves out the optional dimensions that are empty, so that details mentioning a job type only include what
+// is known about it.
+func (t JobType) GoString() string {
+	ret := fmt.Sprintf("platformidentification.JobType{Release:%q, FromRelease:%q, Platform:%q, Architecture:%q, Network:%q, Topology:%q",
+		t.Release, t.FromRelease, t.Platform, t.Architecture, t.Network, t.Topology)
+	for _, dimension := range []struct {
+		name, value string
+	}{
+		{name: "IPFamily", value: t.IPFamily},
+		{name: "Proxy", value: t.Proxy},
+		{name: "FIPS", value: t.FIPS},
+		{name: "InstallerType", value: t.InstallerType},
+	} {
+		if len(dimension.value) > 0 {
+			ret += fmt.Sprintf(", %s:%q", dimension.name, dimension.value)
+		}
 	}
+	return ret + "}"
 }
 
 // GetJobType returns information that can be used to identify a job
@@ -46,6 +106,14 @@ func GetJobType(ctx context.Context, clientConfig *rest.Config) (*JobType, error
 	if err != nil {
 		return nil, err
 	}
+	kubeClient, err := kubernetes.NewForConfig(clientConfig)
+	if err != nil {
+		return nil, err
+	}
+	return getJobType(ctx, configClient, kubeClient)
+}
+
+func getJobType(ctx context.Context, configClient configclient.ConfigV1Interface, kubeClient kubernetes.Interface) (*JobType, error) {
 	infrastructure, err := configClient.Infrastructures().Get(ctx, "cluster", metav1.GetOptions{})
 	if err != nil {
 		return nil, err
@@ -58,7 +126,7 @@ func GetJobType(ctx context.Context, clientConfig *rest.Config) (*JobType, error
 	if err != nil {
 		return nil, err
 	}
-	architecture, err := getArchitecture(clientConfig)
+	architecture, err := getArchitecture(ctx, kubeClient, infrastructure.Status.ControlPlaneTopology)
 	if err != nil {
 		return nil, err
 	}
@@ -71,23 +139,8 @@ func GetJobType(ctx context.Context, clientConfig *rest.Config) (*JobType, error
 	}
 
 	platform := ""
-	switch infrastructure.Status.PlatformStatus.Type {
-	case configv1.AWSPlatformType:
-		platform = "aws"
-	case configv1.GCPPlatformType:
-		platform = "gcp"
-	case configv1.AzurePlatformType:
-		platform = "azure"
-	case configv1.VSpherePlatformType:
-		platform = "vsphere"
-	case configv1.BareMetalPlatformType:
-		platform = "metal"
-	case configv1.OvirtPlatformType:
-		platform = "ovirt"
-	case configv1.OpenStackPlatformType:
-		platform = "openstack"
-	case configv1.LibvirtPlatformType:
-		platform = "libvirt"
+	if infrastructure.Status.PlatformStatus != nil {
+		platform = platformFor(infrastructure.Status.PlatformStatus.Type)
 	}
 
 	networkType := ""
@@ -107,15 +160,122 @@ func GetJobType(ctx context.Context, clientConfig *rest.Config) (*JobType, error
 	}
 
 	return &JobType{
-		Release:      release,
-		FromRelease:  fromRelease,
-		Platform:     platform,
-		Architecture: architecture,
-		Network:      networkType,
-		Topology:     topology,
+		Release:       release,
+		FromRelease:   fromRelease,
+		Platform:      platform,
+		Architecture:  architecture,
+		Network:       networkType,
+		Topology:      topology,
+		IPFamily:      ipFamilyFor(network.Status.ClusterNetwork),
+		Proxy:         getProxy(ctx, configClient),
+		FIPS:          getFIPS(kubeClient),
+		InstallerType: getInstallerType(ctx, kubeClient, infrastructure.Status.ControlPlaneTopology),
 	}, nil
 }
 
+func platformFor(platformType configv1.PlatformType) string {
+	switch platformType {
+	case configv1.AWSPlatformType:
+		return "aws"
+	case configv1.GCPPlatformType:
+		return "gcp"
+	case configv1.AzurePlatformType:
+		return "azure"
+	case configv1.VSpherePlatformType:
+		return "vsphere"
+	case configv1.BareMetalPlatformType:
+		return "metal"
+	case configv1.OvirtPlatformType:
+		return "ovirt"
+	case configv1.OpenStackPlatformType:
+		return "openstack"
+	case configv1.LibvirtPlatformType:
+		return "libvirt"
+	case configv1.NutanixPlatformType:
+		return "nutanix"
+	case configv1.IBMCloudPlatformType:
+		return "ibmcloud"
+	case configv1.AlibabaCloudPlatformType:
+		return "alibaba"
+	case configv1.PowerVSPlatformType:
+		return "powervs"
+	case configv1.KubevirtPlatformType:
+		return "kubevirt"
+	case configv1.EquinixMetalPlatformType:
+		return "equinix-metal"
+	case configv1.NonePlatformType:
+		return "none"
+	}
+	return ""
+}
+
+// ipFamilyFor returns the IP family of the pod network.
+func ipFamilyFor(clusterNetworks []configv1.ClusterNetworkEntry) string {
+	hasV4, hasV6 := false, false
+	for _, clusterNetwork := range clusterNetworks {
+		ip, _, err := net.ParseCIDR(clusterNetwork.CIDR)
+		if err != nil {
+			continue
+		}
+		if ip.To4() != nil {
+			hasV4 = true
+		} else {
+			hasV6 = true
+		}
+	}
+	switch {
+	case hasV4 && hasV6:
+		return IPFamilyDualStack
+	case hasV6:
+		return IPFamilyV6
+	case hasV4:
+		return IPFamilyV4
+	}
+	return ""
+}
+
+func getProxy(ctx context.Context, configClient configclient.ConfigV1Interface) string {
+	proxy, err := configClient.Proxies().Get(ctx, "cluster", metav1.GetOptions{})
+	if err != nil {
+		return ""
+	}
+	return strconv.FormatBool(len(proxy.Status.HTTPProxy) > 0 || len(proxy.Status.HTTPSProxy) > 0)
+}
+
+func getFIPS(kubeClient kubernetes.Interface) string {
+	fips, err := exutil.IsFIPS(kubeClient.CoreV1())
+	if err != nil {
+		return ""
+	}
+	return strconv.FormatBool(fips)
+}
+
+// getInstallerType uses the configmaps the installer leaves behind, like the cluster_installer metric does.  Only an
+// installer that created the infrastructure writes openshift-install, the manifests used by every install write
+// openshift-install-manifests.  Both record who invoked the installer.
+func getInstallerType(ctx context.Context, kubeClient kubernetes.Interface, controlPlaneTopology configv1.TopologyMode) string {
+	if controlPlaneTopology == configv1.ExternalTopologyMode {
+		return InstallerTypeHypershift
+	}
+	for _, installer := range []struct {
+		configMap     string
+		installerType string
+	}{
+		{configMap: "openshift-install", installerType: InstallerTypeIPI},
+		{configMap: "openshift-install-manifests", installerType: InstallerTypeUPI},
+	} {
+		configMap, err := kubeClient.CoreV1().ConfigMaps("openshift-config").Get(ctx, installer.configMap, metav1.GetOptions{})
+		if err != nil {
+			continue
+		}
+		if configMap.Data["invoker"] == "agent-installer" {
+			return InstallerTypeAgent
+		}
+		return installer.installerType
+	}
+	return ""
+}
+
 func VersionFromHistory(history configv1.UpdateHistory) string {
 	versionParts := strings.Split(history.Version, ".")
 	if len(versionParts) < 2 {
@@ -129,27 +289,14 @@ func VersionFromHistory(history configv1.UpdateHistory) string {
 	return version
 }
 
-func getArchitecture(clientConfig *rest.Config) (string, error) {
-	kubeClient, err := kubernetes.NewForConfig(clientConfig)
-	if err != nil {
-		return "", err
-	}
-	configClient, err := configclient.NewForConfig(clientConfig)
-	if err != nil {
-		return "", err
-	}
-
+func getArchitecture(ctx context.Context, kubeClient kubernetes.Interface, controlPlaneTopology configv1.TopologyMode) (string, error) {
 	listOpts := metav1.ListOptions{}
-	controlPlaneTopology, err := exutil.GetControlPlaneTopologyFromConfigClient(configClient)
-	if err != nil {
-		return "", err
-	}
 	// ExternalTopologyMode means there are no master nodes
-	if *controlPlaneTopology != configv1.ExternalTopologyMode {
+	if controlPlaneTopology != configv1.ExternalTopologyMode {
 		listOpts.LabelSelector = "node-role.kubernetes.io/master"
 	}
 
-	masterNodes, err := kubeClient.CoreV1().Nodes().List(context.Background(), listOpts)
+	masterNodes, err := kubeClient.CoreV1().Nodes().List(ctx, listOpts)
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,197 @@
+package platformidentification
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configfake "github.com/openshift/client-go/config/clientset/versioned/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetJobType(t *testing.T) {
+	infrastructure := func(platformType configv1.PlatformType, topology configv1.TopologyMode) *configv1.Infrastructure {
+		return &configv1.Infrastructure{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Status: configv1.InfrastructureStatus{
+				PlatformStatus:       &configv1.PlatformStatus{Type: platformType},
+				ControlPlaneTopology: topology,
+			},
+		}
+	}
+	clusterVersion := func(releases ...string) *configv1.ClusterVersion {
+		ret := &configv1.ClusterVersion{ObjectMeta: metav1.ObjectMeta{Name: "version"}}
+		for _, release := range releases {
+			ret.Status.History = append(ret.Status.History, configv1.UpdateHistory{Version: release})
+		}
+		return ret
+	}
+	network := func(networkType string, cidrs ...string) *configv1.Network {
+		ret := &configv1.Network{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Status:     configv1.NetworkStatus{NetworkType: networkType},
+		}
+		for _, cidr := range cidrs {
+			ret.Status.ClusterNetwork = append(ret.Status.ClusterNetwork, configv1.ClusterNetworkEntry{CIDR: cidr})
+		}
+		return ret
+	}
+	proxy := func(httpProxy string) *configv1.Proxy {
+		return &configv1.Proxy{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Status:     configv1.ProxyStatus{HTTPProxy: httpProxy},
+		}
+	}
+	node := func(labels map[string]string, architecture string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node", Labels: labels},
+			Status:     corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{Architecture: architecture}},
+		}
+	}
+	master := node(map[string]string{"node-role.kubernetes.io/master": ""}, "amd64")
+	installConfig := func(fips bool) *corev1.ConfigMap {
+		installConfig := "fips: false\n"
+		if fips {
+			installConfig = "fips: true\n"
+		}
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "cluster-config-v1"},
+			Data:       map[string]string{"install-config": installConfig},
+		}
+	}
+	installerConfigMap := func(name, invoker string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-config", Name: name},
+			Data:       map[string]string{"invoker": invoker},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		configObjects []runtime.Object
+		kubeObjects   []runtime.Object
+		want          *JobType
+	}{
+		{
+			name: "aws ipi upgrade",
+			configObjects: []runtime.Object{
+				infrastructure(configv1.AWSPlatformType, configv1.HighlyAvailableTopologyMode),
+				clusterVersion("4.12.0-0.ci-2022-07-05", "4.11.0"),
+				network("OVNKubernetes", "10.128.0.0/14"),
+				proxy(""),
+			},
+			kubeObjects: []runtime.Object{
+				master,
+				installConfig(false),
+				installerConfigMap("openshift-install", "openshift-internal-ci/periodic"),
+				installerConfigMap("openshift-install-manifests", "openshift-internal-ci/periodic"),
+			},
+			want: &JobType{
+				Release:       "4.12",
+				FromRelease:   "4.11",
+				Platform:      "aws",
+				Architecture:  "amd64",
+				Network:       "ovn",
+				Topology:      "ha",
+				IPFamily:      IPFamilyV4,
+				Proxy:         "false",
+				FIPS:          "false",
+				InstallerType: InstallerTypeIPI,
+			},
+		},
+		{
+			name: "dualstack metal with a proxy installed by the agent installer",
+			configObjects: []runtime.Object{
+				infrastructure(configv1.BareMetalPlatformType, configv1.HighlyAvailableTopologyMode),
+				clusterVersion("4.12.0"),
+				network("OVNKubernetes", "10.128.0.0/14", "fd01::/48"),
+				proxy("http://proxy.example.com:3128"),
+			},
+			kubeObjects: []runtime.Object{
+				master,
+				installConfig(true),
+				installerConfigMap("openshift-install-manifests", "agent-installer"),
+			},
+			want: &JobType{
+				Release:       "4.12",
+				Platform:      "metal",
+				Architecture:  "amd64",
+				Network:       "ovn",
+				Topology:      "ha",
+				IPFamily:      IPFamilyDualStack,
+				Proxy:         "true",
+				FIPS:          "true",
+				InstallerType: InstallerTypeAgent,
+			},
+		},
+		{
+			name: "single node upi on none",
+			configObjects: []runtime.Object{
+				infrastructure(configv1.NonePlatformType, configv1.SingleReplicaTopologyMode),
+				clusterVersion("4.12.0"),
+				network("OVNKubernetes", "fd01::/48"),
+			},
+			kubeObjects: []runtime.Object{
+				master,
+				installerConfigMap("openshift-install-manifests", "user"),
+			},
+			want: &JobType{
+				Release:       "4.12",
+				Platform:      "none",
+				Architecture:  "amd64",
+				Network:       "ovn",
+				Topology:      "single",
+				IPFamily:      IPFamilyV6,
+				InstallerType: InstallerTypeUPI,
+			},
+		},
+		{
+			name: "hypershift on nutanix",
+			configObjects: []runtime.Object{
+				infrastructure(configv1.NutanixPlatformType, configv1.ExternalTopologyMode),
+				clusterVersion("4.12.0"),
+				network("OVNKubernetes", "10.132.0.0/14"),
+				proxy(""),
+			},
+			kubeObjects: []runtime.Object{
+				node(nil, "arm64"),
+			},
+			want: &JobType{
+				Release:       "4.12",
+				Platform:      "nutanix",
+				Architecture:  "arm64",
+				Network:       "ovn",
+				IPFamily:      IPFamilyV4,
+				Proxy:         "false",
+				InstallerType: InstallerTypeHypershift,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configClient := configfake.NewSimpleClientset(tt.configObjects...).ConfigV1()
+			kubeClient := kubefake.NewSimpleClientset(tt.kubeObjects...)
+
+			got, err := getJobType(context.TODO(), configClient, kubeClient)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %#v, got %#v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestJobType_GoString(t *testing.T) {
+	jobType := JobType{Release: "4.12", Platform: "aws", Architecture: "amd64", Network: "ovn", Topology: "ha", FIPS: "true"}
+	want := `platformidentification.JobType{Release:"4.12", FromRelease:"", Platform:"aws", Architecture:"amd64", Network:"ovn", Topology:"ha", FIPS:"true"}`
+	if got := fmt.Sprintf("%#v", jobType); got != want {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
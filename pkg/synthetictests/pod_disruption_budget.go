@@ -0,0 +1,126 @@
+package synthetictests
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openshift/origin/pkg/monitor/intervalcreation"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// nodeDrainIntervals returns the drains of the machine-config-daemon, from the node annotations where we have them
+// and from the node events otherwise.
+func nodeDrainIntervals(events monitorapi.Intervals) monitorapi.Intervals {
+	beginning, end := events.Span()
+	var drains monitorapi.Intervals
+	for _, interval := range intervalcreation.IntervalsFromEvents_MachineConfigNodeUpdates(events, nil, beginning, end) {
+		if monitorapi.AnnotationsFromMessage(interval.Message)["phase"] == "Draining" {
+			drains = append(drains, interval)
+		}
+	}
+	for _, interval := range intervalcreation.IntervalsFromEvents_NodeChanges(events, nil, beginning, end) {
+		if monitorapi.AnnotationsFromMessage(interval.Message)["phase"] == "Drain" {
+			drains = append(drains, interval)
+		}
+	}
+	return drains
+}
+
+// nodesForPodDisruptionBudget returns the nodes that ran a recorded pod selected by the pod disruption budget.  Pods
+// deleted during the run are still recorded, so the nodes they were evicted from are included.
+func nodesForPodDisruptionBudget(recordedResources *monitorapi.ResourcesMap, namespace, name string) sets.String {
+	nodes := sets.NewString()
+	if recordedResources == nil {
+		return nodes
+	}
+	var selectors []labels.Selector
+	for instanceKey, obj := range (*recordedResources)["poddisruptionbudgets"] {
+		pdb, ok := obj.(*policyv1.PodDisruptionBudget)
+		if !ok || instanceKey.Namespace != namespace || instanceKey.Name != name {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil {
+			continue
+		}
+		selectors = append(selectors, selector)
+	}
+	for instanceKey, obj := range (*recordedResources)["pods"] {
+		pod, ok := obj.(*corev1.Pod)
+		if !ok || instanceKey.Namespace != namespace || len(pod.Spec.NodeName) == 0 {
+			continue
+		}
+		for _, selector := range selectors {
+			if selector.Matches(labels.Set(pod.Labels)) {
+				nodes.Insert(pod.Spec.NodeName)
+				break
+			}
+		}
+	}
+	return nodes
+}
+
+func overlaps(a, b monitorapi.EventInterval) bool {
+	return !a.To.Before(b.From) && !b.To.Before(a.From)
+}
+
+// testPodDisruptionBudgetViolations reports every time fewer pods were healthy than a pod disruption budget in an
+// openshift namespace requires.  Node drains are supposed to respect the budgets, so a violation while a node running
+// pods of the budget was draining fails.  Other violations are usually a crashing operand or a slow rollout and only
+// flake.
+func testPodDisruptionBudgetViolations(events monitorapi.Intervals, recordedResources *monitorapi.ResourcesMap) []*junitapi.JUnitTestCase {
+	const testName = "[sig-apps] pod disruption budgets in openshift namespaces should be respected during node drains"
+
+	beginning, end := events.Span()
+	violations := intervalcreation.IntervalsFromEvents_PodDisruptionBudgetViolations(events, nil, beginning, end)
+	drains := nodeDrainIntervals(events)
+
+	var duringDrains, otherViolations []string
+	for _, violation := range violations {
+		namespace, name, _ := monitorapi.PodDisruptionBudgetFromLocator(violation.Locator)
+		nodes := nodesForPodDisruptionBudget(recordedResources, namespace, name)
+		var overlappingDrains []string
+		for _, drain := range drains {
+			node, _ := monitorapi.NodeFromLocator(drain.Locator)
+			if nodes.Has(node) && overlaps(violation, drain) {
+				overlappingDrains = append(overlappingDrains, drain.String())
+			}
+		}
+		if len(overlappingDrains) == 0 {
+			otherViolations = append(otherViolations, violation.String())
+			continue
+		}
+		duringDrains = append(duringDrains, fmt.Sprintf("%v\n\twhile draining:\n\t%v", violation.String(), strings.Join(overlappingDrains, "\n\t")))
+	}
+
+	if len(duringDrains) > 0 {
+		output := fmt.Sprintf("%d pod disruption budgets were violated during node drains\n\n%v", len(duringDrains), strings.Join(duringDrains, "\n"))
+		if len(otherViolations) > 0 {
+			output += fmt.Sprintf("\n\n%d other violations\n\n%v", len(otherViolations), strings.Join(otherViolations, "\n"))
+		}
+		return []*junitapi.JUnitTestCase{
+			{
+				Name:          testName,
+				FailureOutput: &junitapi.FailureOutput{Output: output},
+			},
+		}
+	}
+
+	tests := []*junitapi.JUnitTestCase{}
+	if len(otherViolations) > 0 {
+		tests = append(tests, &junitapi.JUnitTestCase{
+			Name: testName,
+			FailureOutput: &junitapi.FailureOutput{
+				Output: fmt.Sprintf("%d pod disruption budgets were violated outside of node drains\n\n%v", len(otherViolations), strings.Join(otherViolations, "\n")),
+			},
+		})
+	}
+	tests = append(tests, &junitapi.JUnitTestCase{Name: testName})
+	return tests
+}
@@ -0,0 +1,112 @@
+package synthetictests
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_testPodDisruptionBudgetViolations(t *testing.T) {
+	start := time.Date(2022, 7, 5, 17, 0, 0, 0, time.UTC)
+	event := func(locator, message string, at time.Time) monitorapi.EventInterval {
+		return monitorapi.EventInterval{
+			Condition: monitorapi.Condition{Level: monitorapi.Info, Locator: locator, Message: message},
+			From:      at,
+			To:        at,
+		}
+	}
+	etcd := monitorapi.PodDisruptionBudgetLocator("openshift-etcd", "etcd-quorum-guard")
+	violation := monitorapi.Intervals{
+		event(etcd, "reason/PodDisruptionBudgetStatus currentHealthy/1 desiredHealthy/2 expectedPods/3 disruptionsAllowed/0 maxUnavailable/1", start.Add(2*time.Minute)),
+		event(etcd, "reason/PodDisruptionBudgetStatus currentHealthy/3 desiredHealthy/2 expectedPods/3 disruptionsAllowed/1 maxUnavailable/1", start.Add(4*time.Minute)),
+	}
+
+	guardLabels := map[string]string{"app": "guard"}
+	recordedResources := monitorapi.ResourcesMap{
+		"poddisruptionbudgets": monitorapi.InstanceMap{
+			{Namespace: "openshift-etcd", Name: "etcd-quorum-guard", UID: "1"}: &policyv1.PodDisruptionBudget{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-etcd", Name: "etcd-quorum-guard", UID: "1"},
+				Spec:       policyv1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: guardLabels}},
+			},
+		},
+		"pods": monitorapi.InstanceMap{
+			{Namespace: "openshift-etcd", Name: "etcd-guard-master-0", UID: "2"}: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-etcd", Name: "etcd-guard-master-0", UID: "2", Labels: guardLabels},
+				Spec:       corev1.PodSpec{NodeName: "master-0"},
+			},
+			// not selected by the budget
+			{Namespace: "openshift-etcd", Name: "installer-master-1", UID: "3"}: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-etcd", Name: "installer-master-1", UID: "3"},
+				Spec:       corev1.PodSpec{NodeName: "master-1"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		events     monitorapi.Intervals
+		wantFail   bool
+		wantFlake  bool
+		wantOutput string
+	}{
+		{
+			name: "no violations",
+			events: monitorapi.Intervals{
+				event(etcd, "reason/PodDisruptionBudgetStatus currentHealthy/3 desiredHealthy/2 expectedPods/3 disruptionsAllowed/1 maxUnavailable/1", start),
+			},
+		},
+		{
+			name:       "violation outside of drains",
+			events:     violation,
+			wantFlake:  true,
+			wantOutput: "violated outside of node drains",
+		},
+		{
+			name: "violation during drain",
+			events: append(monitorapi.Intervals{
+				event("node/master-0", "reason/MachineConfigState state/Working config/rendered-master-2 roles/master", start),
+				event("node/master-0", "reason/MachineConfigDrainRequested drain/drain-rendered-master-2 roles/master drain requested", start.Add(time.Minute)),
+				event("node/master-0", "reason/MachineConfigDrainComplete drain/drain-rendered-master-2 roles/master drain complete", start.Add(3*time.Minute)),
+			}, violation...),
+			wantFail:   true,
+			wantOutput: "node/master-0",
+		},
+		{
+			name: "violation during drain of a node without pods of the budget",
+			events: append(monitorapi.Intervals{
+				event("node/master-1", "reason/MachineConfigState state/Working config/rendered-master-2 roles/master", start),
+				event("node/master-1", "reason/MachineConfigDrainRequested drain/drain-rendered-master-2 roles/master drain requested", start.Add(time.Minute)),
+				event("node/master-1", "reason/MachineConfigDrainComplete drain/drain-rendered-master-2 roles/master drain complete", start.Add(3*time.Minute)),
+			}, violation...),
+			wantFlake:  true,
+			wantOutput: "violated outside of node drains",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			junits := testPodDisruptionBudgetViolations(tt.events, &recordedResources)
+			switch {
+			case tt.wantFail:
+				if len(junits) != 1 || junits[0].FailureOutput == nil {
+					t.Fatalf("expected a failure, got %#v", junits)
+				}
+			case tt.wantFlake:
+				if len(junits) != 2 || junits[0].FailureOutput == nil || junits[1].FailureOutput != nil {
+					t.Fatalf("expected a flake, got %#v", junits)
+				}
+			default:
+				if len(junits) != 1 || junits[0].FailureOutput != nil {
+					t.Fatalf("expected a pass, got %#v", junits)
+				}
+			}
+			if len(tt.wantOutput) > 0 && !strings.Contains(junits[0].FailureOutput.Output, tt.wantOutput) {
+				t.Errorf("expected output to contain %q, got %v", tt.wantOutput, junits[0].FailureOutput.Output)
+			}
+		})
+	}
+}
@@ -0,0 +1,328 @@
+package synthetictests
+
+import (
+	_ "embed"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/intervalcreation"
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+	"github.com/openshift/origin/pkg/test/ginkgo/junitapi"
+)
+
+//go:embed pod_startup_slos.yaml
+var podStartupSLOsYAML []byte
+
+// defaultPodStartupSLOs are the SLOs checked in with origin.
+var defaultPodStartupSLOs = mustParse(parsePodStartupSLOs, podStartupSLOsYAML)
+
+const (
+	// slowestPodsToList is how many of the slowest platform pods are listed in the output.
+	slowestPodsToList = 10
+)
+
+// podStartup is how long each phase of starting a single pod took.  Phases that were not observed are zero.
+type podStartup struct {
+	locator   string
+	namespace string
+
+	scheduling  time.Duration
+	imagePull   time.Duration
+	timeToReady time.Duration
+}
+
+// dominantPhase returns the phase that took the longest.  Whatever is not scheduling or pulling images, like sandbox
+// creation, init containers and readiness probes, is container startup.
+func (p podStartup) dominantPhase() string {
+	containerStartup := p.timeToReady - p.scheduling - p.imagePull
+	switch {
+	case p.scheduling >= p.imagePull && p.scheduling >= containerStartup:
+		return "scheduling"
+	case p.imagePull >= containerStartup:
+		return "image pull"
+	default:
+		return "container startup"
+	}
+}
+
+func (p podStartup) String() string {
+	return fmt.Sprintf("%s was ready after %v (scheduling %v, image pull %v), mostly %s",
+		p.locator, p.timeToReady, p.scheduling, p.imagePull, p.dominantPhase())
+}
+
+// podStartupsFrom derives the startup phases of every pod created during the run from the pod lifecycle intervals.
+// Scheduling is from created to scheduled, time to ready is from created until every container was ready, and the
+// image pull time is the sum of the pull durations reported by the kubelet.
+func podStartupsFrom(events monitorapi.Intervals) []podStartup {
+	// CreatePodIntervalsFromInstants sorts what it is given, so give it a copy
+	input := make(monitorapi.Intervals, len(events))
+	copy(input, events)
+	beginning, end := events.Span()
+	podIntervals := intervalcreation.CreatePodIntervalsFromInstants(input, nil, beginning, end)
+
+	type podTimes struct {
+		created, scheduled time.Time
+		containerToReady   map[string]time.Time
+		containerNotReady  map[string]bool
+	}
+	podToTimes := map[string]*podTimes{}
+	timesFor := func(locator string) *podTimes {
+		times, ok := podToTimes[locator]
+		if !ok {
+			times = &podTimes{containerToReady: map[string]time.Time{}, containerNotReady: map[string]bool{}}
+			podToTimes[locator] = times
+		}
+		return times
+	}
+	for _, interval := range podIntervals {
+		pod := monitorapi.PodFrom(interval.Locator)
+		if len(pod.UID) == 0 {
+			continue
+		}
+		times := timesFor(pod.ToLocator())
+		container := monitorapi.ContainerFrom(interval.Locator)
+		switch reason := monitorapi.ReasonFrom(interval.Message); {
+		case len(container.ContainerName) == 0 && reason == monitorapi.PodReasonCreated:
+			// pods created before the monitor started have no real created time
+			if !strings.Contains(interval.Message, "missed real") {
+				times.created = interval.From
+			}
+		case len(container.ContainerName) == 0 && reason == monitorapi.PodReasonScheduled:
+			times.scheduled = interval.From
+		case len(container.ContainerName) > 0 && reason == monitorapi.ContainerReasonReady:
+			if _, ok := times.containerToReady[container.ContainerName]; !ok {
+				times.containerToReady[container.ContainerName] = interval.From
+			}
+		case len(container.ContainerName) > 0 && reason == monitorapi.ContainerReasonNotReady:
+			times.containerNotReady[container.ContainerName] = true
+		}
+	}
+
+	// the kubelet events do not carry the pod UID, so a pull is attributed to the latest pod with the same namespace and
+	// name that was created before it.  Pods with the same name never run at the same time.
+	nonUniqueToLocators := map[string][]string{}
+	for locator, times := range podToTimes {
+		if times.created.IsZero() {
+			continue
+		}
+		nonUniqueLocator := monitorapi.NonUniquePodLocatorFrom(locator)
+		nonUniqueToLocators[nonUniqueLocator] = append(nonUniqueToLocators[nonUniqueLocator], locator)
+	}
+	podToImagePull := map[string]time.Duration{}
+	for _, event := range events {
+		if monitorapi.ReasonFrom(event.Message) != "Pulled" {
+			continue
+		}
+		seconds, err := strconv.ParseFloat(strings.TrimSuffix(monitorapi.AnnotationsFromMessage(event.Message)["duration"], "s"), 64)
+		if err != nil {
+			continue
+		}
+		locator := ""
+		if pod := monitorapi.PodFrom(event.Locator); len(pod.UID) > 0 {
+			locator = pod.ToLocator()
+		} else {
+			var created time.Time
+			for _, candidate := range nonUniqueToLocators[monitorapi.NonUniquePodLocatorFrom(event.Locator)] {
+				candidateCreated := podToTimes[candidate].created
+				if candidateCreated.After(event.From) || candidateCreated.Before(created) {
+					continue
+				}
+				locator, created = candidate, candidateCreated
+			}
+		}
+		if len(locator) == 0 {
+			continue
+		}
+		podToImagePull[locator] += time.Duration(seconds * float64(time.Second))
+	}
+
+	ret := []podStartup{}
+	for locator, times := range podToTimes {
+		if times.created.IsZero() || times.scheduled.IsZero() {
+			continue
+		}
+		startup := podStartup{
+			locator:    locator,
+			namespace:  monitorapi.NamespaceFromLocator(locator),
+			scheduling: times.scheduled.Sub(times.created),
+			imagePull:  podToImagePull[locator],
+		}
+		var ready time.Time
+		for container := range times.containerNotReady {
+			containerReady, ok := times.containerToReady[container]
+			if !ok {
+				ready = time.Time{}
+				break
+			}
+			if containerReady.After(ready) {
+				ready = containerReady
+			}
+		}
+		if !ready.IsZero() {
+			startup.timeToReady = ready.Sub(times.created)
+		}
+		ret = append(ret, startup)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].locator < ret[j].locator
+	})
+	return ret
+}
+
+// percentile returns the nearest-rank percentile of the durations, which must be sorted.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
+
+// podStartupSLOFile is the serialized form of pod_startup_slos.yaml.
+type podStartupSLOFile struct {
+	versionedDataFile
+	SLOs []serializedPodStartupSLO `json:"slos,omitempty"`
+}
+
+type serializedPodStartupSLO struct {
+	Phase      string  `json:"phase"`
+	Percentile float64 `json:"percentile"`
+	Max        string  `json:"max"`
+	MinPods    int     `json:"minPods,omitempty"`
+}
+
+// podStartupSLO is the longest a phase may take for the given percentile of the pods in a namespace group.
+type podStartupSLO struct {
+	phase      string
+	percentile float64
+	max        time.Duration
+	// minPods is how many pods a group needs before the SLO is checked.
+	minPods    int
+	durationOf func(podStartup) time.Duration
+}
+
+// podStartupPhases maps the phases in pod_startup_slos.yaml to how they are reported and measured.
+var podStartupPhases = map[string]podStartupSLO{
+	"scheduling":  {phase: "scheduling", durationOf: func(p podStartup) time.Duration { return p.scheduling }},
+	"imagePull":   {phase: "image pull", durationOf: func(p podStartup) time.Duration { return p.imagePull }},
+	"timeToReady": {phase: "time to ready", durationOf: func(p podStartup) time.Duration { return p.timeToReady }},
+}
+
+func parsePodStartupSLOs(data []byte) ([]podStartupSLO, error) {
+	file := podStartupSLOFile{}
+	if err := parseDataFile("pod startup SLOs", data, &file); err != nil {
+		return nil, err
+	}
+
+	ret := []podStartupSLO{}
+	for i, serialized := range file.SLOs {
+		slo, ok := podStartupPhases[serialized.Phase]
+		if !ok {
+			return nil, fmt.Errorf("slo %d: unknown phase %q", i, serialized.Phase)
+		}
+		if serialized.Percentile <= 0 || serialized.Percentile > 100 {
+			return nil, fmt.Errorf("slo %d: percentile must be greater than 0 and at most 100", i)
+		}
+		max, err := time.ParseDuration(serialized.Max)
+		if err != nil {
+			return nil, fmt.Errorf("slo %d: invalid max: %w", i, err)
+		}
+		if max <= 0 {
+			return nil, fmt.Errorf("slo %d: max must be positive", i)
+		}
+		if serialized.MinPods < 0 {
+			return nil, fmt.Errorf("slo %d: minPods must not be negative", i)
+		}
+		slo.percentile = serialized.Percentile
+		slo.max = max
+		slo.minPods = serialized.MinPods
+		ret = append(ret, slo)
+	}
+	return ret, nil
+}
+
+// testPodStartupSLOs calculates the percentiles of the scheduling latency, image pull time and time to ready of the
+// pods in each namespace group, and flakes when a platform namespace group with enough pods exceeds an SLO.  The
+// percentiles of every group and the slowest platform pods are always listed.  Pods that never became ready only
+// count for scheduling and image pulls.
+//
+// Exceeding an SLO deliberately does not fail yet: the SLOs in pod_startup_slos.yaml have not been measured against
+// CI, so this flakes until the listed percentiles show the SLOs hold on every platform.
+func testPodStartupSLOs(events monitorapi.Intervals, slos []podStartupSLO) []*junitapi.JUnitTestCase {
+	const testName = "[sig-scheduling] platform pods should be scheduled and become ready within their SLOs"
+
+	groupToStartups := map[string][]podStartup{}
+	namespaceToGroup := map[string]string{}
+	var platformStartups []podStartup
+	for _, startup := range podStartupsFrom(events) {
+		group, ok := namespaceToGroup[startup.namespace]
+		if !ok {
+			group = intervalcreation.NamespaceGroupFor(startup.namespace)
+			namespaceToGroup[startup.namespace] = group
+		}
+		groupToStartups[group] = append(groupToStartups[group], startup)
+		if strings.HasPrefix(startup.namespace, "openshift-") {
+			platformStartups = append(platformStartups, startup)
+		}
+	}
+	groups := []string{}
+	for group := range groupToStartups {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	var summaries, failures []string
+	for _, group := range groups {
+		startups := groupToStartups[group]
+		isPlatformGroup := group != "e2e-namespaces" && group != "everything-else"
+		for _, slo := range slos {
+			durations := []time.Duration{}
+			for _, startup := range startups {
+				if duration := slo.durationOf(startup); duration > 0 || slo.phase == "scheduling" {
+					durations = append(durations, duration)
+				}
+			}
+			if len(durations) == 0 {
+				continue
+			}
+			sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+			actual := percentile(durations, slo.percentile)
+			summaries = append(summaries, fmt.Sprintf("%s %s of %d pods: p50 %v, p90 %v, p99 %v",
+				group, slo.phase, len(durations), percentile(durations, 50), percentile(durations, 90), percentile(durations, 99)))
+			if isPlatformGroup && len(durations) >= slo.minPods && actual > slo.max {
+				failures = append(failures, fmt.Sprintf("%s %s p%v was %v, more than the %v allowed", group, slo.phase, slo.percentile, actual, slo.max))
+			}
+		}
+	}
+
+	sort.Slice(platformStartups, func(i, j int) bool {
+		return platformStartups[i].timeToReady+platformStartups[i].scheduling > platformStartups[j].timeToReady+platformStartups[j].scheduling
+	})
+	var slowest []string
+	for i := 0; i < len(platformStartups) && i < slowestPodsToList; i++ {
+		slowest = append(slowest, platformStartups[i].String())
+	}
+
+	systemOut := fmt.Sprintf("%v\n\nslowest platform pods\n\n%v", strings.Join(summaries, "\n"), strings.Join(slowest, "\n"))
+	tests := []*junitapi.JUnitTestCase{}
+	if len(failures) > 0 {
+		tests = append(tests, &junitapi.JUnitTestCase{
+			Name:      testName,
+			SystemOut: systemOut,
+			FailureOutput: &junitapi.FailureOutput{
+				Output: fmt.Sprintf("%d pod startup SLOs were exceeded\n\n%v\n\nslowest platform pods\n\n%v",
+					len(failures), strings.Join(failures, "\n"), strings.Join(slowest, "\n")),
+			},
+		})
+	}
+	// only flake for now
+	tests = append(tests, &junitapi.JUnitTestCase{Name: testName, SystemOut: systemOut})
+	return tests
+}
@@ -0,0 +1,164 @@
+package synthetictests
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/origin/pkg/monitor/monitorapi"
+)
+
+const testPodStartupSLOsYAML = `
+version: 1
+slos:
+- phase: scheduling
+  percentile: 99
+  max: 30s
+- phase: imagePull
+  percentile: 90
+  max: 2m
+- phase: timeToReady
+  percentile: 90
+  max: 5m
+  minPods: 2
+`
+
+func podStartupEvents(namespace, name, uid string, created time.Time, scheduling, imagePull, toReady time.Duration) monitorapi.Intervals {
+	event := func(locator, message string, at time.Time) monitorapi.EventInterval {
+		return monitorapi.EventInterval{
+			Condition: monitorapi.Condition{Level: monitorapi.Info, Locator: locator, Message: message},
+			From:      at,
+			To:        at,
+		}
+	}
+	pod := "ns/" + namespace + " pod/" + name + " node/worker-0 uid/" + uid
+	return monitorapi.Intervals{
+		event("ns/"+namespace+" pod/"+name+" node/ uid/"+uid, "reason/Created", created),
+		event(pod, "reason/Scheduled node/worker-0", created.Add(scheduling)),
+		event(pod+" container/app", "reason/NotReady", created.Add(scheduling)),
+		event("ns/"+namespace+" pod/"+name+" node/worker-0", "container/app reason/Pulled duration/"+strings.TrimSuffix(imagePull.String(), "s")+"s image/quay.io/app", created.Add(scheduling+imagePull)),
+		event(pod+" container/app", "reason/Ready", created.Add(toReady)),
+	}
+}
+
+func Test_podStartupsFrom(t *testing.T) {
+	start := time.Date(2022, 7, 5, 17, 0, 0, 0, time.UTC)
+	events := podStartupEvents("openshift-etcd", "etcd-0", "uid-0", start, 5*time.Second, 30*time.Second, 2*time.Minute)
+	events = append(events, monitorapi.EventInterval{
+		Condition: monitorapi.Condition{Level: monitorapi.Info, Locator: "node/worker-0", Message: "reason/Ignored"},
+		From:      start.Add(10 * time.Minute),
+		To:        start.Add(10 * time.Minute),
+	})
+
+	startups := podStartupsFrom(events)
+	if len(startups) != 1 {
+		t.Fatalf("expected a single pod, got %v", startups)
+	}
+	startup := startups[0]
+	if startup.scheduling != 5*time.Second {
+		t.Errorf("expected scheduling to take 5s, got %v", startup.scheduling)
+	}
+	if startup.imagePull != 30*time.Second {
+		t.Errorf("expected the image pull to take 30s, got %v", startup.imagePull)
+	}
+	if startup.timeToReady != 2*time.Minute {
+		t.Errorf("expected the pod to be ready after 2m, got %v", startup.timeToReady)
+	}
+	if phase := startup.dominantPhase(); phase != "container startup" {
+		t.Errorf("expected container startup to dominate, got %v", phase)
+	}
+}
+
+func Test_podStartupsFrom_recreatedPod(t *testing.T) {
+	start := time.Date(2022, 7, 5, 17, 0, 0, 0, time.UTC)
+	events := podStartupEvents("openshift-etcd", "etcd-0", "uid-0", start, time.Second, 10*time.Second, time.Minute)
+	events = append(events, podStartupEvents("openshift-etcd", "etcd-0", "uid-1", start.Add(10*time.Minute), time.Second, 40*time.Second, time.Minute)...)
+
+	startups := podStartupsFrom(events)
+	if len(startups) != 2 {
+		t.Fatalf("expected two pods, got %v", startups)
+	}
+	if startups[0].imagePull != 10*time.Second {
+		t.Errorf("expected the image pull of the first pod to take 10s, got %v", startups[0].imagePull)
+	}
+	if startups[1].imagePull != 40*time.Second {
+		t.Errorf("expected the image pull of the recreated pod to take 40s, got %v", startups[1].imagePull)
+	}
+}
+
+func Test_parsePodStartupSLOs(t *testing.T) {
+	slos, err := parsePodStartupSLOs([]byte(testPodStartupSLOsYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(slos) != 3 || slos[2].phase != "time to ready" || slos[2].max != 5*time.Minute || slos[2].minPods != 2 {
+		t.Errorf("unexpected SLOs %+v", slos)
+	}
+
+	invalid := map[string]string{
+		"unknown phase":      "version: 1\nslos:\n- phase: running\n  percentile: 90\n  max: 1m\n",
+		"missing percentile": "version: 1\nslos:\n- phase: scheduling\n  max: 1m\n",
+		"percentile over":    "version: 1\nslos:\n- phase: scheduling\n  percentile: 101\n  max: 1m\n",
+		"missing max":        "version: 1\nslos:\n- phase: scheduling\n  percentile: 90\n",
+		"invalid max":        "version: 1\nslos:\n- phase: scheduling\n  percentile: 90\n  max: soon\n",
+		"negative minPods":   "version: 1\nslos:\n- phase: scheduling\n  percentile: 90\n  max: 1m\n  minPods: -1\n",
+		"unknown field":      "version: 1\nslos:\n- phase: scheduling\n  percentile: 90\n  max: 1m\n  namespace: foo\n",
+		"unknown version":    "version: 2\n",
+	}
+	for name, data := range invalid {
+		if _, err := parsePodStartupSLOs([]byte(data)); err == nil {
+			t.Errorf("%v: expected an error", name)
+		}
+	}
+}
+
+func Test_testPodStartupSLOs(t *testing.T) {
+	start := time.Date(2022, 7, 5, 17, 0, 0, 0, time.UTC)
+	slos := mustParse(parsePodStartupSLOs, []byte(testPodStartupSLOsYAML))
+
+	tests := []struct {
+		name     string
+		events   monitorapi.Intervals
+		wantFail bool
+	}{
+		{
+			name:   "fast platform pod",
+			events: podStartupEvents("openshift-etcd", "etcd-0", "uid-0", start, time.Second, 10*time.Second, 30*time.Second),
+		},
+		{
+			name:     "slow scheduling of a platform pod",
+			events:   podStartupEvents("openshift-etcd", "etcd-0", "uid-0", start, 2*time.Minute, 10*time.Second, 3*time.Minute),
+			wantFail: true,
+		},
+		{
+			name:   "slow platform pod in a group too small to check",
+			events: podStartupEvents("openshift-etcd", "etcd-0", "uid-0", start, time.Second, 10*time.Second, 10*time.Minute),
+		},
+		{
+			name: "slow platform pods",
+			events: append(
+				podStartupEvents("openshift-etcd", "etcd-0", "uid-0", start, time.Second, 10*time.Second, 10*time.Minute),
+				podStartupEvents("openshift-etcd", "etcd-1", "uid-1", start, time.Second, 10*time.Second, 10*time.Minute)...),
+			wantFail: true,
+		},
+		{
+			name:   "slow e2e pod",
+			events: podStartupEvents("e2e-test-foo", "app", "uid-0", start, 2*time.Minute, 10*time.Second, 10*time.Minute),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			junits := testPodStartupSLOs(tt.events, slos)
+			if !tt.wantFail {
+				if len(junits) != 1 || junits[0].FailureOutput != nil {
+					t.Errorf("expected a single passing result, got %v", junits)
+				}
+				return
+			}
+			// a failure is always followed by a success so that the test flakes
+			if len(junits) != 2 || junits[0].FailureOutput == nil || junits[1].FailureOutput != nil || junits[0].Name != junits[1].Name {
+				t.Errorf("expected a flake, got %v", junits)
+			}
+		})
+	}
+}
@@ -16,7 +16,9 @@ import (
 	"github.com/openshift/origin/pkg/monitor/intervalcreation"
 	"github.com/openshift/origin/pkg/monitor/monitorapi"
 	monitorserialization "github.com/openshift/origin/pkg/monitor/serialization"
+	"github.com/openshift/origin/pkg/synthetictests"
 	"github.com/openshift/origin/pkg/synthetictests/allowedalerts"
+	"github.com/openshift/origin/pkg/synthetictests/platformidentification"
 	"github.com/openshift/origin/test/extended/util/disruption/controlplane"
 	"github.com/openshift/origin/test/extended/util/disruption/frontends"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
@@ -36,15 +38,23 @@ type MonitorEventsOptions struct {
 	monitor   *monitor.Monitor
 	startTime *time.Time
 	endTime   *time.Time
+	// timeSuffix is set during Start and names the run data of this run, like job-type_<timeSuffix>.json
+	timeSuffix string
 	// recordedEvents is written during End
 	recordedEvents monitorapi.Intervals
 	// recordedResource is written during End
 	recordedResources monitorapi.ResourcesMap
+	// jobType is written during End, when the cluster could be identified
+	jobType *platformidentification.JobType
 
 	Recorders      []monitor.StartEventIntervalRecorderFunc
 	RunDataWriters []RunDataWriter
-	Out            io.Writer
-	ErrOut         io.Writer
+	// IntervalCreators computes additional intervals from the cluster and from the recorded intervals during End.
+	IntervalCreators *intervalcreation.IntervalCreatorRegistry
+	// MetricThresholds are queried during End and every time a series is above its threshold becomes an interval.
+	MetricThresholds []monitor.MetricThreshold
+	Out              io.Writer
+	ErrOut           io.Writer
 }
 
 func NewMonitorEventsOptions(out io.Writer, errOut io.Writer) *MonitorEventsOptions {
@@ -60,6 +70,7 @@ func NewMonitorEventsOptions(out io.Writer, errOut io.Writer) *MonitorEventsOpti
 			// TODO add visualization of individual apiserver containers and their readiness on this page
 			intervalcreation.NewSpyglassEventIntervalRenderer("kube-apiserver", intervalcreation.BelongsInKubeAPIServer),
 			intervalcreation.NewSpyglassEventIntervalRenderer("operators", intervalcreation.BelongsInOperatorRollout),
+			intervalcreation.NewSpyglassEventIntervalRenderer("machine-config", intervalcreation.BelongsInMachineConfig),
 			intervalcreation.NewPodEventIntervalRenderer(),
 			intervalcreation.NewIngressServicePodIntervalRenderer(),
 
@@ -67,9 +78,12 @@ func NewMonitorEventsOptions(out io.Writer, errOut io.Writer) *MonitorEventsOpti
 			RunDataWriterFunc(monitor.WriteTrackedResourcesForJobRun),
 			RunDataWriterFunc(monitor.WriteBackendDisruptionForJobRun),
 			RunDataWriterFunc(allowedalerts.WriteAlertDataForJobRun),
+			RunDataWriterFunc(synthetictests.WriteDuplicatedEventExceptionUsageForJobRun),
 		},
-		Out:    out,
-		ErrOut: errOut,
+		IntervalCreators: intervalcreation.NewDefaultIntervalCreatorRegistry(),
+		MetricThresholds: monitor.DefaultMetricThresholds(),
+		Out:              out,
+		ErrOut:           errOut,
 	}
 }
 
@@ -79,6 +93,7 @@ func (o *MonitorEventsOptions) Start(ctx context.Context, restConfig *rest.Confi
 	}
 	t := time.Now()
 	o.startTime = &t
+	o.timeSuffix = fmt.Sprintf("_%s", t.UTC().Format("20060102-150405"))
 
 	m, err := monitor.Start(ctx, restConfig,
 		[]monitor.StartEventIntervalRecorderFunc{
@@ -109,8 +124,9 @@ func (o *MonitorEventsOptions) End(ctx context.Context, restConfig *rest.Config,
 	var err error
 	fromTime, endTime := time.Time{}, time.Time{}
 	events := o.monitor.Intervals(fromTime, endTime)
-	// this happens before calculation because events collected here could be used to drive later calculations
-	events, err = intervalcreation.InsertIntervalsFromCluster(ctx, restConfig, events, o.recordedResources, fromTime, endTime)
+	// this happens before calculation because events collected here could be used to drive later calculations.
+	// the creators reading logs from the cluster only keep the intervals overlapping the run.
+	events, err = o.IntervalCreators.InsertIntervalsFromCluster(ctx, restConfig, events, o.recordedResources, *o.startTime, *o.endTime)
 	if err != nil {
 		return fmt.Errorf("InsertIntervalsFromClusterError: %w", err)
 	}
@@ -120,7 +136,13 @@ func (o *MonitorEventsOptions) End(ctx context.Context, restConfig *rest.Config,
 		return fmt.Errorf("AlertErr: %w", err)
 	}
 	events = append(events, alertEventIntervals...)
-	events = intervalcreation.InsertCalculatedIntervals(events, o.recordedResources, fromTime, endTime)
+	// metrics are only there to look at, so missing some of them should not fail the run
+	metricEventIntervals, err := monitor.FetchEventIntervalsForMetricThresholds(ctx, restConfig, *o.startTime, o.MetricThresholds)
+	if err != nil {
+		fmt.Fprintf(o.ErrOut, "Unable to create all of the metric threshold intervals: %v\n", err)
+	}
+	events = append(events, metricEventIntervals...)
+	events = o.IntervalCreators.InsertCalculatedIntervals(events, o.recordedResources, fromTime, endTime)
 
 	// read events from other test processes (individual tests for instance) that happened during this run.
 	// this happens during upgrade tests to pass information back to the main monitor.
@@ -148,6 +170,13 @@ func (o *MonitorEventsOptions) End(ctx context.Context, restConfig *rest.Config,
 	sort.Sort(events)
 	events.Clamp(*o.startTime, *o.endTime)
 
+	// the job type lets the run data be used to build historical data without access to the cluster
+	if jobType, err := platformidentification.GetJobType(ctx, restConfig); err != nil {
+		fmt.Fprintf(o.ErrOut, "Unable to identify the job type, it will not be written with the run data: %v\n", err)
+	} else {
+		o.jobType = jobType
+	}
+
 	o.recordedEvents = events
 
 	return nil
@@ -166,7 +195,7 @@ func (o *MonitorEventsOptions) WriteRunDataToArtifactsDir(artifactDir string) er
 	if o.endTime == nil {
 		return fmt.Errorf("not ended")
 	}
-	timeSuffix := fmt.Sprintf("_%s", o.startTime.UTC().Format("20060102-150405"))
+	timeSuffix := o.timeSuffix
 
 	errs := []error{}
 
@@ -184,5 +213,12 @@ func (o *MonitorEventsOptions) WriteRunDataToArtifactsDir(artifactDir string) er
 			errs = append(errs, currErr)
 		}
 	}
+	if o.jobType != nil {
+		if err := platformidentification.WriteJobTypeForJobRun(artifactDir, o.jobType, timeSuffix); err != nil {
+			errs = append(errs, err)
+		} else {
+			synthetictests.SetRunJobTypeFile(platformidentification.JobTypeFilename(artifactDir, timeSuffix))
+		}
+	}
 	return utilerrors.NewAggregate(errs)
 }
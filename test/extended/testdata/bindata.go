@@ -52466,8 +52466,16 @@ var _e2echartE2eChartTemplateHtml = []byte(`<html lang="en">
 <body>
 
 <div id="search" class="form-control-lg">
-    <form>
-        <input class="form-control" type="text" id="filterInput" placeholder="RegExp Filter">
+    <form class="form-inline">
+        <input class="form-control mr-2" type="text" id="filterInput" placeholder="RegExp Filter">
+        <input class="form-control mr-2" type="text" id="locatorInput" placeholder="Locator contains">
+        <select class="form-control mr-2" id="namespaceGroupSelect">
+            <option value="">All namespace groups</option>
+        </select>
+        <div class="form-check mr-2">
+            <input class="form-check-input" type="checkbox" id="hideInfoCheckbox">
+            <label class="form-check-label" for="hideInfoCheckbox">Hide info-level rows</label>
+        </div>
     </form>
 </div>
 
@@ -52477,12 +52485,15 @@ var _e2echartE2eChartTemplateHtml = []byte(`<html lang="en">
     <div class="modal-dialog modal-lg" role="document">
         <div class="modal-content">
             <div class="modal-header">
-                <h5 class="modal-title">Resource</h5>
+                <h5 class="modal-title" id="myModalTitle">Interval</h5>
                 <button type="button" class="close" data-dismiss="modal" aria-label="Close">
                     <span aria-hidden="true">&times;</span>
                 </button>
             </div>
             <div class="modal-body">
+                <h6>Message</h6>
+                <pre><code id="myModalMessage"></code></pre>
+                <h6>Raw</h6>
                 <pre><code id="myModalContent"></code></pre>
             </div>
             <div class="modal-footer">
@@ -52494,26 +52505,93 @@ var _e2echartE2eChartTemplateHtml = []byte(`<html lang="en">
 
 <script>
     var eventIntervals = EVENT_INTERVAL_JSON_GOES_HERE
+    var namespaceGroups = NAMESPACE_GROUPS_JSON_GOES_HERE
 </script>
 
 <script>
+    // All filtering happens here, so the same file can be sliced without regenerating it.
+    var filters = {
+        regex: null,
+        locatorSubstring: "",
+        namespaceGroup: "",
+        hideInfo: false,
+    }
+
+    function rerenderChart() {
+        document.getElementById("chart").innerHTML = "";
+        renderChart(filters.regex)
+    }
+
     // Re-render the chart with input as a regexp. Timeout for event debouncing.
     $('#filterInput').on('input', (e) => {
         var $this = $(this);
         clearTimeout($this.data('timeout'));
         $this.data('timeout', setTimeout(() => {
-            document.getElementById("chart").innerHTML = "";
-            renderChart(new RegExp(e.target.value))
+            filters.regex = new RegExp(e.target.value)
+            rerenderChart()
+        }, 250));
+    });
+
+    $('#locatorInput').on('input', (e) => {
+        var $this = $(e.target);
+        clearTimeout($this.data('timeout'));
+        $this.data('timeout', setTimeout(() => {
+            filters.locatorSubstring = e.target.value
+            rerenderChart()
         }, 250));
     });
 
+    $('#namespaceGroupSelect').on('change', (e) => {
+        filters.namespaceGroup = e.target.value
+        rerenderChart()
+    });
+
+    $('#hideInfoCheckbox').on('change', (e) => {
+        filters.hideInfo = e.target.checked
+        rerenderChart()
+    });
+
     // Prevent page refresh from pressing enter in input box
-    $('#filterInput').keypress((e) => {
-        if (event.which == '13') {
-            event.preventDefault();
+    $('#search input').keypress((e) => {
+        if (e.which == '13') {
+            e.preventDefault();
         }
     });
 
+    const namespaceToGroup = {}
+    namespaceGroups.forEach((nsGroup) => {
+        nsGroup.namespaces.forEach((namespace) => {
+            namespaceToGroup[namespace] = nsGroup.name
+        })
+        $('#namespaceGroupSelect').append($('<option>').val(nsGroup.name).text(nsGroup.name))
+    })
+    $('#namespaceGroupSelect').append($('<option>').val("e2e-namespaces").text("e2e-namespaces"))
+    $('#namespaceGroupSelect').append($('<option>').val("everything-else").text("everything-else"))
+
+    // namespaces outside the well known groups are split the same way the per-namespace timelines split them.
+    const reNamespace = new RegExp("(^| )ns/([^ ]+)")
+    function namespaceGroupFor(eventInterval) {
+        let m = eventInterval.locator.match(reNamespace)
+        let namespace = m ? m[2] : ""
+        if (namespaceToGroup[namespace]) {
+            return namespaceToGroup[namespace]
+        }
+        if (namespace.startsWith("e2e-")) {
+            return "e2e-namespaces"
+        }
+        return "everything-else"
+    }
+
+    function matchesFilters(eventInterval) {
+        if (filters.locatorSubstring && !eventInterval.locator.includes(filters.locatorSubstring)) {
+            return false
+        }
+        if (filters.namespaceGroup && namespaceGroupFor(eventInterval) != filters.namespaceGroup) {
+            return false
+        }
+        return true
+    }
+
     function isOperatorAvailable(eventInterval) {
         if (eventInterval.locator.startsWith("clusteroperator/") && eventInterval.message.includes("condition/Available") && eventInterval.message.includes("status/False")) {
             return true
@@ -52570,6 +52648,27 @@ var _e2echartE2eChartTemplateHtml = []byte(`<html lang="en">
         return false
     }
 
+    function isPodSandbox(eventInterval) {
+        if (eventInterval.locator.includes("pod/") && (eventInterval.message.includes("reason/SandboxCreated") || eventInterval.message.includes("reason/SandboxDestroyed"))) {
+            return true
+        }
+        return false
+    }
+
+    function isEtcdLeadership(eventInterval) {
+        if (eventInterval.locator.includes("pod/") && (eventInterval.message.includes("reason/LeaderElected") || eventInterval.message.includes("reason/LeaderChanged") || eventInterval.message.includes("reason/LeaderMissing"))) {
+            return true
+        }
+        return false
+    }
+
+    function isAPIServerShutdown(eventInterval) {
+        if (eventInterval.locator.includes("pod/") && eventInterval.message.includes("reason/GracefulShutdown")) {
+            return true
+        }
+        return false
+    }
+
     function isE2EFailed(eventInterval) {
         if (eventInterval.locator.startsWith("e2e-test/") && eventInterval.message.includes("finished As \"Failed")) {
             return true
@@ -52591,6 +52690,13 @@ var _e2echartE2eChartTemplateHtml = []byte(`<html lang="en">
         return false
     }
 
+    function isE2ETestNamespace(eventInterval) {
+        if (eventInterval.message.startsWith("reason/E2ETestNamespace")) {
+            return true
+        }
+        return false
+    }
+
     function isEndpointConnectivity(eventInterval) {
         if (!eventInterval.message.includes("reason/DisruptionBegan") && !eventInterval.message.includes("reason/DisruptionSamplerOutageBegan")){
             return false
@@ -52610,7 +52716,42 @@ var _e2echartE2eChartTemplateHtml = []byte(`<html lang="en">
 
     function isNodeState(eventInterval) {
         if (eventInterval.locator.startsWith("node/")) {
-            return (eventInterval.message.startsWith("reason/NodeUpdate ") || eventInterval.message.includes("node is not ready"))
+            return (eventInterval.message.startsWith("reason/NodeUpdate ") || eventInterval.message.startsWith("reason/MachineConfigNodeUpdate ") || eventInterval.message.startsWith("reason/NodeResourcePressure ") || eventInterval.message.includes("node is not ready"))
+        }
+        return false
+    }
+
+    function isNodeLink(eventInterval) {
+        if (eventInterval.locator.startsWith("node/") && eventInterval.locator.includes(" device/")) {
+            return eventInterval.message.includes("reason/LinkDown")
+        }
+        return false
+    }
+
+    function isNodeUnit(eventInterval) {
+        if (eventInterval.locator.startsWith("node/") && eventInterval.locator.includes(" unit/")) {
+            return (eventInterval.message.includes("reason/UnitFailed") || eventInterval.message.includes("reason/UnitRestarted"))
+        }
+        return false
+    }
+
+    function isMachineConfigPool(eventInterval) {
+        if (eventInterval.locator.startsWith("machineconfigpool/")) {
+            return (eventInterval.message.startsWith("reason/MachineConfigPoolUpdating") || eventInterval.message.startsWith("reason/MachineConfigPoolDegraded"))
+        }
+        return false
+    }
+
+    function isClusterVersionUpdate(eventInterval) {
+        if (eventInterval.locator.startsWith("clusterversion/")) {
+            return eventInterval.message.startsWith("reason/ClusterVersionUpdate")
+        }
+        return false
+    }
+
+    function isLeaseHolder(eventInterval) {
+        if (eventInterval.locator.includes(" lease/")) {
+            return eventInterval.message.startsWith("reason/LeaseHolder ")
         }
         return false
     }
@@ -52622,6 +52763,13 @@ var _e2echartE2eChartTemplateHtml = []byte(`<html lang="en">
         return false
     }
 
+    function isMetricThreshold(eventInterval) {
+        if (eventInterval.locator.startsWith("metric/")) {
+            return eventInterval.message.startsWith("reason/MetricThresholdExceeded ")
+        }
+        return false
+    }
+
     const reReason = new RegExp("(^| )reason/([^ ]+)")
     function podStateValue(item) {
         let m = item.message.match(reReason);
@@ -52662,6 +52810,24 @@ var _e2echartE2eChartTemplateHtml = []byte(`<html lang="en">
             }
         }
 
+        if (m && isPodSandbox(item)){
+            return [item.locator, ` + "`" + ` (pod sandbox)` + "`" + `, m[2]];
+        }
+        if (m && isEtcdLeadership(item)){
+            return [item.locator, ` + "`" + ` (etcd leadership)` + "`" + `, m[2]];
+        }
+        if (m && isAPIServerShutdown(item)){
+            return [item.locator, ` + "`" + ` (apiserver shutdown)` + "`" + `, m[2]];
+        }
+
+        return [item.locator, "", "Unknown"];
+    }
+
+    function reasonValue(item) {
+        let m = item.message.match(reReason);
+        if (m) {
+            return [item.locator, "", m[2]];
+        }
         return [item.locator, "", "Unknown"];
     }
 
@@ -52680,6 +52846,9 @@ var _e2echartE2eChartTemplateHtml = []byte(`<html lang="en">
         if (item.message.includes("node is not ready")) {
             return [item.locator, ` + "`" + ` (${roles},not ready)` + "`" + `, "NodeNotReady"]
         }
+        if (item.message.startsWith("reason/NodeResourcePressure ")) {
+            return [item.locator, ` + "`" + ` (${roles},pressure)` + "`" + `, "NodeResourcePressure"]
+        }
         let m = item.message.match(rePhase);
         if (m && m[2] != "Update") {
             return [item.locator, ` + "`" + ` (${roles},update phases)` + "`" + `, m[2]];
@@ -52738,7 +52907,7 @@ var _e2echartE2eChartTemplateHtml = []byte(`<html lang="en">
             new Date(now.getTime() - 1),
         );
         rawEventIntervals.items.forEach((item) => {
-            if (!preconditionFunc(item)) {
+            if (!preconditionFunc(item) || !matchesFilters(item)) {
                 return
             }
             var startDate = new Date(item.from)
@@ -52768,7 +52937,8 @@ var _e2echartE2eChartTemplateHtml = []byte(`<html lang="en">
             ranges.push({
                 timeRange: [startDate, endDate],
                 val: val,
-                labelVal: defaultToolTip(item)
+                labelVal: defaultToolTip(item),
+                item: item
             });
         });
         for (const label in data) {
@@ -52776,6 +52946,10 @@ var _e2echartE2eChartTemplateHtml = []byte(`<html lang="en">
             for (const sub in section) {
                 if (regex == null || (regex != null && regex.test(label))) {
                     const data = section[sub];
+                    // a row is info-level when every interval on it is
+                    if (filters.hideInfo && data.every((curr) => curr.item.level == "Info")) {
+                        continue
+                    }
                     const totalDurationSeconds = data.reduce(
                         (prev, curr) => prev + (curr.timeRange[1].getTime() - curr.timeRange[0].getTime())/1000,
                         0);
@@ -52818,6 +52992,9 @@ var _e2echartE2eChartTemplateHtml = []byte(`<html lang="en">
         //     return 0
         // })
 
+        timelineGroups.push({group: "metrics", data: []})
+        createTimelineData(reasonValue, timelineGroups[timelineGroups.length - 1].data, eventIntervals, isMetricThreshold, regex)
+
         timelineGroups.push({group: "node-state", data: []})
         createTimelineData(nodeStateValue, timelineGroups[timelineGroups.length - 1].data, eventIntervals, isNodeState, regex)
         timelineGroups[timelineGroups.length - 1].data.sort(function (e1 ,e2){
@@ -52827,9 +53004,27 @@ var _e2echartE2eChartTemplateHtml = []byte(`<html lang="en">
             return 0
         })
 
+        timelineGroups.push({group: "cluster-version", data: []})
+        createTimelineData("ClusterVersionUpdate", timelineGroups[timelineGroups.length - 1].data, eventIntervals, isClusterVersionUpdate, regex)
+
+        timelineGroups.push({group: "machine-config-pools", data: []})
+        createTimelineData(reasonValue, timelineGroups[timelineGroups.length - 1].data, eventIntervals, isMachineConfigPool, regex)
+
+        timelineGroups.push({group: "lease-holders", data: []})
+        createTimelineData("LeaseHolder", timelineGroups[timelineGroups.length - 1].data, eventIntervals, isLeaseHolder, regex)
+
+        timelineGroups.push({group: "node-links", data: []})
+        createTimelineData("LinkDown", timelineGroups[timelineGroups.length - 1].data, eventIntervals, isNodeLink, regex)
+
+        timelineGroups.push({group: "node-units", data: []})
+        createTimelineData(reasonValue, timelineGroups[timelineGroups.length - 1].data, eventIntervals, isNodeUnit, regex)
+
         timelineGroups.push({group: "endpoint-availability", data: []})
         createTimelineData("Failed", timelineGroups[timelineGroups.length - 1].data, eventIntervals, isEndpointConnectivity, regex)
 
+        timelineGroups.push({group: "e2e-test-namespaces", data: []})
+        createTimelineData("E2ETestNamespace", timelineGroups[timelineGroups.length - 1].data, eventIntervals, isE2ETestNamespace, regex)
+
         timelineGroups.push({group: "e2e-test-failed", data: []})
         createTimelineData("Failed", timelineGroups[timelineGroups.length - 1].data, eventIntervals, isE2EFailed, regex)
 
@@ -52839,26 +53034,28 @@ var _e2echartE2eChartTemplateHtml = []byte(`<html lang="en">
         timelineGroups.push({group: "e2e-test-passed", data: []})
         createTimelineData("Passed", timelineGroups[timelineGroups.length - 1].data, eventIntervals, isE2EPassed, regex)
 
+        // show the full message and the raw interval of the clicked segment
         var segmentFunc = function (segment) {
-            // for (var i in data) {
-            //     if (data[i].group == segment.group) {
-            //         var groupdata = data[i].data
-            //         for (var j in groupdata) {
-            //             if (groupdata[j].label == segment.label) {
-            //                 labeldata = groupdata[j].data
-            //                 for (var k in labeldata) {
-            //                     var startDate = new Date(labeldata[k].timeRange[0])
-            //                     var endDate = new Date(labeldata[k].timeRange[1])
-            //                     if (startDate.getTime() == segment.timeRange[0].getTime() &&
-            //                         endDate.getTime() == segment.timeRange[1].getTime()) {
-            //                         $('#myModalContent').text(labeldata[k].extended)
-            //                         $('#myModal').modal()
-            //                     }
-            //                 }
-            //             }
-            //         }
-            //     }
-            // }
+            for (const timelineGroup of timelineGroups) {
+                if (timelineGroup.group != segment.group) {
+                    continue
+                }
+                for (const row of timelineGroup.data) {
+                    if (row.label != segment.label) {
+                        continue
+                    }
+                    for (const curr of row.data) {
+                        if (curr.timeRange[0].getTime() == segment.timeRange[0].getTime() &&
+                            curr.timeRange[1].getTime() == segment.timeRange[1].getTime()) {
+                            $('#myModalTitle').text(curr.item.locator)
+                            $('#myModalMessage').text(curr.item.message)
+                            $('#myModalContent').text(JSON.stringify(curr.item, null, 2))
+                            $('#myModal').modal()
+                            return
+                        }
+                    }
+                }
+            }
         }
 
         const el = document.querySelector('#chart');
@@ -52866,17 +53063,23 @@ var _e2echartE2eChartTemplateHtml = []byte(`<html lang="en">
         var ordinalScale = d3.scaleOrdinal()
             .domain([
                 'AlertInfo', 'AlertPending', 'AlertWarning', 'AlertCritical', // alerts
+                'MetricThresholdExceeded', // metrics
                 'OperatorUnavailable', 'OperatorDegraded', 'OperatorProgressing', // operators
-                'Update', 'Drain', 'Reboot', 'OperatingSystemUpdate', 'NodeNotReady', // nodes
-                'Passed', 'Skipped', 'Flaked', 'Failed',  // tests
-                'PodCreated', 'PodScheduled', 'PodTerminating','ContainerWait', 'ContainerStart', 'ContainerNotReady', 'ContainerReady', 'ContainerReadinessFailed', 'ContainerReadinessErrored',  // pods
+                'Update', 'Drain', 'Reboot', 'OperatingSystemUpdate', 'NodeNotReady', 'LinkDown', 'UnitFailed', 'UnitRestarted', 'Applying', 'Draining', 'Rebooting', 'NodeResourcePressure', // nodes
+                'ClusterVersionUpdate', 'MachineConfigPoolUpdating', 'MachineConfigPoolDegraded', // updates
+                'LeaseHolder', // leases
+                'Passed', 'Skipped', 'Flaked', 'Failed', 'E2ETestNamespace', // tests
+                'PodCreated', 'PodScheduled', 'PodTerminating','ContainerWait', 'ContainerStart', 'ContainerNotReady', 'ContainerReady', 'ContainerReadinessFailed', 'ContainerReadinessErrored', 'SandboxCreated', 'SandboxDestroyed', 'LeaderElected', 'LeaderChanged', 'LeaderMissing', 'GracefulShutdown',  // pods
                 'Degraded', 'Upgradeable', 'False', 'Unknown'])
             .range([
                 '#fada5e','#fada5e','#ffa500', '#d0312d',  // alerts
+                '#ff8c69', // metrics
                 '#d0312d', '#ffa500', '#fada5e', // operators
-                '#1e7bd9', '#4294e6', '#6aaef2', '#96cbff', '#fada5e', // nodes
-                '#3cb043', '#ceba76', '#ffa500', '#d0312d', // tests
-                '#96cbff', '#1e7bd9', '#ffa500', '#ca8dfd', '#9300ff', '#fada5e','#3cb043', '#d0312d', '#d0312d', // pods
+                '#1e7bd9', '#4294e6', '#6aaef2', '#96cbff', '#fada5e', '#d0312d', '#d0312d', '#ffa500', '#96cbff', '#4294e6', '#6aaef2', '#ca8dfd', // nodes
+                '#1e7bd9', '#96cbff', '#d0312d', // updates
+                '#3cb043', // leases
+                '#3cb043', '#ceba76', '#ffa500', '#d0312d', '#96cbff', // tests
+                '#96cbff', '#1e7bd9', '#ffa500', '#ca8dfd', '#9300ff', '#fada5e','#3cb043', '#d0312d', '#d0312d', '#96cbff', '#6aaef2', '#3cb043', '#ffa500', '#d0312d', '#ca8dfd', // pods
                 '#b65049', '#32b8b6', '#ffffff', '#bbbbbb']);
         myChart.
         data(timelineGroups).